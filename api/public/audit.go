@@ -0,0 +1,106 @@
+package public
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetReelAuditLog lists every recorded change to a reel, oldest first
+func (p *PublicHandler) GetReelAuditLog(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	entries, err := p.Opts.AuditRepo.GetReelAuditEntries(r.Context(), reel.UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	if entries == nil {
+		entries = []datastore.AuditEntry{}
+	}
+
+	respondOK(w, "audit log fetched successfully", entries)
+}
+
+var auditExportHeader = []string{"created_at", "action", "actor_id", "request_id", "changes"}
+
+// ExportReelAuditLog downloads a reel's audit log as csv, the format support works with
+func (p *PublicHandler) ExportReelAuditLog(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	entries, err := p.Opts.AuditRepo.GetReelAuditEntries(r.Context(), reel.UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	p.writeAuditExport(w, r, reel.UID, entries)
+}
+
+// ExportSupportReelAuditLog downloads any reel's audit log for support. Entries outlive
+// their reel, so it works for reels that have since been purged too
+func (p *PublicHandler) ExportSupportReelAuditLog(w http.ResponseWriter, r *http.Request) {
+	reelID := chi.URLParam(r, "reelID")
+
+	entries, err := p.Opts.AuditRepo.GetReelAuditEntries(r.Context(), reelID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	// every reel has at least its creation recorded
+	if len(entries) == 0 {
+		respondError(w, http.StatusNotFound, datastore.ErrReelNotFound.Error())
+		return
+	}
+
+	p.writeAuditExport(w, r, reelID, entries)
+}
+
+func (p *PublicHandler) writeAuditExport(w http.ResponseWriter, r *http.Request, reelID string, entries []datastore.AuditEntry) {
+	records := [][]string{auditExportHeader}
+	for _, entry := range entries {
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			p.respondInternalError(w, r, err)
+			return
+		}
+
+		records = append(records, []string{
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			string(entry.Action),
+			entry.ActorID.String,
+			entry.RequestID.String,
+			string(changes),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"reel-%s-audit.csv\"", reelID))
+	w.WriteHeader(http.StatusOK)
+
+	_ = csv.NewWriter(w).WriteAll(records)
+}
@@ -0,0 +1,68 @@
+package public
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSupportReelAuditLog(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	owner := datastoretest.GenerateUser()
+	require.NoError(t, memory.NewUserRepo(store).CreateUser(ctx, owner))
+
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(ctx, video))
+
+	reel := datastoretest.GenerateReel(video.UID, owner.UID)
+	require.NoError(t, memory.NewReelRepo(store).CreateReel(ctx, reel))
+	require.NoError(t, memory.NewReelRepo(store).DeleteReel(ctx, reel.UID))
+
+	// support can still export the log once the reel is purged
+	_, _, err := memory.NewReelRepo(store).PurgeDeletedReels(ctx, time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+
+	handler := (&PublicHandler{Opts: types.APIOptions{
+		SupportToken: "support-token",
+		AuditRepo:    memory.NewAuditRepo(store),
+	}}).BuildRoutes()
+
+	request := func(reelID string, authorization string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/support/reels/"+reelID+"/audit/export", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusUnauthorized, request(reel.UID, "").Code)
+	require.Equal(t, http.StatusUnauthorized, request(reel.UID, "Bearer wrong-token").Code)
+	require.Equal(t, http.StatusNotFound, request(datastoretest.GenerateReel(video.UID, "").UID, "Bearer support-token").Code)
+
+	w := request(reel.UID, "Bearer support-token")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, string(datastore.ReelCreatedAction), records[1][1])
+	require.Equal(t, string(datastore.ReelPurgedAction), records[3][1])
+
+	// the support api is off without a token
+	handler = (&PublicHandler{Opts: types.APIOptions{AuditRepo: memory.NewAuditRepo(store)}}).BuildRoutes()
+	require.Equal(t, http.StatusUnauthorized, request(reel.UID, "Bearer ").Code)
+}
@@ -0,0 +1,262 @@
+package public
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+const maxBulkItems = 100
+
+type bulkItemResult struct {
+	ID     string `json:"id"`
+	Status bool   `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkItemErrors are the per item failures whose messages are safe to show
+var bulkItemErrors = []error{
+	datastore.ErrReelNotFound,
+	datastore.ErrReelNotDeleted,
+	datastore.ErrReelRecipientsNotAdded,
+	datastore.ErrDuplicate,
+	datastore.ErrReferenceNotFound,
+	datastore.ErrConstraintViolation,
+}
+
+func (p *PublicHandler) bulkResult(r *http.Request, id string, err error) bulkItemResult {
+	if err == nil {
+		return bulkItemResult{ID: id, Status: true}
+	}
+
+	for _, known := range bulkItemErrors {
+		if errors.Is(err, known) {
+			return bulkItemResult{ID: id, Error: err.Error()}
+		}
+	}
+
+	p.Opts.Logger.ErrorContext(r.Context(), "bulk item failed", "error", err, "id", id, "path", r.URL.Path)
+	return bulkItemResult{ID: id, Error: "something went wrong"}
+}
+
+type bulkCreateReelsRequest struct {
+	Reels []createReelRequest `json:"reels"`
+}
+
+type createReelRequest struct {
+	VideoID     string `json:"video_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+	// Public opts the reel into the public gallery once it's delivered, it can't be given later
+	Public       bool      `json:"public"`
+	DeliveryDate time.Time `json:"delivery_date"`
+	Recipients   []string  `json:"recipients"`
+}
+
+// BulkCreateReels creates a reel for each item, like a teacher making the same capsule for
+// a whole class. Items that fail don't stop the others from being created
+func (p *PublicHandler) BulkCreateReels(w http.ResponseWriter, r *http.Request) {
+	var body bulkCreateReelsRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(body.Reels) == 0 || len(body.Reels) > maxBulkItems {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("between 1 and %d reels can be created at once", maxBulkItems))
+		return
+	}
+
+	user := getAuthUser(r)
+
+	reels := make([]*datastore.Reel, len(body.Reels))
+	for i, item := range body.Reels {
+		if strings.TrimSpace(item.Title) == "" || item.VideoID == "" {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("reel %d: title and video id are required", i))
+			return
+		}
+
+		if !item.DeliveryDate.After(time.Now()) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("reel %d: delivery date must be in the future", i))
+			return
+		}
+
+		if item.Public && item.Private {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("reel %d: a private reel can't be shared publicly", i))
+			return
+		}
+
+		recipients, err := newRecipients(item.Recipients)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("reel %d: %s", i, err.Error()))
+			return
+		}
+
+		// reels created by a signed in user don't need their email confirmed
+		reels[i] = &datastore.Reel{
+			UID:            ulid.Make().String(),
+			UserID:         null.StringFrom(user.UID),
+			VideoID:        item.VideoID,
+			Email:          user.Email,
+			Title:          strings.TrimSpace(item.Title),
+			Description:    item.Description,
+			Private:        item.Private,
+			Recipients:     recipients,
+			DeliveryStatus: datastore.ScheduledReelStatus,
+			DeliveryDate:   item.DeliveryDate.UTC(),
+		}
+
+		if item.Public {
+			reels[i].PublicOptInAt = null.TimeFrom(time.Now())
+		}
+	}
+
+	errs, err := p.Opts.ReelRepo.CreateReels(r.Context(), reels)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	results := make([]bulkItemResult, len(reels))
+	for i := range reels {
+		results[i] = p.bulkResult(r, reels[i].UID, errs[i])
+	}
+
+	respondOK(w, "bulk create processed", results)
+}
+
+type bulkReelIDsRequest struct {
+	ReelIDs []string `json:"reel_ids"`
+}
+
+func (p *PublicHandler) BulkDeleteReels(w http.ResponseWriter, r *http.Request) {
+	var body bulkReelIDsRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(body.ReelIDs) == 0 || len(body.ReelIDs) > maxBulkItems {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("between 1 and %d reels can be deleted at once", maxBulkItems))
+		return
+	}
+
+	results := make([]bulkItemResult, len(body.ReelIDs))
+
+	// only reels the user owns are sent on to be deleted
+	var owned []string
+	var ownedIndexes []int
+	for i, reelID := range body.ReelIDs {
+		if _, err := p.getUserReel(r, reelID); err != nil {
+			results[i] = p.bulkResult(r, reelID, err)
+			continue
+		}
+
+		owned = append(owned, reelID)
+		ownedIndexes = append(ownedIndexes, i)
+	}
+
+	errs, err := p.Opts.ReelRepo.DeleteReels(r.Context(), owned)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	for j, i := range ownedIndexes {
+		results[i] = p.bulkResult(r, owned[j], errs[j])
+	}
+
+	respondOK(w, "bulk delete processed", results)
+}
+
+type bulkAddRecipientsRequest struct {
+	ReelIDs []string `json:"reel_ids"`
+	Emails  []string `json:"emails"`
+}
+
+// BulkAddRecipients adds the same list of recipients to several reels
+func (p *PublicHandler) BulkAddRecipients(w http.ResponseWriter, r *http.Request) {
+	var body bulkAddRecipientsRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(body.ReelIDs) == 0 || len(body.ReelIDs) > maxBulkItems {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("between 1 and %d reels can be updated at once", maxBulkItems))
+		return
+	}
+
+	if len(body.Emails) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one recipient email is required")
+		return
+	}
+
+	if _, err := newRecipients(body.Emails); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := make([]bulkItemResult, len(body.ReelIDs))
+
+	var reels []*datastore.Reel
+	var recipients []datastore.Recipients
+	var indexes []int
+	for i, reelID := range body.ReelIDs {
+		reel, err := p.getUserReel(r, reelID)
+		if err != nil {
+			results[i] = p.bulkResult(r, reelID, err)
+			continue
+		}
+
+		// recipients are rows of their own, every reel needs new ids
+		reelRecipients, _ := newRecipients(body.Emails)
+
+		reels = append(reels, reel)
+		recipients = append(recipients, reelRecipients)
+		indexes = append(indexes, i)
+	}
+
+	errs, err := p.Opts.ReelRepo.AddRecipientsToReels(r.Context(), reels, recipients)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	for j, i := range indexes {
+		results[i] = p.bulkResult(r, reels[j].UID, errs[j])
+	}
+
+	respondOK(w, "bulk add recipients processed", results)
+}
+
+// newRecipients validates emails and builds recipients for them
+func newRecipients(emails []string) (datastore.Recipients, error) {
+	seen := map[string]bool{}
+
+	var recipients datastore.Recipients
+	for _, email := range emails {
+		address, err := mail.ParseAddress(email)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid email", email)
+		}
+
+		normalized := strings.ToLower(address.Address)
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		recipients = append(recipients, datastore.Recipient{UID: ulid.Make().String(), Email: normalized})
+	}
+
+	return recipients, nil
+}
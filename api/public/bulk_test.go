@@ -0,0 +1,18 @@
+package public
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecipients(t *testing.T) {
+	recipients, err := newRecipients([]string{"Ada <Ada@Example.com>", "ada@example.com", "grace@example.com"})
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+	require.Equal(t, "ada@example.com", recipients[0].Email)
+	require.NotEqual(t, recipients[0].UID, recipients[1].UID)
+
+	_, err = newRecipients([]string{"not an email"})
+	require.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayo-awe/memoreel-be/calendar"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetCalendarFeed serves the iCalendar feed a user subscribes to from their calendar app
+func (p *PublicHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := p.Opts.Calendar.Feed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCalendarToken) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", calendar.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(feed)
+}
+
+type calendarFeedResponse struct {
+	FeedURL string `json:"feed_url"`
+}
+
+// RotateCalendarToken issues a new calendar feed url, the previous one stops working
+func (p *PublicHandler) RotateCalendarToken(w http.ResponseWriter, r *http.Request) {
+	feedURL, err := p.Opts.Calendar.RotateToken(r.Context(), getAuthUser(r))
+	if err != nil {
+		if errors.Is(err, datastore.ErrVersionConflict) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "calendar feed url created successfully", calendarFeedResponse{FeedURL: feedURL})
+}
@@ -0,0 +1,40 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/calendar"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCalendarFeed(t *testing.T) {
+	store := memory.NewStore()
+	service := &services.CalendarService{
+		UserRepo: memory.NewUserRepo(store),
+		ReelRepo: memory.NewReelRepo(store),
+	}
+
+	user := datastoretest.GenerateUser()
+	require.NoError(t, service.UserRepo.CreateUser(context.Background(), user))
+	_, err := service.RotateToken(context.Background(), user)
+	require.NoError(t, err)
+
+	handler := (&PublicHandler{Opts: types.APIOptions{Calendar: service}}).BuildRoutes()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/calendar/"+user.CalendarToken.String+".ics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, calendar.ContentType, w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), "BEGIN:VCALENDAR")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/calendar/unknown.ics", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,287 @@
+package public
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/mail"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/guregu/null.v4"
+)
+
+type contributionDeadlineRequest struct {
+	// Deadline closes the reel to contributions when it's null
+	Deadline null.Time `json:"deadline"`
+}
+
+// SetContributionDeadline opens a reel to contributors until the deadline
+func (p *PublicHandler) SetContributionDeadline(w http.ResponseWriter, r *http.Request) {
+	var body contributionDeadlineRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	if err := p.Opts.Contributions.SetDeadline(r.Context(), reel, body.Deadline); err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	setETag(w, reel.Version)
+	respondOK(w, "contribution deadline updated", reel)
+}
+
+type inviteContributorRequest struct {
+	Email string `json:"email"`
+}
+
+func (p *PublicHandler) InviteContributor(w http.ResponseWriter, r *http.Request) {
+	var body inviteContributorRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	address, err := mail.ParseAddress(body.Email)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "a valid email is required")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	contributor, err := p.Opts.Contributions.Invite(r.Context(), reel, address.Address)
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contributor invited", contributor)
+}
+
+func (p *PublicHandler) GetContributors(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	contributors, err := p.Opts.Contributions.ContributionRepo.GetContributorsByReel(r.Context(), reel.UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contributors fetched successfully", contributors)
+}
+
+// RemoveContributor withdraws a contributor's invite, only the clips that were approved stay
+func (p *PublicHandler) RemoveContributor(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	err = p.Opts.Contributions.RemoveContributor(r.Context(), reel, chi.URLParam(r, "contributorID"))
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contributor removed", nil)
+}
+
+func (p *PublicHandler) GetContributions(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	contributions, err := p.Opts.Contributions.Contributions(r.Context(), reel)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contributions fetched successfully", contributions)
+}
+
+type reorderContributionsRequest struct {
+	ContributionIDs []string `json:"contribution_ids"`
+}
+
+func (p *PublicHandler) ReorderContributions(w http.ResponseWriter, r *http.Request) {
+	var body reorderContributionsRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	if err := p.Opts.Contributions.Reorder(r.Context(), reel, body.ContributionIDs); err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contributions reordered", nil)
+}
+
+type contributionStatusRequest struct {
+	Status datastore.ContributionStatus `json:"status"`
+}
+
+// SetContributionStatus approves or rejects a contribution, only approved ones are delivered
+func (p *PublicHandler) SetContributionStatus(w http.ResponseWriter, r *http.Request) {
+	var body contributionStatusRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	err = p.Opts.Contributions.SetStatus(r.Context(), reel, chi.URLParam(r, "contributionID"), body.Status)
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contribution updated", nil)
+}
+
+func (p *PublicHandler) RemoveContribution(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	if err := p.Opts.Contributions.Remove(r.Context(), reel, chi.URLParam(r, "contributionID")); err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "contribution removed", nil)
+}
+
+// GetContributorView shows an invited contributor the reel and the clips they've added to it
+func (p *PublicHandler) GetContributorView(w http.ResponseWriter, r *http.Request) {
+	contributing, err := p.Opts.Contributions.ContributorFromLink(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	view, err := p.Opts.Contributions.View(r.Context(), contributing)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "reel fetched successfully", view)
+}
+
+// Contribute takes a clip as the raw request body, its content type gives the format
+func (p *PublicHandler) Contribute(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	fileFormat, ok := uploadFormats[mediaType]
+	if !ok {
+		respondError(w, http.StatusUnsupportedMediaType, services.ErrUnsupportedVideo.Error())
+		return
+	}
+
+	contributing, err := p.Opts.Contributions.ContributorFromLink(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	contribution, err := p.Opts.Contributions.Contribute(r.Context(), contributing, r.Body, fileFormat)
+	if err != nil {
+		p.respondContributionError(w, r, err)
+		return
+	}
+
+	respondOK(w, "clip added", contribution)
+}
+
+func (p *PublicHandler) respondContributionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidContributeLink),
+		errors.Is(err, datastore.ErrContributorNotFound),
+		errors.Is(err, datastore.ErrContributionNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrContributionsClosed),
+		errors.Is(err, services.ErrContributionsLocked),
+		errors.Is(err, datastore.ErrDuplicateContributor),
+		errors.Is(err, datastore.ErrContributionLimitReached),
+		errors.Is(err, datastore.ErrVersionConflict):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidContributionDeadline),
+		errors.Is(err, services.ErrInvalidContributionStatus),
+		errors.Is(err, datastore.ErrInvalidContributionOrder):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrUnsupportedVideo):
+		respondError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, services.ErrVideoTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		p.respondInternalError(w, r, err)
+	}
+}
@@ -0,0 +1,98 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupReelContributions(t *testing.T) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	ctx := context.Background()
+
+	owner := datastoretest.SeedUser(t, repos)
+	reel := datastoretest.SeedReel(t, repos, owner.UID, datastore.ScheduledReelStatus)
+
+	service := &services.ContributionService{
+		UnitOfWork:       memory.NewUnitOfWork(store),
+		ContributionRepo: repos.Contributions,
+		ReelRepo:         repos.Reels,
+		VideoRepo:        repos.Videos,
+		Signer:           token.NewSigner("secret"),
+		Storage:          storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()}),
+		MaxVideoBytes:    1024,
+	}
+
+	handler := (&PublicHandler{Opts: types.APIOptions{
+		Authenticator: staticAuthenticator{user: owner},
+		ReelRepo:      repos.Reels,
+		Contributions: service,
+	}}).BuildRoutes()
+
+	request := func(method string, path string, contentType string, body string) int {
+		r := httptest.NewRequest(method, "/v1"+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	reelPath := "/reels/" + reel.UID
+
+	// contributors can only be invited while the reel is open to them
+	require.Equal(t, http.StatusConflict, request(http.MethodPost, reelPath+"/contributors", "application/json", `{"email":"friend@gmail.com"}`))
+
+	deadline := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	require.Equal(t, http.StatusOK, request(http.MethodPut, reelPath+"/contribution-deadline", "application/json", fmt.Sprintf(`{"deadline":%q}`, deadline)))
+
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPost, reelPath+"/contributors", "application/json", `{"email":"not an email"}`))
+	require.Equal(t, http.StatusOK, request(http.MethodPost, reelPath+"/contributors", "application/json", `{"email":"friend@gmail.com"}`))
+	require.Equal(t, http.StatusConflict, request(http.MethodPost, reelPath+"/contributors", "application/json", `{"email":"FRIEND@gmail.com"}`))
+
+	contributors, err := service.ContributionRepo.GetContributorsByReel(ctx, reel.UID)
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+
+	signed, err := service.Signer.Sign(token.Claims{Subject: contributors[0].UID, Audience: "reel_contribution"})
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/contribute/"+signed, "", ""))
+	require.Equal(t, http.StatusNotFound, request(http.MethodGet, "/contribute/nope", "", ""))
+	require.Equal(t, http.StatusUnsupportedMediaType, request(http.MethodPost, "/contribute/"+signed+"/videos", "text/plain", "clip"))
+	require.Equal(t, http.StatusRequestEntityTooLarge, request(http.MethodPost, "/contribute/"+signed+"/videos", "video/mp4", strings.Repeat("a", 2048)))
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/contribute/"+signed+"/videos", "video/mp4", "first"))
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/contribute/"+signed+"/videos", "video/webm", "second"))
+
+	contributions, err := service.Contributions(ctx, reel)
+	require.NoError(t, err)
+	require.Len(t, contributions, 2)
+	first, second := contributions[0].UID, contributions[1].UID
+
+	require.Equal(t, http.StatusOK, request(http.MethodGet, reelPath+"/contributions", "", ""))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPut, reelPath+"/contributions/"+first+"/status", "application/json", `{"status":"pending"}`))
+	require.Equal(t, http.StatusOK, request(http.MethodPut, reelPath+"/contributions/"+first+"/status", "application/json", `{"status":"approved"}`))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPut, reelPath+"/contributions/order", "application/json", fmt.Sprintf(`{"contribution_ids":[%q]}`, first)))
+	require.Equal(t, http.StatusOK, request(http.MethodPut, reelPath+"/contributions/order", "application/json", fmt.Sprintf(`{"contribution_ids":[%q,%q]}`, second, first)))
+	require.Equal(t, http.StatusOK, request(http.MethodDelete, reelPath+"/contributions/"+second, "", ""))
+	require.Equal(t, http.StatusNotFound, request(http.MethodDelete, reelPath+"/contributions/"+second, "", ""))
+
+	// a removed contributor's link stops working
+	require.Equal(t, http.StatusOK, request(http.MethodDelete, reelPath+"/contributors/"+contributors[0].UID, "", ""))
+	require.Equal(t, http.StatusNotFound, request(http.MethodPost, "/contribute/"+signed+"/videos", "video/mp4", "late"))
+}
@@ -0,0 +1,65 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMissingIfMatch = errors.New("an If-Match header is required to update this resource")
+	errInvalidIfMatch = errors.New("the If-Match header is not a valid etag")
+	errStaleIfMatch   = errors.New("the resource has been modified since it was fetched")
+)
+
+// setETag exposes a record's version so clients can send it back in If-Match when updating it
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version in the request's If-Match header, or current for "*"
+func ifMatchVersion(r *http.Request, current int) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, errMissingIfMatch
+	}
+
+	if header == "*" {
+		return current, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.Atoi(tag)
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// checkIfMatch makes sure an update is based on the current version of a record, writing
+// the matching error response when it isn't
+func checkIfMatch(w http.ResponseWriter, r *http.Request, current int) (int, bool) {
+	version, err := ifMatchVersion(r, current)
+	if err != nil {
+		switch {
+		case errors.Is(err, errMissingIfMatch):
+			respondError(w, http.StatusPreconditionRequired, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
+		return 0, false
+	}
+
+	if version != current {
+		respondError(w, http.StatusPreconditionFailed, errStaleIfMatch.Error())
+		return 0, false
+	}
+
+	return version, true
+}
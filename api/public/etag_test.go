@@ -0,0 +1,44 @@
+package public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		ok      bool
+	}{
+		{name: "missing", ifMatch: "", status: http.StatusPreconditionRequired},
+		{name: "malformed", ifMatch: "3", status: http.StatusBadRequest},
+		{name: "stale", ifMatch: `"2"`, status: http.StatusPreconditionFailed},
+		{name: "current", ifMatch: `"3"`, ok: true},
+		{name: "weak", ifMatch: `W/"3"`, ok: true},
+		{name: "any", ifMatch: "*", ok: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			version, ok := checkIfMatch(w, r, 3)
+			require.Equal(t, tc.ok, ok)
+
+			if tc.ok {
+				require.Equal(t, 3, version)
+				return
+			}
+			require.Equal(t, tc.status, w.Code)
+		})
+	}
+}
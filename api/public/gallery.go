@@ -0,0 +1,44 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetGallery lists delivered reels their senders chose to share publicly, latest first
+func (p *PublicHandler) GetGallery(w http.ResponseWriter, r *http.Request) {
+	pageable, err := readPageable(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, pagination, err := p.Opts.GalleryRepo.GetGalleryPaged(r.Context(), pageable)
+	if err != nil {
+		if errors.Is(err, datastore.ErrInvalidSort) || errors.Is(err, datastore.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "gallery fetched successfully", newPagedResponse(items, pagination))
+}
+
+func (p *PublicHandler) GetGalleryReel(w http.ResponseWriter, r *http.Request) {
+	view, err := p.Opts.Gallery.Get(r.Context(), chi.URLParam(r, "reelID"))
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "reel fetched successfully", view)
+}
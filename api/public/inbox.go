@@ -0,0 +1,83 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5"
+)
+
+var errEmailNotVerified = errors.New("verify your email to see reels sent to you")
+
+// GetInbox lists reels delivered to the authenticated user's email, latest first. Hidden
+// reels are left out unless hidden=true, which lists only them
+func (p *PublicHandler) GetInbox(w http.ResponseWriter, r *http.Request) {
+	user := getAuthUser(r)
+
+	// anyone can sign up with any email, only a verified one proves the reels are theirs
+	if !user.EmailVerified {
+		respondError(w, http.StatusForbidden, errEmailNotVerified.Error())
+		return
+	}
+
+	pageable, err := readPageable(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var filter datastore.InboxFilter
+	if value := r.URL.Query().Get("hidden"); value != "" {
+		filter.Hidden, err = strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "hidden must be true or false")
+			return
+		}
+	}
+
+	items, pagination, err := p.Opts.InboxRepo.GetInboxPaged(r.Context(), user.UID, user.Email, filter, pageable)
+	if err != nil {
+		if errors.Is(err, datastore.ErrInvalidSort) || errors.Is(err, datastore.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "inbox fetched successfully", newPagedResponse(items, pagination))
+}
+
+func (p *PublicHandler) HideInboxItem(w http.ResponseWriter, r *http.Request) {
+	p.setInboxItemHidden(w, r, true)
+}
+
+func (p *PublicHandler) UnhideInboxItem(w http.ResponseWriter, r *http.Request) {
+	p.setInboxItemHidden(w, r, false)
+}
+
+func (p *PublicHandler) setInboxItemHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	user := getAuthUser(r)
+	if !user.EmailVerified {
+		respondError(w, http.StatusForbidden, errEmailNotVerified.Error())
+		return
+	}
+
+	err := p.Opts.InboxRepo.SetInboxItemHidden(r.Context(), user.UID, user.Email, chi.URLParam(r, "reelID"), hidden)
+	if err != nil {
+		if errors.Is(err, datastore.ErrInboxItemNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	message := "reel hidden from inbox"
+	if !hidden {
+		message = "reel restored to inbox"
+	}
+	respondOK(w, message, nil)
+}
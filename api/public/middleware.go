@@ -0,0 +1,58 @@
+package public
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type contextKey string
+
+const authUserCtxKey contextKey = "auth_user"
+
+func (p *PublicHandler) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.Opts.Authenticator == nil {
+			respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		user, err := p.Opts.Authenticator.Authenticate(r)
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), authUserCtxKey, user)
+
+		// changes made while handling the request are recorded against the user
+		ctx = datastore.WithAuditor(ctx, datastore.Auditor{
+			ActorID:   user.UID,
+			RequestID: middleware.GetReqID(ctx),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireSupport only lets through requests carrying the support token as a bearer token
+func (p *PublicHandler) requireSupport(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || p.Opts.SupportToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(p.Opts.SupportToken)) != 1 {
+			respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getAuthUser returns the user set by requireAuth
+func getAuthUser(r *http.Request) *datastore.User {
+	return r.Context().Value(authUserCtxKey).(*datastore.User)
+}
@@ -0,0 +1,54 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+var errInvalidPerPage = errors.New("per_page must be a number between 1 and 100")
+
+type pagedResponse[T any] struct {
+	Items      []T                      `json:"items"`
+	Pagination datastore.PaginationData `json:"pagination"`
+}
+
+// readPageable reads the per_page, cursor, sort and order query params. The sort field
+// is checked by the repository since each listing sorts by different fields
+func readPageable(r *http.Request) (datastore.Pageable, error) {
+	query := r.URL.Query()
+	pageable := datastore.Pageable{
+		PerPage: defaultPerPage,
+		Cursor:  query.Get("cursor"),
+		Sort:    datastore.Sort{Field: query.Get("sort"), Order: datastore.SortOrder(query.Get("order"))},
+	}
+
+	if perPage := query.Get("per_page"); perPage != "" {
+		n, err := strconv.Atoi(perPage)
+		if err != nil || n < 1 || n > maxPerPage {
+			return datastore.Pageable{}, errInvalidPerPage
+		}
+		pageable.PerPage = n
+	}
+
+	// catch a bad cursor here rather than letting it surface from the repository
+	if _, err := datastore.DecodeCursor(pageable.Cursor); err != nil {
+		return datastore.Pageable{}, err
+	}
+
+	return pageable, nil
+}
+
+func newPagedResponse[T any](items []T, pagination datastore.PaginationData) pagedResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return pagedResponse[T]{Items: items, Pagination: pagination}
+}
@@ -0,0 +1,32 @@
+package public
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPageable(t *testing.T) {
+	pageable, err := readPageable(httptest.NewRequest("GET", "/reels", nil))
+	require.NoError(t, err)
+	require.Equal(t, datastore.Pageable{PerPage: defaultPerPage}, pageable)
+
+	cursor := datastore.Cursor{ID: "01HQ", Direction: datastore.PrevPage}.Encode()
+	pageable, err = readPageable(httptest.NewRequest("GET", "/reels?per_page=5&sort=title&order=desc&cursor="+cursor, nil))
+	require.NoError(t, err)
+	require.Equal(t, datastore.Pageable{
+		PerPage: 5,
+		Cursor:  cursor,
+		Sort:    datastore.Sort{Field: "title", Order: datastore.SortDesc},
+	}, pageable)
+
+	for _, query := range []string{"per_page=0", "per_page=101", "per_page=ten"} {
+		_, err = readPageable(httptest.NewRequest("GET", "/reels?"+query, nil))
+		require.ErrorIs(t, err, errInvalidPerPage, query)
+	}
+
+	_, err = readPageable(httptest.NewRequest("GET", "/reels?cursor=garbage", nil))
+	require.ErrorIs(t, err, datastore.ErrInvalidCursor)
+}
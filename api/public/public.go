@@ -17,30 +17,104 @@ func (p *PublicHandler) BuildRoutes() http.Handler {
 	v1Router := chi.NewRouter()
 
 	v1Router.Route("/me", func(meRouter chi.Router) {
-		meRouter.Get("/", func(w http.ResponseWriter, r *http.Request) {})
-		meRouter.Patch("/", func(w http.ResponseWriter, r *http.Request) {})
+		meRouter.Use(p.requireAuth)
+		meRouter.Get("/", p.GetMe)
+		meRouter.Patch("/", p.UpdateMe)
+		meRouter.Post("/calendar/token", p.RotateCalendarToken)
 	})
 
 	v1Router.Route("/reels", func(reelRouter chi.Router) {
-		reelRouter.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		reelRouter.Use(p.requireAuth)
+		reelRouter.Get("/", p.GetReels)
 		reelRouter.Post("/", func(w http.ResponseWriter, r *http.Request) {})
 		reelRouter.Post("/confirm", func(w http.ResponseWriter, r *http.Request) {})
+		reelRouter.Get("/trash", p.GetTrash)
+		reelRouter.Get("/stats", p.GetReelStats)
+		reelRouter.Route("/bulk", func(bulkRouter chi.Router) {
+			bulkRouter.Post("/", p.BulkCreateReels)
+			bulkRouter.Post("/delete", p.BulkDeleteReels)
+			bulkRouter.Post("/recipients", p.BulkAddRecipients)
+		})
 		reelRouter.Route("/{reelID}", func(reelSubRouter chi.Router) {
-			reelSubRouter.Get("/", func(w http.ResponseWriter, r *http.Request) {})
-			reelSubRouter.Put("/", func(w http.ResponseWriter, r *http.Request) {})
+			reelSubRouter.Get("/", p.GetReel)
+			reelSubRouter.Put("/", p.UpdateReel)
 			reelSubRouter.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+			reelSubRouter.Post("/restore", p.RestoreReel)
+			reelSubRouter.Get("/audit", p.GetReelAuditLog)
+			reelSubRouter.Get("/audit/export", p.ExportReelAuditLog)
+			reelSubRouter.Put("/reminders", p.UpdateReminderSettings)
 			reelSubRouter.Route("/recipients", func(recipientRouter chi.Router) {
 				recipientRouter.Post("/", func(w http.ResponseWriter, r *http.Request) {})
 				recipientRouter.Delete("/{recipientID}", func(w http.ResponseWriter, r *http.Request) {})
+				recipientRouter.Delete("/{recipientID}/link", p.RevokeRecipientLink)
+			})
+			reelSubRouter.Route("/responses", func(responseRouter chi.Router) {
+				responseRouter.Get("/", p.GetReelResponses)
+				responseRouter.Put("/{responseID}/status", p.SetResponseStatus)
+			})
+			reelSubRouter.Route("/share-links", func(shareRouter chi.Router) {
+				shareRouter.Post("/", p.CreateShareLink)
+				shareRouter.Get("/", p.GetShareLinks)
+				shareRouter.Delete("/{linkID}", p.RevokeShareLink)
+			})
+			reelSubRouter.Put("/contribution-deadline", p.SetContributionDeadline)
+			reelSubRouter.Route("/contributors", func(contributorRouter chi.Router) {
+				contributorRouter.Post("/", p.InviteContributor)
+				contributorRouter.Get("/", p.GetContributors)
+				contributorRouter.Delete("/{contributorID}", p.RemoveContributor)
+			})
+			reelSubRouter.Route("/contributions", func(contributionRouter chi.Router) {
+				contributionRouter.Get("/", p.GetContributions)
+				contributionRouter.Put("/order", p.ReorderContributions)
+				contributionRouter.Put("/{contributionID}/status", p.SetContributionStatus)
+				contributionRouter.Delete("/{contributionID}", p.RemoveContribution)
 			})
 		})
 
 	})
 
+	v1Router.Route("/support", func(supportRouter chi.Router) {
+		supportRouter.Use(p.requireSupport)
+		supportRouter.Get("/reels/{reelID}/audit/export", p.ExportSupportReelAuditLog)
+	})
+
+	v1Router.Route("/inbox", func(inboxRouter chi.Router) {
+		inboxRouter.Use(p.requireAuth)
+		inboxRouter.Get("/", p.GetInbox)
+		inboxRouter.Put("/{reelID}/hidden", p.HideInboxItem)
+		inboxRouter.Delete("/{reelID}/hidden", p.UnhideInboxItem)
+		inboxRouter.Post("/{reelID}/reactions", p.reactAs(p.userResponder))
+		inboxRouter.Post("/{reelID}/notes", p.leaveNoteAs(p.userResponder))
+		inboxRouter.Post("/{reelID}/replies", p.replyAs(p.userResponder))
+	})
+
+	v1Router.Route("/gallery", func(galleryRouter chi.Router) {
+		galleryRouter.Get("/", p.GetGallery)
+		galleryRouter.Get("/{reelID}", p.GetGalleryReel)
+	})
+
 	v1Router.Route("/videos", func(videoRouter chi.Router) {
 		videoRouter.Post("/", func(w http.ResponseWriter, r *http.Request) {})
 	})
 
+	v1Router.Route("/view/{token}", func(viewRouter chi.Router) {
+		viewRouter.Get("/", p.GetRecipientView)
+		viewRouter.Get("/master.m3u8", p.GetRecipientPlaylist)
+		viewRouter.Post("/reactions", p.reactAs(p.linkResponder))
+		viewRouter.Post("/notes", p.leaveNoteAs(p.linkResponder))
+		viewRouter.Post("/replies", p.replyAs(p.linkResponder))
+	})
+
+	v1Router.Get("/share/{token}", p.GetSharedReel)
+	v1Router.Get("/share/stream/{token}/*", p.StreamSharedReel)
+
+	v1Router.Route("/contribute/{token}", func(contributeRouter chi.Router) {
+		contributeRouter.Get("/", p.GetContributorView)
+		contributeRouter.Post("/videos", p.Contribute)
+	})
+
+	v1Router.Get("/calendar/{token}.ics", p.GetCalendarFeed)
+
 	router.Mount("/v1", v1Router)
 
 	p.Router = router
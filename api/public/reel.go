@@ -0,0 +1,269 @@
+package public
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/guregu/null.v4"
+)
+
+// getOwnedReel loads the reel in the url and makes sure it belongs to the authenticated user
+func (p *PublicHandler) getOwnedReel(r *http.Request) (*datastore.Reel, error) {
+	return p.getUserReel(r, chi.URLParam(r, "reelID"))
+}
+
+// getUserReel loads a reel, making sure it belongs to the authenticated user
+func (p *PublicHandler) getUserReel(r *http.Request, reelID string) (*datastore.Reel, error) {
+	user := getAuthUser(r)
+
+	reel, err := p.Opts.ReelRepo.GetReelByID(r.Context(), reelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reel.UserID.String != user.UID {
+		return nil, datastore.ErrReelNotFound
+	}
+
+	return reel, nil
+}
+
+const maxSearchLength = 256
+
+// readReelFilter reads a reel listing's filters from the query. Dates are RFC 3339 timestamps
+// and q is a search over titles and descriptions
+func readReelFilter(r *http.Request) (datastore.ReelFilter, error) {
+	query := r.URL.Query()
+	filter := datastore.ReelFilter{
+		RecipientEmail: strings.TrimSpace(query.Get("recipient")),
+		Query:          strings.TrimSpace(query.Get("q")),
+	}
+
+	if len(filter.Query) > maxSearchLength {
+		return filter, fmt.Errorf("q must be at most %d characters", maxSearchLength)
+	}
+
+	if status := query.Get("delivery_status"); status != "" {
+		filter.DeliveryStatus = datastore.ReelDeliveryStatus(status)
+		if !filter.DeliveryStatus.IsValid() {
+			return filter, errors.New("invalid delivery status")
+		}
+	}
+
+	times := map[string]*null.Time{
+		"delivery_from": &filter.DeliveryFrom,
+		"delivery_to":   &filter.DeliveryTo,
+		"created_from":  &filter.CreatedFrom,
+		"created_to":    &filter.CreatedTo,
+	}
+
+	for param, dst := range times {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an RFC 3339 timestamp", param)
+			}
+			*dst = null.TimeFrom(t)
+		}
+	}
+
+	bools := map[string]*null.Bool{
+		"private":        &filter.Private,
+		"has_recipients": &filter.HasRecipients,
+	}
+
+	for param, dst := range bools {
+		if value := query.Get(param); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be true or false", param)
+			}
+			*dst = null.BoolFrom(b)
+		}
+	}
+
+	return filter, nil
+}
+
+// GetReels lists the authenticated user's reels narrowed by the filters in readReelFilter.
+// They're newest first, or best match first when searching, unless another sort is asked for
+func (p *PublicHandler) GetReels(w http.ResponseWriter, r *http.Request) {
+	pageable, err := readPageable(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := readReelFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reels, pagination, err := p.Opts.ReelRepo.GetReelsPaged(r.Context(), getAuthUser(r).UID, filter, pageable)
+	if err != nil {
+		// a cursor can still be rejected here when it was made for another sort
+		if errors.Is(err, datastore.ErrInvalidSort) || errors.Is(err, datastore.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "reels fetched successfully", newPagedResponse(reels, pagination))
+}
+
+func (p *PublicHandler) GetReel(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	setETag(w, reel.Version)
+	respondOK(w, "reel fetched successfully", reel)
+}
+
+type updateReelRequest struct {
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Private      bool      `json:"private"`
+	DeliveryDate time.Time `json:"delivery_date"`
+}
+
+// UpdateReel replaces a reel's editable details. The If-Match header must carry the
+// version the client last saw so edits from another tab aren't silently overwritten
+func (p *PublicHandler) UpdateReel(w http.ResponseWriter, r *http.Request) {
+	var body updateReelRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, reel.Version)
+	if !ok {
+		return
+	}
+
+	if reel.DeliveryStatus == datastore.DeliveredReelStatus {
+		respondError(w, http.StatusConflict, "a delivered reel can't be changed")
+		return
+	}
+
+	if strings.TrimSpace(body.Title) == "" {
+		respondError(w, http.StatusBadRequest, "title can't be empty")
+		return
+	}
+
+	if !body.DeliveryDate.After(time.Now()) {
+		respondError(w, http.StatusBadRequest, "delivery date must be in the future")
+		return
+	}
+
+	reel.Title = strings.TrimSpace(body.Title)
+	reel.Description = body.Description
+	reel.Private = body.Private
+	reel.DeliveryDate = body.DeliveryDate.UTC()
+	reel.Version = version
+
+	if err := p.Opts.ReelRepo.UpdateReel(r.Context(), reel); err != nil {
+		if errors.Is(err, datastore.ErrVersionConflict) {
+			respondError(w, http.StatusPreconditionFailed, errStaleIfMatch.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	setETag(w, reel.Version)
+	respondOK(w, "reel updated successfully", reel)
+}
+
+func (p *PublicHandler) RevokeRecipientLink(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	err = p.Opts.RecipientLinks.Revoke(r.Context(), reel, chi.URLParam(r, "recipientID"))
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecipientNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "recipient link revoked", nil)
+}
+
+type updateReminderSettingsRequest struct {
+	Suppress bool `json:"suppress"`
+}
+
+// UpdateReminderSettings lets a creator turn pre-delivery reminders off (or back on) for a reel.
+// Like UpdateReel, it needs the reel's current version in If-Match
+func (p *PublicHandler) UpdateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	var body updateReminderSettingsRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, reel.Version)
+	if !ok {
+		return
+	}
+
+	reel.SuppressReminders = body.Suppress
+	reel.Version = version
+
+	if err := p.Opts.ReelRepo.UpdateReel(r.Context(), reel); err != nil {
+		if errors.Is(err, datastore.ErrVersionConflict) {
+			respondError(w, http.StatusPreconditionFailed, errStaleIfMatch.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	setETag(w, reel.Version)
+	respondOK(w, "reminder settings updated", reel)
+}
@@ -0,0 +1,193 @@
+package public
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// uploadFormats maps the content types videos are uploaded with to their file formats
+var uploadFormats = map[string]string{
+	"video/mp4":       "mp4",
+	"video/quicktime": "mov",
+	"video/webm":      "webm",
+}
+
+// responderFunc identifies the recipient responding to a reel, by their signed link or their account
+type responderFunc func(r *http.Request) (*services.Responder, error)
+
+func (p *PublicHandler) linkResponder(r *http.Request) (*services.Responder, error) {
+	return p.Opts.Responses.ResponderFromLink(r.Context(), chi.URLParam(r, "token"))
+}
+
+func (p *PublicHandler) userResponder(r *http.Request) (*services.Responder, error) {
+	return p.Opts.Responses.ResponderFromUser(r.Context(), getAuthUser(r), chi.URLParam(r, "reelID"))
+}
+
+type reactRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+func (p *PublicHandler) reactAs(identify responderFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reactRequest
+		if err := readJSON(r, &body); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		responder, err := identify(r)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		response, err := p.Opts.Responses.React(r.Context(), responder, body.Emoji)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		respondOK(w, "reaction sent", response)
+	}
+}
+
+type leaveNoteRequest struct {
+	Note string `json:"note"`
+}
+
+func (p *PublicHandler) leaveNoteAs(identify responderFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body leaveNoteRequest
+		if err := readJSON(r, &body); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		responder, err := identify(r)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		response, err := p.Opts.Responses.LeaveNote(r.Context(), responder, body.Note)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		respondOK(w, "note sent", response)
+	}
+}
+
+// replyAs takes the reply video as the raw request body, its content type gives the format
+func (p *PublicHandler) replyAs(identify responderFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		fileFormat, ok := uploadFormats[mediaType]
+		if !ok {
+			respondError(w, http.StatusUnsupportedMediaType, services.ErrUnsupportedVideo.Error())
+			return
+		}
+
+		responder, err := identify(r)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		response, err := p.Opts.Responses.ReplyWithVideo(r.Context(), responder, r.Body, fileFormat)
+		if err != nil {
+			p.respondResponseError(w, r, err)
+			return
+		}
+
+		respondOK(w, "reply video sent", response)
+	}
+}
+
+// GetReelResponses lists the responses recipients sent back to the sender, the approved ones
+// unless the status query parameter asks for those pending review or rejected
+func (p *PublicHandler) GetReelResponses(w http.ResponseWriter, r *http.Request) {
+	status := datastore.ApprovedModeration
+	if value := r.URL.Query().Get("status"); value != "" {
+		status = datastore.ModerationStatus(value)
+		if !status.IsValid() {
+			respondError(w, http.StatusBadRequest, "status must be pending, approved or rejected")
+			return
+		}
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	responses, err := p.Opts.Responses.Responses(r.Context(), reel, status)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "responses fetched successfully", responses)
+}
+
+type responseStatusRequest struct {
+	Status datastore.ModerationStatus `json:"status"`
+}
+
+// SetResponseStatus approves or rejects a response the moderator left pending, approving it
+// notifies the sender like any other response
+func (p *PublicHandler) SetResponseStatus(w http.ResponseWriter, r *http.Request) {
+	var body responseStatusRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	err = p.Opts.Responses.Review(r.Context(), reel, chi.URLParam(r, "responseID"), body.Status)
+	if err != nil {
+		p.respondResponseError(w, r, err)
+		return
+	}
+
+	respondOK(w, "response updated", nil)
+}
+
+func (p *PublicHandler) respondResponseError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidViewLink):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrResponseNotAllowed):
+		respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, datastore.ErrResponseNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidReaction), errors.Is(err, services.ErrInvalidNote), errors.Is(err, services.ErrInvalidModeration):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrUnsupportedVideo):
+		respondError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, services.ErrVideoTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		p.respondInternalError(w, r, err)
+	}
+}
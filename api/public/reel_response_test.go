@@ -0,0 +1,131 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/stretchr/testify/require"
+)
+
+type staticAuthenticator struct {
+	user *datastore.User
+}
+
+func (a staticAuthenticator) Authenticate(r *http.Request) (*datastore.User, error) {
+	return a.user, nil
+}
+
+func TestRespondFromInbox(t *testing.T) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	ctx := context.Background()
+
+	reel := datastoretest.SeedReel(t, repos, datastoretest.SeedUser(t, repos).UID, datastore.DeliveredReelStatus)
+
+	recipient := datastoretest.GenerateUser()
+	recipient.Email = reel.Recipients[0].Email
+	recipient.EmailVerified = true
+
+	service := &services.ResponseService{
+		UnitOfWork:    memory.NewUnitOfWork(store),
+		ResponseRepo:  repos.Responses,
+		ReelRepo:      repos.Reels,
+		VideoRepo:     repos.Videos,
+		Storage:       storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()}),
+		Moderator:     services.ApproveAll{},
+		MaxVideoBytes: 1024,
+	}
+
+	handler := (&PublicHandler{Opts: types.APIOptions{
+		Authenticator: staticAuthenticator{user: recipient},
+		Responses:     service,
+	}}).BuildRoutes()
+
+	request := func(path string, contentType string, body string) int {
+		r := httptest.NewRequest(http.MethodPost, "/v1/inbox/"+reel.UID+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, request("/reactions", "application/json", `{"emoji":"🎉"}`))
+	require.Equal(t, http.StatusBadRequest, request("/reactions", "application/json", `{"emoji":"yay"}`))
+	require.Equal(t, http.StatusOK, request("/notes", "application/json", `{"note":"Thank you!"}`))
+	require.Equal(t, http.StatusOK, request("/replies", "video/mp4", "video"))
+	require.Equal(t, http.StatusUnsupportedMediaType, request("/replies", "text/plain", "video"))
+	require.Equal(t, http.StatusRequestEntityTooLarge, request("/replies", "video/webm", strings.Repeat("a", 2048)))
+
+	responses, err := service.Responses(ctx, reel, datastore.ApprovedModeration)
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+
+	recipient.Email = "stranger@example.com"
+	require.Equal(t, http.StatusForbidden, request("/reactions", "application/json", `{"emoji":"🎉"}`))
+}
+
+// holdAll leaves every response pending for the sender to review
+type holdAll struct{}
+
+func (holdAll) Moderate(ctx context.Context, response *datastore.ReelResponse) (datastore.ModerationStatus, error) {
+	return datastore.PendingModeration, nil
+}
+
+func TestModerateResponses(t *testing.T) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	ctx := context.Background()
+
+	sender := datastoretest.SeedUser(t, repos)
+	reel := datastoretest.SeedReel(t, repos, sender.UID, datastore.DeliveredReelStatus)
+
+	service := &services.ResponseService{
+		UnitOfWork:   memory.NewUnitOfWork(store),
+		ResponseRepo: repos.Responses,
+		ReelRepo:     repos.Reels,
+		VideoRepo:    repos.Videos,
+		Moderator:    holdAll{},
+	}
+
+	note, err := service.LeaveNote(ctx, &services.Responder{Reel: reel, Recipient: reel.Recipients[0]}, "Thank you!")
+	require.NoError(t, err)
+
+	handler := (&PublicHandler{Opts: types.APIOptions{
+		Authenticator: staticAuthenticator{user: sender},
+		ReelRepo:      repos.Reels,
+		Responses:     service,
+	}}).BuildRoutes()
+
+	request := func(method string, path string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/v1/reels/"+reel.UID+"/responses"+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := request(http.MethodGet, "/?status=pending", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), note.UID)
+
+	require.Equal(t, http.StatusBadRequest, request(http.MethodGet, "/?status=unknown", "").Code)
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPut, "/"+note.UID+"/status", `{"status":"pending"}`).Code)
+	require.Equal(t, http.StatusOK, request(http.MethodPut, "/"+note.UID+"/status", `{"status":"approved"}`).Code)
+	require.Equal(t, http.StatusNotFound, request(http.MethodPut, "/"+note.UID+"/status", `{"status":"rejected"}`).Code)
+
+	w = request(http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), note.UID)
+}
@@ -0,0 +1,84 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestReadReelFilter(t *testing.T) {
+	filter, err := readReelFilter(httptest.NewRequest("GET", "/reels", nil))
+	require.NoError(t, err)
+	require.Equal(t, datastore.ReelFilter{}, filter)
+
+	url := "/reels?delivery_status=scheduled&delivery_from=2024-01-01T00:00:00Z&created_to=2024-02-01T00:00:00Z&private=false&has_recipients=1&recipient=%20alex@example.com&q=beach+%22surprise+party%22"
+	filter, err = readReelFilter(httptest.NewRequest("GET", url, nil))
+	require.NoError(t, err)
+	require.Equal(t, datastore.ReelFilter{
+		DeliveryStatus: datastore.ScheduledReelStatus,
+		DeliveryFrom:   null.TimeFrom(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		CreatedTo:      null.TimeFrom(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+		Private:        null.BoolFrom(false),
+		HasRecipients:  null.BoolFrom(true),
+		RecipientEmail: "alex@example.com",
+		Query:          `beach "surprise party"`,
+	}, filter)
+
+	for _, query := range []string{"delivery_status=lost", "delivery_to=tomorrow", "private=maybe", "q=" + strings.Repeat("a", maxSearchLength+1)} {
+		_, err := readReelFilter(httptest.NewRequest("GET", "/reels?"+query, nil))
+		require.Error(t, err, query)
+	}
+}
+
+func TestUpdateReminderSettings(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	user := datastoretest.GenerateUser()
+	require.NoError(t, memory.NewUserRepo(store).CreateUser(ctx, user))
+
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(ctx, video))
+
+	reel := datastoretest.GenerateReel(video.UID, user.UID)
+	require.NoError(t, memory.NewReelRepo(store).CreateReel(ctx, reel))
+
+	handler := (&PublicHandler{Opts: types.APIOptions{
+		Authenticator: staticAuthenticator{user: user},
+		ReelRepo:      memory.NewReelRepo(store),
+	}}).BuildRoutes()
+
+	request := func(ifMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/v1/reels/"+reel.UID+"/reminders", strings.NewReader(`{"suppress":true}`))
+		r.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusPreconditionRequired, request("").Code)
+	require.Equal(t, http.StatusPreconditionFailed, request(`"0"`).Code)
+
+	w := request(`"` + strconv.Itoa(reel.Version) + `"`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, strconv.Quote(strconv.Itoa(reel.Version+1)), w.Header().Get("ETag"))
+
+	updated, err := memory.NewReelRepo(store).GetReelByID(ctx, reel.UID)
+	require.NoError(t, err)
+	require.True(t, updated.SuppressReminders)
+}
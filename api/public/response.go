@@ -0,0 +1,38 @@
+package public
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type serverResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func readJSON(r *http.Request, dst any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(dst)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func respondOK(w http.ResponseWriter, message string, data any) {
+	writeJSON(w, http.StatusOK, serverResponse{Status: true, Message: message, Data: data})
+}
+
+func respondError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, serverResponse{Status: false, Message: message})
+}
+
+func (p *PublicHandler) respondInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	p.Opts.Logger.ErrorContext(r.Context(), "internal server error", "error", err, "path", r.URL.Path)
+	respondError(w, http.StatusInternalServerError, "something went wrong")
+}
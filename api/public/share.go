@@ -0,0 +1,176 @@
+package public
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/guregu/null.v4"
+)
+
+// sharePasswordHeader carries a share link's password so it stays out of urls and logs
+const sharePasswordHeader = "X-Share-Password"
+
+type createShareLinkRequest struct {
+	ExpiresAt null.Time `json:"expires_at"`
+	MaxViews  null.Int  `json:"max_views"`
+	Password  string    `json:"password"`
+}
+
+type shareLinkResponse struct {
+	datastore.ShareLink
+	URL string `json:"url"`
+}
+
+func (p *PublicHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	var body createShareLinkRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	link, shareURL, err := p.Opts.ShareLinks.Create(r.Context(), reel, services.ShareLinkOptions{
+		ExpiresAt: body.ExpiresAt,
+		MaxViews:  body.MaxViews,
+		Password:  body.Password,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrReelNotDelivered):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrInvalidShareOptions):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			p.respondInternalError(w, r, err)
+		}
+		return
+	}
+
+	respondOK(w, "share link created", shareLinkResponse{ShareLink: *link, URL: shareURL})
+}
+
+func (p *PublicHandler) GetShareLinks(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	links, err := p.Opts.ShareLinks.ShareLinkRepo.GetShareLinksByReel(r.Context(), reel.UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	response := make([]shareLinkResponse, len(links))
+	for i := range links {
+		shareURL, err := p.Opts.ShareLinks.URL(&links[i])
+		if err != nil {
+			p.respondInternalError(w, r, err)
+			return
+		}
+		response[i] = shareLinkResponse{ShareLink: links[i], URL: shareURL}
+	}
+
+	respondOK(w, "share links fetched successfully", response)
+}
+
+func (p *PublicHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.getOwnedReel(r)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	err = p.Opts.ShareLinks.ShareLinkRepo.RevokeShareLink(r.Context(), reel.UID, chi.URLParam(r, "linkID"))
+	if err != nil {
+		if errors.Is(err, datastore.ErrShareLinkNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "share link revoked", nil)
+}
+
+// GetSharedReel lets anyone with a share link watch the reel, each successful request counts as a view
+func (p *PublicHandler) GetSharedReel(w http.ResponseWriter, r *http.Request) {
+	view, err := p.Opts.ShareLinks.Resolve(r.Context(), chi.URLParam(r, "token"), r.Header.Get(sharePasswordHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidShareLink):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrSharePasswordNeeded), errors.Is(err, services.ErrWrongSharePassword):
+			respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, services.ErrShareLinkLocked):
+			respondError(w, http.StatusTooManyRequests, err.Error())
+		default:
+			p.respondInternalError(w, r, err)
+		}
+		return
+	}
+
+	respondOK(w, "reel fetched successfully", view)
+}
+
+// streamContentTypes are the content types of the files a shared reel is streamed from
+var streamContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+}
+
+// StreamSharedReel serves the video behind a stream url handed out when a share link is opened
+func (p *PublicHandler) StreamSharedReel(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "*")
+
+	rc, err := p.Opts.ShareLinks.Stream(r.Context(), chi.URLParam(r, "token"), name)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidShareLink), errors.Is(err, storage.ErrObjectNotFound), errors.Is(err, storage.ErrInvalidKey):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			p.respondInternalError(w, r, err)
+		}
+		return
+	}
+	defer rc.Close()
+
+	contentType, ok := streamContentTypes[path.Ext(name)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	// revoking the link has to stop playback, so nothing is kept in caches
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, rc)
+}
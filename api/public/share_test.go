@@ -0,0 +1,106 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/api/types"
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+)
+
+// newSharedReel seeds a delivered reel created without an account and the service sharing it
+func newSharedReel(t *testing.T) (*services.ShareLinkService, *datastore.Reel) {
+	repos := memory.NewRepositories(memory.NewStore())
+
+	return &services.ShareLinkService{
+		ShareLinkRepo: repos.Shares,
+		ReelRepo:      repos.Reels,
+		VideoRepo:     repos.Videos,
+		Signer:        token.NewSigner("secret"),
+		Storage:       storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()}),
+		APIURL:        "http://localhost:8080",
+		StreamTTL:     time.Hour,
+	}, datastoretest.SeedReel(t, repos, "", datastore.DeliveredReelStatus)
+}
+
+func TestGetSharedReel(t *testing.T) {
+	service, reel := newSharedReel(t)
+	ctx := context.Background()
+
+	_, shareURL, err := service.Create(ctx, reel, services.ShareLinkOptions{Password: "open sesame"})
+	require.NoError(t, err)
+
+	u, err := url.Parse(shareURL)
+	require.NoError(t, err)
+
+	handler := (&PublicHandler{Opts: types.APIOptions{ShareLinks: service}}).BuildRoutes()
+
+	request := func(password string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, u.Path, nil)
+		if password != "" {
+			r.Header.Set(sharePasswordHeader, password)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusUnauthorized, request("").Code)
+	require.Equal(t, http.StatusUnauthorized, request("wrong").Code)
+
+	w := request("open sesame")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), reel.Title)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/share/unknown", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestStreamSharedReel(t *testing.T) {
+	service, reel := newSharedReel(t)
+	ctx := context.Background()
+	video, err := service.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	require.NoError(t, err)
+	require.NoError(t, service.Storage.Put(ctx, video.Key, strings.NewReader("source")))
+
+	link, shareURL, err := service.Create(ctx, reel, services.ShareLinkOptions{})
+	require.NoError(t, err)
+
+	u, err := url.Parse(shareURL)
+	require.NoError(t, err)
+
+	view, err := service.Resolve(ctx, path.Base(u.Path), "")
+	require.NoError(t, err)
+
+	streamURL, err := url.Parse(view.StreamURL)
+	require.NoError(t, err)
+
+	handler := (&PublicHandler{Opts: types.APIOptions{ShareLinks: service}}).BuildRoutes()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, streamURL.Path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "source", w.Body.String())
+	require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	require.NoError(t, service.ShareLinkRepo.RevokeShareLink(ctx, reel.UID, link.UID))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, streamURL.Path, nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
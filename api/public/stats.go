@@ -0,0 +1,49 @@
+package public
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+const (
+	defaultTimelineMonths = 12
+	maxTimelineMonths     = 60
+)
+
+type reelStatsResponse struct {
+	*datastore.ReelStats
+	Timeline []datastore.MonthlyDeliveries `json:"timeline"`
+}
+
+// GetReelStats summarises the authenticated user's reels for the dashboard, with the number of
+// reels due each month over the next `months` months
+func (p *PublicHandler) GetReelStats(w http.ResponseWriter, r *http.Request) {
+	months := defaultTimelineMonths
+	if value := r.URL.Query().Get("months"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxTimelineMonths {
+			respondError(w, http.StatusBadRequest, "months must be a number between 1 and 60")
+			return
+		}
+		months = n
+	}
+
+	userID := getAuthUser(r).UID
+
+	stats, err := p.Opts.ReelRepo.GetReelStats(r.Context(), userID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	timeline, err := p.Opts.ReelRepo.GetDeliveryTimeline(r.Context(), userID, time.Now(), months)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "reel stats fetched successfully", reelStatsResponse{ReelStats: stats, Timeline: timeline})
+}
@@ -0,0 +1,63 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTrash pages through the authenticated user's deleted reels that can still be restored
+func (p *PublicHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	pageable, err := readPageable(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reels, pagination, err := p.Opts.Trash.Trash(r.Context(), getAuthUser(r).UID, pageable)
+	if err != nil {
+		if errors.Is(err, datastore.ErrInvalidSort) || errors.Is(err, datastore.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "trash fetched successfully", newPagedResponse(reels, pagination))
+}
+
+func (p *PublicHandler) RestoreReel(w http.ResponseWriter, r *http.Request) {
+	reel, err := p.Opts.ReelRepo.GetDeletedReelByID(r.Context(), chi.URLParam(r, "reelID"))
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	if reel.UserID.String != getAuthUser(r).UID {
+		respondError(w, http.StatusNotFound, datastore.ErrReelNotFound.Error())
+		return
+	}
+
+	err = p.Opts.Trash.Restore(r.Context(), reel)
+	if err != nil {
+		switch {
+		case errors.Is(err, datastore.ErrReelVideoInUse):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, datastore.ErrReelNotRestored):
+			respondError(w, http.StatusNotFound, datastore.ErrReelNotFound.Error())
+		default:
+			p.respondInternalError(w, r, err)
+		}
+		return
+	}
+
+	setETag(w, reel.Version)
+	respondOK(w, "reel restored successfully", reel)
+}
@@ -0,0 +1,73 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+func (p *PublicHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	user, err := p.Opts.UserRepo.GetUserByID(r.Context(), getAuthUser(r).UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	respondOK(w, "user fetched successfully", user)
+}
+
+type updateMeRequest struct {
+	Firstname *string `json:"first_name"`
+	Lastname  *string `json:"last_name"`
+}
+
+// UpdateMe changes the authenticated user's profile. The If-Match header must carry the
+// version the client last saw so concurrent edits don't overwrite each other
+func (p *PublicHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	var body updateMeRequest
+	if err := readJSON(r, &body); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := p.Opts.UserRepo.GetUserByID(r.Context(), getAuthUser(r).UID)
+	if err != nil {
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, user.Version)
+	if !ok {
+		return
+	}
+
+	if body.Firstname != nil {
+		user.Firstname = strings.TrimSpace(*body.Firstname)
+	}
+
+	if body.Lastname != nil {
+		user.Lastname = strings.TrimSpace(*body.Lastname)
+	}
+
+	if user.Firstname == "" || user.Lastname == "" {
+		respondError(w, http.StatusBadRequest, "first name and last name can't be empty")
+		return
+	}
+
+	user.Version = version
+
+	if err := p.Opts.UserRepo.UpdateUser(r.Context(), user); err != nil {
+		if errors.Is(err, datastore.ErrVersionConflict) {
+			respondError(w, http.StatusPreconditionFailed, errStaleIfMatch.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	respondOK(w, "user updated successfully", user)
+}
@@ -0,0 +1,42 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayo-awe/memoreel-be/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetRecipientView lets a recipient without an account watch a reel using the signed link from their delivery email
+func (p *PublicHandler) GetRecipientView(w http.ResponseWriter, r *http.Request) {
+	view, err := p.Opts.RecipientLinks.Resolve(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidViewLink) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		p.respondInternalError(w, r, err)
+		return
+	}
+
+	respondOK(w, "reel fetched successfully", view)
+}
+
+// GetRecipientPlaylist serves the HLS master playlist for the reel behind a signed link
+func (p *PublicHandler) GetRecipientPlaylist(w http.ResponseWriter, r *http.Request) {
+	playlist, err := p.Opts.RecipientLinks.MasterPlaylist(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidViewLink), errors.Is(err, services.ErrPlaylistNotReady):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			p.respondInternalError(w, r, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(playlist)
+}
@@ -2,11 +2,38 @@ package types
 
 import (
 	"log/slog"
+	"net/http"
 
 	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/services"
 )
 
+// Authenticator identifies the user making a request
+type Authenticator interface {
+	Authenticate(r *http.Request) (*datastore.User, error)
+}
+
 type APIOptions struct {
-	DB     database.Database
-	Logger slog.Logger
+	DB            database.Database
+	Logger        slog.Logger
+	Authenticator Authenticator
+	// SupportToken is the bearer token support tooling sends, the support api is off without it
+	SupportToken string
+
+	UnitOfWork  datastore.UnitOfWork
+	UserRepo    datastore.UserRepository
+	ReelRepo    datastore.ReelRepository
+	VideoRepo   datastore.VideoRepository
+	AuditRepo   datastore.AuditRepository
+	InboxRepo   datastore.InboxRepository
+	GalleryRepo datastore.GalleryRepository
+
+	RecipientLinks *services.RecipientLinkService
+	Calendar       *services.CalendarService
+	Gallery        *services.GalleryService
+	ShareLinks     *services.ShareLinkService
+	Responses      *services.ResponseService
+	Contributions  *services.ContributionService
+	Trash          *services.TrashService
 }
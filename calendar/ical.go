@@ -0,0 +1,109 @@
+// Package calendar writes iCalendar (RFC 5545) feeds that calendar apps can subscribe to
+package calendar
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	prodID     = "-//Memoreel//Reel Deliveries//EN"
+	timeFormat = "20060102T150405Z"
+	// lines longer than this many octets are folded onto continuation lines
+	maxLineLength = 75
+)
+
+type Event struct {
+	// UID identifies the event across refreshes of the feed, it must stay the same when the event changes
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// Stamp is when the event was last changed and Sequence its revision, calendar apps use
+	// them to tell an updated event from the one they already have
+	Stamp    time.Time
+	Sequence int
+}
+
+type Feed struct {
+	Name   string
+	Events []Event
+}
+
+// Encode writes the feed in iCalendar format
+func (f Feed) Encode() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if f.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(f.Name))
+	}
+
+	for _, event := range f.Events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escapeText(event.UID))
+		writeLine(&buf, "DTSTAMP:"+formatTime(event.Stamp))
+		writeLine(&buf, "DTSTART:"+formatTime(event.Start))
+		writeLine(&buf, "DTEND:"+formatTime(event.End))
+		writeLine(&buf, "SEQUENCE:"+strconv.Itoa(event.Sequence))
+		writeLine(&buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// escapeText escapes the characters that have a meaning in iCalendar text values
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine writes a content line ended by CRLF, folding it so no line is longer than
+// maxLineLength octets. A fold never splits a multi-byte character
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+
+		// continuation lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
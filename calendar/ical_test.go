@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 30, 0, 0, time.FixedZone("WAT", 3600))
+
+	feed := Feed{
+		Name: "Memoreel deliveries",
+		Events: []Event{{
+			UID:         "01HQ@memoreel",
+			Summary:     "Reel delivery: Mum, Dad; and me",
+			Description: "Delivering to 2 recipients\nsee C:\\reels",
+			Start:       start,
+			End:         start.Add(30 * time.Minute),
+			Stamp:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sequence:    3,
+		}},
+	}
+
+	require.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Memoreel//Reel Deliveries//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Memoreel deliveries",
+		"BEGIN:VEVENT",
+		"UID:01HQ@memoreel",
+		"DTSTAMP:20250101T000000Z",
+		"DTSTART:20250601T083000Z",
+		"DTEND:20250601T090000Z",
+		"SEQUENCE:3",
+		`SUMMARY:Reel delivery: Mum\, Dad\; and me`,
+		`DESCRIPTION:Delivering to 2 recipients\nsee C:\\reels`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), string(feed.Encode()))
+}
+
+func TestWriteLineFolds(t *testing.T) {
+	var buf bytes.Buffer
+	line := "SUMMARY:" + strings.Repeat("é", 100)
+	writeLine(&buf, line)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+
+	var unfolded string
+	for i, l := range lines {
+		require.LessOrEqual(t, len(l), maxLineLength)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(l, " "))
+			l = l[1:]
+		}
+		unfolded += l
+	}
+
+	require.Equal(t, line, unfolded)
+}
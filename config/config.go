@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
+	"strconv"
+	"time"
 
 	"github.com/ayo-awe/memoreel-be/util"
 	"github.com/joho/godotenv"
@@ -14,6 +17,11 @@ import (
 
 var config applicationConfiguration
 
+// minSigningSecretLength keeps signed links from being forged by guessing the secret
+const minSigningSecretLength = 32
+
+var ErrWeakSigningSecret = fmt.Errorf("SIGNING_SECRET must be at least %d characters", minSigningSecretLength)
+
 type ConfigType string
 
 const (
@@ -29,6 +37,13 @@ type applicationConfiguration struct {
 type Configuration struct {
 	Database DatabaseConfiguration
 	Server   ServerConfiguration
+	App      AppConfiguration
+	Security SecurityConfiguration
+	Mailer   MailerConfiguration
+	Storage  StorageConfiguration
+	Media    MediaConfiguration
+	Reminder ReminderConfiguration
+	Trash    TrashConfiguration
 }
 
 type DatabaseConfiguration struct {
@@ -38,19 +53,124 @@ type DatabaseConfiguration struct {
 	Database string `env:"DB_DATABASE, default=memoreel"`
 	Port     int    `env:"DB_PORT, default=5432"`
 	SSLMode  string `env:"DB_SSL_MODE, default=disable"`
+
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS, default=25"`
+	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS, default=25"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME, default=30m"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME, default=5m"`
+
+	// ConnectRetries is how many more times connecting is attempted when the database
+	// isn't reachable yet, waiting ConnectBackoff before the first retry and twice as long
+	// before each one after that
+	ConnectRetries int           `env:"DB_CONNECT_RETRIES, default=5"`
+	ConnectBackoff time.Duration `env:"DB_CONNECT_BACKOFF, default=1s"`
+
+	// ApplicationName identifies the api's connections in pg_stat_activity
+	ApplicationName string `env:"DB_APPLICATION_NAME, default=memoreel-api"`
+	// StatementTimeout aborts statements that run longer, zero disables it
+	StatementTimeout time.Duration `env:"DB_STATEMENT_TIMEOUT, default=30s"`
+
+	// ReplicaHost is a read replica that read-only queries can use, leave it empty to
+	// send everything to the primary. The replica shares the primary's credentials and settings
+	ReplicaHost string `env:"DB_REPLICA_HOST"`
+	ReplicaPort int    `env:"DB_REPLICA_PORT, default=5432"`
 }
 
 type ServerConfiguration struct {
 	Port int `env:"PORT, default=8080"`
 }
 
+type AppConfiguration struct {
+	// ClientURL is the base url of the web client, links sent in emails point here
+	ClientURL string `env:"CLIENT_URL, default=http://localhost:3000"`
+	// APIURL is the public base url the api is mounted on
+	APIURL string `env:"API_URL, default=http://localhost:8080/api"`
+}
+
+type SecurityConfiguration struct {
+	// SigningSecret signs recipient, share and contribution links, it's required
+	SigningSecret    string        `env:"SIGNING_SECRET"`
+	RecipientLinkTTL time.Duration `env:"RECIPIENT_LINK_TTL, default=8760h"`
+	// ShareStreamTTL is how long a share link's stream url works after the link is opened
+	ShareStreamTTL time.Duration `env:"SHARE_STREAM_TTL, default=1h"`
+	// SupportToken authenticates support tooling on the support api, which is off while it's empty
+	SupportToken string `env:"SUPPORT_API_TOKEN"`
+}
+
+type MailerConfiguration struct {
+	Host     string `env:"SMTP_HOST, default=localhost"`
+	Port     int    `env:"SMTP_PORT, default=1025"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	From     string `env:"MAIL_FROM, default=Memoreel <no-reply@memoreel.com>"`
+}
+
+type StorageConfiguration struct {
+	// Directory on disk where local storage keeps objects
+	Directory string `env:"STORAGE_DIRECTORY, default=./storage"`
+	// BaseURL objects in local storage are served from
+	BaseURL string `env:"STORAGE_BASE_URL, default=http://localhost:8080/media"`
+}
+
+type MediaConfiguration struct {
+	FFmpegPath string `env:"FFMPEG_PATH, default=ffmpeg"`
+}
+
+type ReminderConfiguration struct {
+	// DaysBefore lists how many days ahead of delivery the creator is reminded
+	DaysBefore []int `env:"REMINDER_DAYS_BEFORE, default=30,7"`
+}
+
+type TrashConfiguration struct {
+	// Retention is how long deleted reels can be restored before they're purged
+	Retention time.Duration `env:"TRASH_RETENTION, default=720h"`
+}
+
+// Validate rejects configuration the api can't safely run with
+func (c Configuration) Validate() error {
+	if len(c.Security.SigningSecret) < minSigningSecretLength {
+		return ErrWeakSigningSecret
+	}
+
+	return nil
+}
+
+// HasReplica reports whether a read replica is configured
+func (d DatabaseConfiguration) HasReplica() bool {
+	return d.ReplicaHost != ""
+}
+
+// Replica returns the configuration for connecting to the read replica
+func (d DatabaseConfiguration) Replica() DatabaseConfiguration {
+	replica := d
+	replica.Host = d.ReplicaHost
+	replica.Port = d.ReplicaPort
+
+	return replica
+}
+
 func (d DatabaseConfiguration) BuildDSN() string {
-	dsnFormat := "postgres://%s@%s/%s?sslmode=%s"
+	params := url.Values{}
+	params.Set("sslmode", d.SSLMode)
 
-	auth := fmt.Sprintf("%s:%s", d.Username, d.Password)
-	address := fmt.Sprintf("%s:%d", d.Host, d.Port)
+	if d.ApplicationName != "" {
+		params.Set("application_name", d.ApplicationName)
+	}
+
+	// unknown parameters are sent to the server as run-time settings
+	if d.StatementTimeout > 0 {
+		params.Set("statement_timeout", strconv.FormatInt(d.StatementTimeout.Milliseconds(), 10))
+	}
 
-	return fmt.Sprintf(dsnFormat, auth, address, d.Database, d.SSLMode)
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.Username, d.Password),
+		Host:     fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:     d.Database,
+		RawQuery: params.Encode(),
+	}
+
+	return dsn.String()
 }
 
 func Get(configType ConfigType) Configuration {
@@ -76,10 +196,29 @@ func LoadConfig() error {
 		return err
 	}
 
-	err = envconfig.Process(context.Background(), &config)
+	// envconfig keeps fields that are already set, so every load starts afresh
+	var loaded applicationConfiguration
+	err = envconfig.Process(context.Background(), &loaded)
 	if err != nil {
 		return err
 	}
 
+	config = loaded
+
 	return nil
 }
+
+// Load loads the configuration of the given type and validates it, the api should refuse
+// to start when it fails
+func Load(configType ConfigType) (Configuration, error) {
+	if err := LoadConfig(); err != nil {
+		return Configuration{}, err
+	}
+
+	cfg := Get(configType)
+	if err := cfg.Validate(); err != nil {
+		return Configuration{}, err
+	}
+
+	return cfg, nil
+}
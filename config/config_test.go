@@ -0,0 +1,73 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDSN(t *testing.T) {
+	cfg := DatabaseConfiguration{
+		Username:         "memoreel",
+		Password:         "p@ss/word",
+		Host:             "db",
+		Port:             5432,
+		Database:         "memoreel",
+		SSLMode:          "disable",
+		ApplicationName:  "memoreel-api",
+		StatementTimeout: 30 * time.Second,
+	}
+
+	dsn, err := url.Parse(cfg.BuildDSN())
+	require.NoError(t, err)
+
+	password, _ := dsn.User.Password()
+	require.Equal(t, "p@ss/word", password)
+	require.Equal(t, "db:5432", dsn.Host)
+	require.Equal(t, "/memoreel", dsn.Path)
+	require.Equal(t, "disable", dsn.Query().Get("sslmode"))
+	require.Equal(t, "memoreel-api", dsn.Query().Get("application_name"))
+	require.Equal(t, "30000", dsn.Query().Get("statement_timeout"))
+
+	cfg.StatementTimeout = 0
+	dsn, err = url.Parse(cfg.BuildDSN())
+	require.NoError(t, err)
+	require.False(t, dsn.Query().Has("statement_timeout"))
+}
+
+func TestReplica(t *testing.T) {
+	cfg := DatabaseConfiguration{Host: "primary", Port: 5432, Username: "memoreel", StatementTimeout: time.Second}
+	require.False(t, cfg.HasReplica())
+
+	cfg.ReplicaHost = "replica"
+	cfg.ReplicaPort = 5433
+	require.True(t, cfg.HasReplica())
+
+	replica := cfg.Replica()
+	require.Equal(t, "replica", replica.Host)
+	require.Equal(t, 5433, replica.Port)
+	require.Equal(t, cfg.Username, replica.Username)
+	require.Equal(t, cfg.StatementTimeout, replica.StatementTimeout)
+
+	// the primary is left as is
+	require.Equal(t, "primary", cfg.Host)
+}
+
+func TestLoadRequiresSigningSecret(t *testing.T) {
+	t.Setenv("SIGNING_SECRET", "")
+	_, err := Load(Prod)
+	require.ErrorIs(t, err, ErrWeakSigningSecret)
+
+	t.Setenv("SIGNING_SECRET", "too-short")
+	_, err = Load(Prod)
+	require.ErrorIs(t, err, ErrWeakSigningSecret)
+
+	secret := strings.Repeat("s", minSigningSecretLength)
+	t.Setenv("SIGNING_SECRET", secret)
+	cfg, err := Load(Prod)
+	require.NoError(t, err)
+	require.Equal(t, secret, cfg.Security.SigningSecret)
+}
@@ -1,8 +1,17 @@
 package database
 
-import "github.com/jmoiron/sqlx"
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type Database interface {
+	// GetDB returns the primary, it takes writes and reads that must see them
 	GetDB() *sqlx.DB
+	// GetReplicaDB returns a read replica that may lag behind the primary. It's the
+	// primary itself when no replica is configured
+	GetReplicaDB() *sqlx.DB
+	Stats() sql.DBStats
 	Close() error
 }
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type auditRepo struct {
+	store *Store
+}
+
+func NewAuditRepo(store *Store) datastore.AuditRepository {
+	return &auditRepo{store: store}
+}
+
+func (a auditRepo) GetReelAuditEntries(ctx context.Context, reelID string) ([]datastore.AuditEntry, error) {
+	a.store.mu.RLock()
+	defer a.store.mu.RUnlock()
+
+	var entries []datastore.AuditEntry
+	for _, entry := range a.store.audit {
+		if entry.ReelID == reelID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// recordReelAudit appends an audit entry, the caller must hold the store's lock
+func (s *Store) recordReelAudit(ctx context.Context, reelID string, action datastore.AuditAction, changes datastore.AuditChanges) {
+	entry := datastore.NewReelAuditEntry(ctx, reelID, action, changes)
+	entry.CreatedAt = now()
+
+	s.audit = append(s.audit, entry)
+}
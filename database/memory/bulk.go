@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+// the single item operations leave the store untouched when they fail, so applying
+// them one after the other gives bulk operations the same per item semantics as postgres
+
+func (r reelRepo) CreateReels(ctx context.Context, reels []*datastore.Reel) ([]error, error) {
+	results := make([]error, len(reels))
+	for i := range reels {
+		results[i] = r.CreateReel(ctx, reels[i])
+	}
+
+	return results, nil
+}
+
+func (r reelRepo) DeleteReels(ctx context.Context, reelIDs []string) ([]error, error) {
+	results := make([]error, len(reelIDs))
+	for i := range reelIDs {
+		results[i] = r.DeleteReel(ctx, reelIDs[i])
+	}
+
+	return results, nil
+}
+
+func (r reelRepo) AddRecipientsToReels(ctx context.Context, reels []*datastore.Reel, recipients []datastore.Recipients) ([]error, error) {
+	if len(reels) != len(recipients) {
+		return nil, datastore.ErrBulkLengthMismatch
+	}
+
+	results := make([]error, len(reels))
+	for i := range reels {
+		results[i] = r.AddRecipients(ctx, reels[i], recipients[i])
+	}
+
+	return results, nil
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type contributionRepo struct {
+	store *Store
+}
+
+func NewContributionRepo(store *Store) datastore.ContributionRepository {
+	return &contributionRepo{store: store}
+}
+
+func (c contributionRepo) GetContributorByID(ctx context.Context, id string) (*datastore.Contributor, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	contributor, ok := c.store.contributors[id]
+	if !ok || contributor.RemovedAt.Valid {
+		return nil, datastore.ErrContributorNotFound
+	}
+
+	return &contributor, nil
+}
+
+func (c contributionRepo) GetContributorsByReel(ctx context.Context, reelID string) ([]datastore.Contributor, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	contributors := []datastore.Contributor{}
+	for _, contributor := range c.store.contributors {
+		if contributor.ReelID == reelID && !contributor.RemovedAt.Valid {
+			contributors = append(contributors, contributor)
+		}
+	}
+
+	slices.SortFunc(contributors, func(a, b datastore.Contributor) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+
+	return contributors, nil
+}
+
+func (c contributionRepo) CreateContributor(ctx context.Context, contributor *datastore.Contributor, messages ...datastore.OutboxMessage) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.store.reels[contributor.ReelID]; !ok {
+		return violation(datastore.ErrReelNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	for _, other := range c.store.contributors {
+		if other.ReelID == contributor.ReelID && !other.RemovedAt.Valid && strings.EqualFold(other.Email, contributor.Email) {
+			return violation(datastore.ErrDuplicateContributor, datastore.ErrDuplicate)
+		}
+	}
+
+	contributor.CreatedAt = now()
+	contributor.RemovedAt = null.Time{}
+	c.store.contributors[contributor.UID] = *contributor
+	c.store.enqueue(messages)
+
+	return nil
+}
+
+func (c contributionRepo) RemoveContributor(ctx context.Context, reelID string, id string) ([]datastore.Video, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	contributor, ok := c.store.contributors[id]
+	if !ok || contributor.ReelID != reelID || contributor.RemovedAt.Valid {
+		return nil, datastore.ErrContributorNotFound
+	}
+
+	contributor.RemovedAt = null.TimeFrom(now())
+	c.store.contributors[id] = contributor
+
+	videos := []datastore.Video{}
+	for _, contribution := range c.store.contributions {
+		if contribution.ContributorID != id || contribution.Status == datastore.ApprovedContribution {
+			continue
+		}
+
+		videos = append(videos, c.store.videos[contribution.VideoID])
+		delete(c.store.contributions, contribution.UID)
+		delete(c.store.videos, contribution.VideoID)
+	}
+
+	return videos, nil
+}
+
+func (c contributionRepo) GetContributionsByReel(ctx context.Context, reelID string) ([]datastore.Contribution, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	return c.contributions(reelID), nil
+}
+
+// contributions returns the reel's contributions by position, the caller holds the lock
+func (c contributionRepo) contributions(reelID string) []datastore.Contribution {
+	contributions := []datastore.Contribution{}
+	for _, contribution := range c.store.contributions {
+		if contribution.ReelID == reelID {
+			contributions = append(contributions, contribution)
+		}
+	}
+
+	slices.SortFunc(contributions, func(a, b datastore.Contribution) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+
+	return contributions
+}
+
+func (c contributionRepo) CreateContribution(ctx context.Context, contribution *datastore.Contribution, maxPerContributor int) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.store.reels[contribution.ReelID]; !ok {
+		return violation(datastore.ErrReelNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if _, ok := c.store.contributors[contribution.ContributorID]; !ok {
+		return violation(datastore.ErrContributorNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if _, ok := c.store.videos[contribution.VideoID]; !ok {
+		return violation(datastore.ErrVideoNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if !contribution.Status.IsValid() {
+		return datastore.ErrConstraintViolation
+	}
+
+	existing := c.contributions(contribution.ReelID)
+
+	added := 0
+	for _, other := range existing {
+		if other.ContributorID == contribution.ContributorID {
+			added++
+		}
+	}
+
+	if added >= maxPerContributor {
+		return datastore.ErrContributionLimitReached
+	}
+
+	contribution.Position = 1
+	if len(existing) > 0 {
+		contribution.Position = existing[len(existing)-1].Position + 1
+	}
+
+	timestamp := now()
+	contribution.CreatedAt = timestamp
+	contribution.UpdatedAt = timestamp
+	c.store.contributions[contribution.UID] = *contribution
+
+	return nil
+}
+
+func (c contributionRepo) SetContributionStatus(ctx context.Context, reelID string, id string, status datastore.ContributionStatus) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	contribution, ok := c.store.contributions[id]
+	if !ok || contribution.ReelID != reelID {
+		return datastore.ErrContributionNotFound
+	}
+
+	if !status.IsValid() {
+		return datastore.ErrConstraintViolation
+	}
+
+	contribution.Status = status
+	contribution.UpdatedAt = now()
+	c.store.contributions[id] = contribution
+
+	return nil
+}
+
+func (c contributionRepo) ReorderContributions(ctx context.Context, reelID string, ids []string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	existing := c.contributions(reelID)
+	if len(existing) != len(ids) {
+		return datastore.ErrInvalidContributionOrder
+	}
+
+	seen := map[string]bool{}
+	for _, id := range ids {
+		contribution, ok := c.store.contributions[id]
+		if !ok || contribution.ReelID != reelID || seen[id] {
+			return datastore.ErrInvalidContributionOrder
+		}
+		seen[id] = true
+	}
+
+	timestamp := now()
+	for i, id := range ids {
+		contribution := c.store.contributions[id]
+		contribution.Position = i + 1
+		contribution.UpdatedAt = timestamp
+		c.store.contributions[id] = contribution
+	}
+
+	return nil
+}
+
+func (c contributionRepo) DeleteContribution(ctx context.Context, reelID string, id string) (*datastore.Video, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	contribution, ok := c.store.contributions[id]
+	if !ok || contribution.ReelID != reelID {
+		return nil, datastore.ErrContributionNotFound
+	}
+
+	video := c.store.videos[contribution.VideoID]
+	delete(c.store.contributions, id)
+	delete(c.store.videos, contribution.VideoID)
+
+	return &video, nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type galleryRepo struct {
+	store *Store
+}
+
+func NewGalleryRepo(store *Store) datastore.GalleryRepository {
+	return &galleryRepo{store: store}
+}
+
+// galleryItem returns the reel as it's shown in the gallery, if it's shared there
+func (r galleryRepo) galleryItem(reel datastore.Reel) (datastore.GalleryItem, bool) {
+	if !reel.InGallery() {
+		return datastore.GalleryItem{}, false
+	}
+
+	return datastore.GalleryItem{
+		ReelID:      reel.UID,
+		Title:       reel.Title,
+		Description: reel.Description,
+		DeliveredAt: reel.DeliveryDate,
+		SenderName:  r.store.users[reel.UserID.String].Firstname,
+		VideoID:     reel.VideoID,
+	}, true
+}
+
+// compareGalleryItems orders items latest delivery first
+func compareGalleryItems(a, b datastore.GalleryItem) int {
+	if c := b.DeliveredAt.Compare(a.DeliveredAt); c != 0 {
+		return c
+	}
+	return strings.Compare(b.ReelID, a.ReelID)
+}
+
+func (r galleryRepo) GetGalleryPaged(ctx context.Context, pageable datastore.Pageable) ([]datastore.GalleryItem, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.GallerySort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	var from *datastore.GalleryItem
+	if cursor.ID != "" {
+		deliveredAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+		from = &datastore.GalleryItem{ReelID: cursor.ID, DeliveredAt: deliveredAt}
+	}
+
+	// a previous page is fetched in reverse from the cursor
+	compare := compareGalleryItems
+	if cursor.Direction == datastore.PrevPage {
+		compare = func(a, b datastore.GalleryItem) int { return compareGalleryItems(b, a) }
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []datastore.GalleryItem
+	var hasBehind bool
+	for _, reel := range r.store.reels {
+		item, ok := r.galleryItem(reel)
+		if !ok {
+			continue
+		}
+
+		if from != nil && compare(item, *from) <= 0 {
+			hasBehind = true
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	slices.SortFunc(items, compare)
+
+	if len(items) > pageable.Limit() {
+		items = items[:pageable.Limit()]
+	}
+
+	positions := make([]datastore.Cursor, len(items))
+	for i := range items {
+		positions[i] = datastore.GalleryPosition(items[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	items = items[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(items)
+	}
+
+	return items, pagination, nil
+}
+
+func (r galleryRepo) GetGalleryItem(ctx context.Context, reelID string) (*datastore.GalleryItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	item, ok := r.galleryItem(r.store.reels[reelID])
+	if !ok {
+		return nil, datastore.ErrReelNotFound
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type inboxRepo struct {
+	store *Store
+}
+
+func NewInboxRepo(store *Store) datastore.InboxRepository {
+	return &inboxRepo{store: store}
+}
+
+// inboxItem returns the reel as the email's recipient sees it, if it's in their inbox
+func (r inboxRepo) inboxItem(userID string, email string, reel datastore.Reel) (datastore.InboxItem, bool) {
+	if reel.DeletedAt.Valid || reel.DeliveryStatus != datastore.DeliveredReelStatus {
+		return datastore.InboxItem{}, false
+	}
+
+	for _, recipient := range readReel(reel).Recipients {
+		if strings.EqualFold(recipient.Email, email) {
+			_, hidden := r.store.hidden[inboxKey{userID: userID, reelID: reel.UID}]
+
+			return datastore.InboxItem{
+				ReelID:      reel.UID,
+				RecipientID: recipient.UID,
+				Title:       reel.Title,
+				Description: reel.Description,
+				DeliveredAt: reel.DeliveryDate,
+				Hidden:      hidden,
+			}, true
+		}
+	}
+
+	return datastore.InboxItem{}, false
+}
+
+// compareInboxItems orders items latest delivery first
+func compareInboxItems(a, b datastore.InboxItem) int {
+	if c := b.DeliveredAt.Compare(a.DeliveredAt); c != 0 {
+		return c
+	}
+	return strings.Compare(b.ReelID, a.ReelID)
+}
+
+func (r inboxRepo) GetInboxPaged(ctx context.Context, userID string, email string, filter datastore.InboxFilter, pageable datastore.Pageable) ([]datastore.InboxItem, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.InboxSort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	var from *datastore.InboxItem
+	if cursor.ID != "" {
+		deliveredAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+		from = &datastore.InboxItem{ReelID: cursor.ID, DeliveredAt: deliveredAt}
+	}
+
+	// a previous page is fetched in reverse from the cursor
+	compare := compareInboxItems
+	if cursor.Direction == datastore.PrevPage {
+		compare = func(a, b datastore.InboxItem) int { return compareInboxItems(b, a) }
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []datastore.InboxItem
+	var hasBehind bool
+	for _, reel := range r.store.reels {
+		item, ok := r.inboxItem(userID, email, reel)
+		if !ok || item.Hidden != filter.Hidden {
+			continue
+		}
+
+		if from != nil && compare(item, *from) <= 0 {
+			hasBehind = true
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	slices.SortFunc(items, compare)
+
+	if len(items) > pageable.Limit() {
+		items = items[:pageable.Limit()]
+	}
+
+	positions := make([]datastore.Cursor, len(items))
+	for i := range items {
+		positions[i] = datastore.InboxPosition(items[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	items = items[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(items)
+	}
+
+	return items, pagination, nil
+}
+
+func (r inboxRepo) SetInboxItemHidden(ctx context.Context, userID string, email string, reelID string, hidden bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reel, ok := r.store.reels[reelID]
+	if !ok {
+		return datastore.ErrInboxItemNotFound
+	}
+
+	if _, ok := r.inboxItem(userID, email, reel); !ok {
+		return datastore.ErrInboxItemNotFound
+	}
+
+	key := inboxKey{userID: userID, reelID: reelID}
+	if hidden {
+		if _, ok := r.store.hidden[key]; !ok {
+			r.store.hidden[key] = now()
+		}
+	} else {
+		delete(r.store.hidden, key)
+	}
+
+	return nil
+}
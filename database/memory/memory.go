@@ -0,0 +1,80 @@
+// Package memory implements the datastore repositories in memory. They honor the
+// same semantics as the postgres repositories and are meant for tests that
+// shouldn't depend on a running database
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type reminderKey struct {
+	reelID     string
+	daysBefore int
+}
+
+type inboxKey struct {
+	userID string
+	reelID string
+}
+
+// Store holds the data shared by the repositories created from it
+type Store struct {
+	mu sync.RWMutex
+
+	users     map[string]datastore.User
+	videos    map[string]datastore.Video
+	reels     map[string]datastore.Reel
+	reminders map[reminderKey]time.Time
+	outbox    map[string]datastore.OutboxMessage
+	audit     []datastore.AuditEntry
+	// hidden holds when each user hid a reel from their inbox
+	hidden     map[inboxKey]time.Time
+	viewTokens map[string]datastore.ViewToken
+	shareLinks map[string]datastore.ShareLink
+	responses  map[string]datastore.ReelResponse
+	// contributors and contributions of group reels
+	contributors  map[string]datastore.Contributor
+	contributions map[string]datastore.Contribution
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:         map[string]datastore.User{},
+		videos:        map[string]datastore.Video{},
+		reels:         map[string]datastore.Reel{},
+		reminders:     map[reminderKey]time.Time{},
+		outbox:        map[string]datastore.OutboxMessage{},
+		hidden:        map[inboxKey]time.Time{},
+		viewTokens:    map[string]datastore.ViewToken{},
+		shareLinks:    map[string]datastore.ShareLink{},
+		responses:     map[string]datastore.ReelResponse{},
+		contributors:  map[string]datastore.Contributor{},
+		contributions: map[string]datastore.Contribution{},
+	}
+}
+
+// now mirrors the precision of postgres timestamps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// constraintError mirrors how the postgres repositories report a violated constraint,
+// matching both the specific error and the general one for its class
+type constraintError struct {
+	matches []error
+}
+
+func violation(specific, class error) error {
+	return &constraintError{matches: []error{specific, class}}
+}
+
+func (e *constraintError) Error() string {
+	return e.matches[0].Error()
+}
+
+func (e *constraintError) Unwrap() []error {
+	return e.matches
+}
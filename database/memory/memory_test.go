@@ -0,0 +1,18 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	datastoretest.Run(t, func(t *testing.T) datastoretest.Repositories {
+		store := NewStore()
+
+		return datastoretest.Repositories{
+			Repositories: NewRepositories(store),
+			UnitOfWork:   NewUnitOfWork(store),
+		}
+	})
+}
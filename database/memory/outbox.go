@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type outboxRepo struct {
+	store *Store
+}
+
+func NewOutboxRepo(store *Store) datastore.OutboxRepository {
+	return &outboxRepo{store: store}
+}
+
+func (o outboxRepo) EnqueueMessages(ctx context.Context, messages ...datastore.OutboxMessage) error {
+	o.store.mu.Lock()
+	defer o.store.mu.Unlock()
+
+	o.store.enqueue(messages)
+
+	return nil
+}
+
+func (o outboxRepo) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]datastore.OutboxMessage, error) {
+	o.store.mu.Lock()
+	defer o.store.mu.Unlock()
+
+	timestamp := now()
+
+	var pending []datastore.OutboxMessage
+	for _, message := range o.store.outbox {
+		if message.DispatchedAt.Valid || message.DeadLetteredAt.Valid || message.AvailableAt.After(timestamp) {
+			continue
+		}
+
+		if message.LockedUntil.Valid && !message.LockedUntil.Time.Before(timestamp) {
+			continue
+		}
+
+		pending = append(pending, message)
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].AvailableAt.Before(pending[j].AvailableAt) })
+
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	for i := range pending {
+		pending[i].LockedUntil = null.TimeFrom(timestamp.Add(lease))
+		pending[i].Attempts++
+
+		o.store.outbox[pending[i].UID] = pending[i]
+	}
+
+	return pending, nil
+}
+
+func (o outboxRepo) MarkMessageDispatched(ctx context.Context, messageID string) error {
+	o.store.mu.Lock()
+	defer o.store.mu.Unlock()
+
+	message, ok := o.store.outbox[messageID]
+	if !ok {
+		return nil
+	}
+
+	message.DispatchedAt = null.TimeFrom(now())
+	message.LockedUntil = null.Time{}
+	message.LastError = null.String{}
+	o.store.outbox[messageID] = message
+
+	return nil
+}
+
+func (o outboxRepo) MarkMessageFailed(ctx context.Context, messageID string, reason string, retryAt time.Time) error {
+	o.store.mu.Lock()
+	defer o.store.mu.Unlock()
+
+	message, ok := o.store.outbox[messageID]
+	if !ok {
+		return nil
+	}
+
+	message.LastError = null.StringFrom(reason)
+	message.AvailableAt = retryAt
+	message.LockedUntil = null.Time{}
+	o.store.outbox[messageID] = message
+
+	return nil
+}
+
+func (o outboxRepo) MarkMessageDeadLettered(ctx context.Context, messageID string, reason string) error {
+	o.store.mu.Lock()
+	defer o.store.mu.Unlock()
+
+	message, ok := o.store.outbox[messageID]
+	if !ok {
+		return nil
+	}
+
+	message.LastError = null.StringFrom(reason)
+	message.DeadLetteredAt = null.TimeFrom(now())
+	message.LockedUntil = null.Time{}
+	o.store.outbox[messageID] = message
+
+	return nil
+}
+
+// enqueue must be called with the store's lock held. Like the postgres
+// outbox, messages whose idempotency key was already enqueued are ignored
+func (s *Store) enqueue(messages []datastore.OutboxMessage) {
+	timestamp := now()
+
+	for _, message := range messages {
+		duplicate := false
+		for _, existing := range s.outbox {
+			if existing.IdempotencyKey == message.IdempotencyKey {
+				duplicate = true
+				break
+			}
+		}
+
+		if duplicate {
+			continue
+		}
+
+		message.Attempts = 0
+		message.AvailableAt = timestamp
+		message.CreatedAt = timestamp
+		s.outbox[message.UID] = message
+	}
+}
@@ -0,0 +1,719 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type reelRepo struct {
+	store *Store
+}
+
+func NewReelRepo(store *Store) datastore.ReelRepository {
+	return &reelRepo{store: store}
+}
+
+func (r reelRepo) GetReelByID(ctx context.Context, id string) (*datastore.Reel, error) {
+	return r.findReel(func(reel datastore.Reel) bool { return reel.UID == id })
+}
+
+func (r reelRepo) GetReelByEmailConfirmationToken(ctx context.Context, token string) (*datastore.Reel, error) {
+	return r.findReel(func(reel datastore.Reel) bool { return reel.EmailConfirmationToken == token })
+}
+
+// matchesReelFilter checks a reel read through readReel against the filter
+func matchesReelFilter(reel datastore.Reel, filter datastore.ReelFilter) bool {
+	if filter.DeliveryStatus.IsValid() && reel.DeliveryStatus != filter.DeliveryStatus {
+		return false
+	}
+
+	if !inRange(reel.DeliveryDate, filter.DeliveryFrom, filter.DeliveryTo) || !inRange(reel.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+		return false
+	}
+
+	if filter.Private.Valid && reel.Private != filter.Private.Bool {
+		return false
+	}
+
+	if filter.HasRecipients.Valid && (len(reel.Recipients) > 0) != filter.HasRecipients.Bool {
+		return false
+	}
+
+	if filter.RecipientEmail != "" {
+		return slices.ContainsFunc(reel.Recipients, func(recipient datastore.Recipient) bool {
+			return strings.EqualFold(recipient.Email, filter.RecipientEmail)
+		})
+	}
+
+	return true
+}
+
+// searchWords splits text into lowercase words, dropping punctuation
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchRank is a rough stand in for postgres full text search: every word of the query
+// must be in the title or description, and title matches count for more. There's no
+// stemming, stop words or operators
+func searchRank(reel datastore.Reel, query string) (float32, bool) {
+	terms := searchWords(query)
+	if len(terms) == 0 {
+		return 0, false
+	}
+
+	title, description := searchWords(reel.Title), searchWords(reel.Description)
+
+	var rank float32
+	for _, term := range terms {
+		switch {
+		case slices.Contains(title, term):
+			rank += 1
+		case slices.Contains(description, term):
+			rank += 0.4
+		default:
+			return 0, false
+		}
+	}
+
+	return rank, true
+}
+
+// inRange reports whether t is at or after from and before to, a null bound is open
+func inRange(t time.Time, from, to null.Time) bool {
+	return (!from.Valid || !t.Before(from.Time)) && (!to.Valid || t.Before(to.Time))
+}
+
+// compareReels orders reels by sort, breaking ties on id
+func compareReels(sort datastore.Sort, a, b datastore.Reel) int {
+	var c int
+	switch sort.Field {
+	case datastore.ReelSortCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case datastore.ReelSortUpdatedAt:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	case datastore.ReelSortDeliveryDate:
+		c = a.DeliveryDate.Compare(b.DeliveryDate)
+	case datastore.ReelSortTitle:
+		// ignoring case, byte by byte like postgres sorts lower(title) COLLATE "C"
+		c = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case datastore.ReelSortRelevance:
+		c = cmp.Compare(a.SearchRank, b.SearchRank)
+	}
+
+	if c == 0 {
+		c = strings.Compare(a.UID, b.UID)
+	}
+
+	if sort.Order == datastore.SortDesc {
+		c = -c
+	}
+	return c
+}
+
+// cursorReel builds a reel holding just the cursor's sort key, to compare reels against
+func cursorReel(cursor datastore.Cursor) (datastore.Reel, error) {
+	reel := datastore.Reel{UID: cursor.ID}
+	switch cursor.Sort.Field {
+	case datastore.ReelSortTitle:
+		reel.Title = cursor.Value
+		return reel, nil
+	case datastore.ReelSortRelevance:
+		rank, err := cursor.Float()
+		reel.SearchRank = rank
+		return reel, err
+	}
+
+	value, err := cursor.Time()
+	if err != nil {
+		return reel, err
+	}
+	reel.CreatedAt, reel.UpdatedAt, reel.DeliveryDate = value, value, value
+
+	return reel, nil
+}
+
+func (r reelRepo) GetReelsPaged(ctx context.Context, userID string, filter datastore.ReelFilter, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
+	sort, err := datastore.ReelSort(pageable.Sort, filter)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	cursor, err := pageable.Position(sort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	// a previous page is fetched in reverse from the cursor
+	pageSort := sort
+	if cursor.Direction == datastore.PrevPage {
+		pageSort = sort.Reverse()
+	}
+
+	var from *datastore.Reel
+	if cursor.ID != "" {
+		reel, err := cursorReel(cursor)
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+		from = &reel
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reels []datastore.Reel
+	var hasBehind bool
+	for _, reel := range r.store.reels {
+		if reel.DeletedAt.Valid || reel.UserID.String != userID {
+			continue
+		}
+
+		reel = readReel(reel)
+		if !matchesReelFilter(reel, filter) {
+			continue
+		}
+
+		if filter.Query != "" {
+			rank, ok := searchRank(reel, filter.Query)
+			if !ok {
+				continue
+			}
+			reel.SearchRank = rank
+		}
+
+		if from != nil && compareReels(pageSort, reel, *from) <= 0 {
+			hasBehind = true
+			continue
+		}
+
+		reels = append(reels, reel)
+	}
+
+	slices.SortFunc(reels, func(a, b datastore.Reel) int { return compareReels(pageSort, a, b) })
+
+	if len(reels) > pageable.Limit() {
+		reels = reels[:pageable.Limit()]
+	}
+
+	positions := make([]datastore.Cursor, len(reels))
+	for i := range reels {
+		positions[i] = datastore.ReelPosition(reels[i], sort)
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	reels = reels[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(reels)
+	}
+
+	return reels, pagination, nil
+}
+
+func (r reelRepo) GetReelsDueForReminder(ctx context.Context, daysBefore int, limit int) ([]datastore.Reel, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	timestamp := now()
+	windowEnd := timestamp.AddDate(0, 0, daysBefore)
+
+	var reels []datastore.Reel
+	for _, reel := range r.store.reels {
+		if reel.DeletedAt.Valid || reel.SuppressReminders || reel.DeliveryStatus != datastore.ScheduledReelStatus {
+			continue
+		}
+
+		if !reel.DeliveryDate.After(timestamp) || reel.DeliveryDate.After(windowEnd) {
+			continue
+		}
+
+		if _, sent := r.store.reminders[reminderKey{reelID: reel.UID, daysBefore: daysBefore}]; sent {
+			continue
+		}
+
+		reels = append(reels, readReel(reel))
+	}
+
+	sort.Slice(reels, func(i, j int) bool { return reels[i].DeliveryDate.Before(reels[j].DeliveryDate) })
+
+	if len(reels) > limit {
+		reels = reels[:limit]
+	}
+
+	return reels, nil
+}
+
+func (r reelRepo) RecordReminders(ctx context.Context, reelID string, daysBefore []int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	timestamp := now()
+	for _, days := range daysBefore {
+		key := reminderKey{reelID: reelID, daysBefore: days}
+
+		if _, sent := r.store.reminders[key]; !sent {
+			r.store.reminders[key] = timestamp
+		}
+	}
+
+	return nil
+}
+
+func (r reelRepo) CreateReel(ctx context.Context, reel *datastore.Reel, messages ...datastore.OutboxMessage) error {
+	if hasDuplicateRecipients(nil, reel.Recipients) {
+		return violation(datastore.ErrDuplicateRecipient, datastore.ErrDuplicate)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkReferences(*reel); err != nil {
+		return err
+	}
+
+	timestamp := now()
+	reel.CreatedAt = timestamp
+	reel.UpdatedAt = timestamp
+	reel.DeletedAt = null.Time{}
+	reel.Version = 1
+
+	stored := *reel
+	stored.Recipients = append(datastore.Recipients(nil), reel.Recipients...)
+	r.store.reels[reel.UID] = stored
+
+	*reel = readReel(stored)
+	r.store.recordReelAudit(ctx, reel.UID, datastore.ReelCreatedAction, datastore.NewReelChanges(*reel))
+	r.store.enqueue(messages)
+
+	return nil
+}
+
+func (r reelRepo) UpdateReel(ctx context.Context, reel *datastore.Reel, messages ...datastore.OutboxMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reels[reel.UID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrReelNotUpdated
+	}
+
+	if existing.Version != reel.Version {
+		return datastore.ErrVersionConflict
+	}
+
+	if err := r.checkReferences(*reel); err != nil {
+		return err
+	}
+
+	before := existing
+
+	existing.UserID = reel.UserID
+	existing.VideoID = reel.VideoID
+	existing.Email = reel.Email
+	existing.Title = reel.Title
+	existing.Description = reel.Description
+	existing.Private = reel.Private
+	existing.DeliveryStatus = reel.DeliveryStatus
+	existing.DeliveryDate = reel.DeliveryDate
+	existing.EmailConfirmationToken = reel.EmailConfirmationToken
+	existing.SuppressReminders = reel.SuppressReminders
+	existing.ContributionDeadline = reel.ContributionDeadline
+	existing.UpdatedAt = now()
+	existing.Version++
+
+	r.store.reels[reel.UID] = existing
+	if changes := datastore.DiffReels(before, existing); len(changes) > 0 {
+		r.store.recordReelAudit(ctx, reel.UID, datastore.ReelUpdatedAction, changes)
+	}
+	r.store.enqueue(messages)
+
+	reel.Version = existing.Version
+	reel.UpdatedAt = existing.UpdatedAt
+
+	return nil
+}
+
+func (r reelRepo) AssignReelsToUserByEmail(ctx context.Context, email string, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, reel := range r.store.reels {
+		if reel.UserID.Valid || reel.Email != email || reel.DeletedAt.Valid {
+			continue
+		}
+
+		reel.UserID = null.StringFrom(userID)
+		reel.Version++
+		r.store.reels[id] = reel
+		r.store.recordReelAudit(ctx, id, datastore.ReelUpdatedAction, datastore.AuditChanges{"user_id": {To: userID}})
+	}
+
+	return nil
+}
+
+func (r reelRepo) AddRecipients(ctx context.Context, reel *datastore.Reel, newRecipients datastore.Recipients) error {
+	reel.Recipients = append(reel.Recipients, newRecipients...)
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reels[reel.UID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrReelRecipientsNotAdded
+	}
+
+	if hasDuplicateRecipients(existing.Recipients, newRecipients) {
+		return violation(datastore.ErrDuplicateRecipient, datastore.ErrDuplicate)
+	}
+
+	existing.Recipients = append(append(datastore.Recipients(nil), existing.Recipients...), newRecipients...)
+	existing.UpdatedAt = now()
+	existing.Version++
+
+	r.store.reels[reel.UID] = existing
+	r.store.recordReelAudit(ctx, reel.UID, datastore.RecipientsAddedAction, datastore.AuditChanges{"recipients": {To: datastore.RecipientEmails(newRecipients)}})
+	reel.Version = existing.Version
+
+	return nil
+}
+
+func (r reelRepo) DeleteRecipient(ctx context.Context, reel *datastore.Reel, recipientID string) error {
+	recipient := reel.FindRecipient(recipientID)
+	if recipient == nil {
+		return datastore.ErrRecipientNotFound
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reels[reel.UID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrReelRecipientNotDeleted
+	}
+
+	deleted := false
+	recipients := append(datastore.Recipients(nil), existing.Recipients...)
+	for i := range recipients {
+		if recipients[i].UID == recipientID && !recipients[i].DeletedAt.Valid {
+			recipients[i].DeletedAt = null.TimeFrom(now())
+			deleted = true
+		}
+	}
+
+	if !deleted {
+		return datastore.ErrReelRecipientNotDeleted
+	}
+
+	existing.Recipients = recipients
+	existing.UpdatedAt = now()
+	existing.Version++
+
+	r.store.reels[reel.UID] = existing
+	r.store.recordReelAudit(ctx, reel.UID, datastore.RecipientRemovedAction, datastore.AuditChanges{"recipients": {From: []string{recipient.Email}}})
+	reel.Version = existing.Version
+
+	return nil
+}
+
+func (r reelRepo) DeleteReel(ctx context.Context, reelID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reels[reelID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrReelNotDeleted
+	}
+
+	existing.DeletedAt = null.TimeFrom(now())
+	r.store.reels[reelID] = existing
+	r.store.recordReelAudit(ctx, reelID, datastore.ReelDeletedAction, nil)
+
+	return nil
+}
+
+func (r reelRepo) findReel(match func(datastore.Reel) bool) (*datastore.Reel, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, reel := range r.store.reels {
+		if !reel.DeletedAt.Valid && match(reel) {
+			found := readReel(reel)
+			return &found, nil
+		}
+	}
+
+	return nil, datastore.ErrReelNotFound
+}
+
+// checkReferences enforces the foreign keys and unique video of a reel being written,
+// the caller must hold the store's lock
+func (r reelRepo) checkReferences(reel datastore.Reel) error {
+	if _, ok := r.store.videos[reel.VideoID]; !ok {
+		return violation(datastore.ErrVideoNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if _, ok := r.store.users[reel.UserID.String]; reel.UserID.Valid && !ok {
+		return violation(datastore.ErrUserNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	for id, other := range r.store.reels {
+		if id != reel.UID && other.VideoID == reel.VideoID && !other.DeletedAt.Valid {
+			return violation(datastore.ErrReelVideoInUse, datastore.ErrDuplicate)
+		}
+	}
+
+	return nil
+}
+
+// hasDuplicateRecipients reports whether an email would be an active recipient of a reel more than once
+func hasDuplicateRecipients(existing datastore.Recipients, added datastore.Recipients) bool {
+	emails := map[string]bool{}
+
+	for _, recipient := range append(append(datastore.Recipients(nil), existing...), added...) {
+		if recipient.DeletedAt.Valid {
+			continue
+		}
+
+		// like the postgres unique index, emails are compared without case
+		email := strings.ToLower(recipient.Email)
+		if emails[email] {
+			return true
+		}
+		emails[email] = true
+	}
+
+	return false
+}
+
+// readReel returns a copy of a stored reel without its deleted recipients,
+// the same way datastore.Recipients scans them from the database
+func readReel(reel datastore.Reel) datastore.Reel {
+	var recipients datastore.Recipients
+	for _, recipient := range reel.Recipients {
+		if recipient.DeletedAt.IsZero() {
+			recipients = append(recipients, recipient)
+		}
+	}
+
+	reel.Recipients = recipients
+
+	return reel
+}
+
+// compareDeletedReels orders the trash most recently deleted first
+func compareDeletedReels(a, b datastore.Reel) int {
+	if c := b.DeletedAt.Time.Compare(a.DeletedAt.Time); c != 0 {
+		return c
+	}
+	return strings.Compare(b.UID, a.UID)
+}
+
+func (r reelRepo) GetDeletedReelsPaged(ctx context.Context, userID string, deletedAfter time.Time, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.TrashSort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	var from *datastore.Reel
+	if cursor.ID != "" {
+		deletedAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+		from = &datastore.Reel{UID: cursor.ID, DeletedAt: null.TimeFrom(deletedAt)}
+	}
+
+	// a previous page is fetched in reverse from the cursor
+	compare := compareDeletedReels
+	if cursor.Direction == datastore.PrevPage {
+		compare = func(a, b datastore.Reel) int { return compareDeletedReels(b, a) }
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reels []datastore.Reel
+	var hasBehind bool
+	for _, reel := range r.store.reels {
+		if !reel.DeletedAt.Valid || reel.UserID.String != userID || reel.DeletedAt.Time.Before(deletedAfter) {
+			continue
+		}
+
+		if from != nil && compare(reel, *from) <= 0 {
+			hasBehind = true
+			continue
+		}
+
+		reels = append(reels, readReel(reel))
+	}
+
+	slices.SortFunc(reels, compare)
+
+	if len(reels) > pageable.Limit() {
+		reels = reels[:pageable.Limit()]
+	}
+
+	positions := make([]datastore.Cursor, len(reels))
+	for i := range reels {
+		positions[i] = datastore.TrashPosition(reels[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	reels = reels[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(reels)
+	}
+
+	return reels, pagination, nil
+}
+
+func (r reelRepo) GetDeletedReelByID(ctx context.Context, reelID string) (*datastore.Reel, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reel, ok := r.store.reels[reelID]
+	if !ok || !reel.DeletedAt.Valid {
+		return nil, datastore.ErrReelNotFound
+	}
+
+	found := readReel(reel)
+	return &found, nil
+}
+
+func (r reelRepo) RestoreReel(ctx context.Context, reel *datastore.Reel, deletedAfter time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reels[reel.UID]
+	if !ok || !existing.DeletedAt.Valid || existing.DeletedAt.Time.Before(deletedAfter) {
+		return datastore.ErrReelNotRestored
+	}
+
+	for _, other := range r.store.reels {
+		if other.VideoID == existing.VideoID && !other.DeletedAt.Valid {
+			return violation(datastore.ErrReelVideoInUse, datastore.ErrDuplicate)
+		}
+	}
+
+	existing.DeletedAt = null.Time{}
+	existing.UpdatedAt = now()
+	existing.Version++
+	r.store.reels[reel.UID] = existing
+	r.store.recordReelAudit(ctx, reel.UID, datastore.ReelRestoredAction, nil)
+
+	reel.DeletedAt = null.Time{}
+	reel.UpdatedAt = existing.UpdatedAt
+	reel.Version = existing.Version
+
+	return nil
+}
+
+func (r reelRepo) PurgeDeletedReels(ctx context.Context, deletedBefore time.Time, limit int) (int, []datastore.Video, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []datastore.Reel
+	for _, reel := range r.store.reels {
+		if reel.DeletedAt.Valid && reel.DeletedAt.Time.Before(deletedBefore) {
+			expired = append(expired, reel)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].DeletedAt.Time.Before(expired[j].DeletedAt.Time) })
+
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	var videos []datastore.Video
+	deleteVideo := func(videoID string) {
+		if video, ok := r.store.videos[videoID]; ok {
+			videos = append(videos, video)
+			delete(r.store.videos, videoID)
+		}
+	}
+
+	for _, reel := range expired {
+		delete(r.store.reels, reel.UID)
+
+		for key := range r.store.reminders {
+			if key.reelID == reel.UID {
+				delete(r.store.reminders, key)
+			}
+		}
+
+		for key := range r.store.hidden {
+			if key.reelID == reel.UID {
+				delete(r.store.hidden, key)
+			}
+		}
+
+		for id, token := range r.store.viewTokens {
+			if token.ReelID == reel.UID {
+				delete(r.store.viewTokens, id)
+			}
+		}
+
+		for id, link := range r.store.shareLinks {
+			if link.ReelID == reel.UID {
+				delete(r.store.shareLinks, id)
+			}
+		}
+
+		// reply videos go along with their responses, as do contributed clips
+		for id, response := range r.store.responses {
+			if response.ReelID == reel.UID {
+				delete(r.store.responses, id)
+				if response.VideoID.Valid {
+					deleteVideo(response.VideoID.String)
+				}
+			}
+		}
+
+		for id, contribution := range r.store.contributions {
+			if contribution.ReelID == reel.UID {
+				delete(r.store.contributions, id)
+				deleteVideo(contribution.VideoID)
+			}
+		}
+
+		for id, contributor := range r.store.contributors {
+			if contributor.ReelID == reel.UID {
+				delete(r.store.contributors, id)
+			}
+		}
+	}
+
+	for _, reel := range expired {
+		referenced := false
+		for _, other := range r.store.reels {
+			if other.VideoID == reel.VideoID {
+				referenced = true
+				break
+			}
+		}
+
+		if !referenced {
+			deleteVideo(reel.VideoID)
+		}
+	}
+
+	// the audit log is kept, ending with the purge
+	for _, reel := range expired {
+		r.store.recordReelAudit(ctx, reel.UID, datastore.ReelPurgedAction, nil)
+	}
+
+	return len(expired), videos, nil
+}
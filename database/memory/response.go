@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type responseRepo struct {
+	store *Store
+}
+
+func NewResponseRepo(store *Store) datastore.ResponseRepository {
+	return &responseRepo{store: store}
+}
+
+func (r responseRepo) GetResponseByID(ctx context.Context, id string) (*datastore.ReelResponse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	response, ok := r.store.responses[id]
+	if !ok {
+		return nil, datastore.ErrResponseNotFound
+	}
+
+	return &response, nil
+}
+
+func (r responseRepo) GetResponsesByReel(ctx context.Context, reelID string, status datastore.ModerationStatus) ([]datastore.ReelResponse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	responses := []datastore.ReelResponse{}
+	for _, response := range r.store.responses {
+		if response.ReelID == reelID && response.ModerationStatus == status {
+			responses = append(responses, response)
+		}
+	}
+
+	slices.SortFunc(responses, func(a, b datastore.ReelResponse) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+
+	return responses, nil
+}
+
+func (r responseRepo) CreateResponse(ctx context.Context, response *datastore.ReelResponse, messages ...datastore.OutboxMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reels[response.ReelID]; !ok {
+		return violation(datastore.ErrReelNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if _, ok := r.store.videos[response.VideoID.String]; response.VideoID.Valid && !ok {
+		return violation(datastore.ErrVideoNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if (response.Kind == datastore.VideoResponse) != response.VideoID.Valid || !response.ModerationStatus.IsValid() {
+		return datastore.ErrConstraintViolation
+	}
+
+	if _, ok := r.store.responses[response.UID]; ok {
+		return datastore.ErrDuplicate
+	}
+
+	response.CreatedAt = now()
+	r.store.responses[response.UID] = *response
+	r.store.enqueue(messages)
+
+	return nil
+}
+
+func (r responseRepo) ModerateResponse(ctx context.Context, id string, status datastore.ModerationStatus, messages ...datastore.OutboxMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	response, ok := r.store.responses[id]
+	if !ok || response.ModerationStatus != datastore.PendingModeration {
+		return datastore.ErrResponseNotFound
+	}
+
+	if !status.IsValid() {
+		return datastore.ErrConstraintViolation
+	}
+
+	response.ModerationStatus = status
+	r.store.responses[id] = response
+	r.store.enqueue(messages)
+
+	return nil
+}
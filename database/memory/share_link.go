@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type shareLinkRepo struct {
+	store *Store
+}
+
+func NewShareLinkRepo(store *Store) datastore.ShareLinkRepository {
+	return &shareLinkRepo{store: store}
+}
+
+func (s shareLinkRepo) GetShareLinkByID(ctx context.Context, id string) (*datastore.ShareLink, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	link, ok := s.store.shareLinks[id]
+	if !ok {
+		return nil, datastore.ErrShareLinkNotFound
+	}
+
+	return &link, nil
+}
+
+func (s shareLinkRepo) GetShareLinksByReel(ctx context.Context, reelID string) ([]datastore.ShareLink, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	links := []datastore.ShareLink{}
+	for _, link := range s.store.shareLinks {
+		if link.ReelID == reelID {
+			links = append(links, link)
+		}
+	}
+
+	// latest first
+	slices.SortFunc(links, func(a, b datastore.ShareLink) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.UID, a.UID)
+	})
+
+	return links, nil
+}
+
+func (s shareLinkRepo) CreateShareLink(ctx context.Context, link *datastore.ShareLink) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.reels[link.ReelID]; !ok {
+		return violation(datastore.ErrReelNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	if _, ok := s.store.shareLinks[link.UID]; ok {
+		return datastore.ErrDuplicate
+	}
+
+	link.Views = 0
+	link.RevokedAt = null.Time{}
+	link.FailedPasswordAttempts = 0
+	link.LockedUntil = null.Time{}
+	link.CreatedAt = now()
+	s.store.shareLinks[link.UID] = *link
+
+	return nil
+}
+
+func (s shareLinkRepo) RecordShareLinkView(ctx context.Context, id string) (*datastore.ShareLink, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	link, ok := s.store.shareLinks[id]
+	if !ok || !link.Active(now()) {
+		return nil, datastore.ErrShareLinkNotFound
+	}
+
+	link.Views++
+	link.FailedPasswordAttempts = 0
+	s.store.shareLinks[id] = link
+
+	return &link, nil
+}
+
+func (s shareLinkRepo) RecordSharePasswordFailure(ctx context.Context, id string, maxAttempts int, lockout time.Duration) (*datastore.ShareLink, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	link, ok := s.store.shareLinks[id]
+	if !ok {
+		return nil, datastore.ErrShareLinkNotFound
+	}
+
+	link.FailedPasswordAttempts++
+	if link.FailedPasswordAttempts >= maxAttempts {
+		link.FailedPasswordAttempts = 0
+		link.LockedUntil = null.TimeFrom(now().Add(lockout))
+	}
+	s.store.shareLinks[id] = link
+
+	return &link, nil
+}
+
+func (s shareLinkRepo) RevokeShareLink(ctx context.Context, reelID string, id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	link, ok := s.store.shareLinks[id]
+	if !ok || link.ReelID != reelID {
+		return datastore.ErrShareLinkNotFound
+	}
+
+	if !link.RevokedAt.Valid {
+		link.RevokedAt = null.TimeFrom(now())
+		s.store.shareLinks[id] = link
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+func (r reelRepo) GetReelStats(ctx context.Context, userID string) (*datastore.ReelStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byStatus := map[datastore.ReelDeliveryStatus]int{}
+	videos := map[string]bool{}
+	var recipients int
+
+	for _, reel := range r.store.reels {
+		if reel.DeletedAt.Valid || reel.UserID.String != userID {
+			continue
+		}
+
+		byStatus[reel.DeliveryStatus]++
+		recipients += len(readReel(reel).Recipients)
+		videos[reel.VideoID] = true
+	}
+
+	var storageMB float64
+	for videoID := range videos {
+		if video, ok := r.store.videos[videoID]; ok && !video.DeletedAt.Valid {
+			storageMB += float64(video.SizeMB)
+		}
+	}
+
+	return datastore.NewReelStats(byStatus, recipients, storageMB), nil
+}
+
+func (r reelRepo) GetDeliveryTimeline(ctx context.Context, userID string, from time.Time, months int) ([]datastore.MonthlyDeliveries, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	until := datastore.TimelineWindow(from, months)
+
+	counts := map[string]int{}
+	for _, reel := range r.store.reels {
+		if reel.DeletedAt.Valid || reel.UserID.String != userID || reel.DeliveryStatus != datastore.ScheduledReelStatus {
+			continue
+		}
+
+		if reel.DeliveryDate.Before(from) || !reel.DeliveryDate.Before(until) {
+			continue
+		}
+
+		counts[reel.DeliveryDate.UTC().Format(datastore.TimelineMonthFormat)]++
+	}
+
+	return datastore.NewDeliveryTimeline(from, months, counts), nil
+}
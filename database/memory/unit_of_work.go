@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+)
+
+type unitOfWork struct {
+	store *Store
+	// transactions are serialized, each one works on its own copy of the store
+	mu sync.Mutex
+}
+
+// NewUnitOfWork runs transactions against a copy of the store that replaces it on commit.
+// Writes made through the store's other repositories while a transaction is running are
+// overwritten when it commits, which is fine for tests but not for concurrent use
+func NewUnitOfWork(store *Store) datastore.UnitOfWork {
+	return &unitOfWork{store: store}
+}
+
+func (u *unitOfWork) RunInTx(ctx context.Context, fn func(repos datastore.Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	tx := u.store.clone()
+
+	// a panic unwinds past the commit below, leaving the store untouched
+	if err := fn(NewRepositories(tx)); err != nil {
+		return err
+	}
+
+	u.store.replace(tx)
+
+	return nil
+}
+
+// NewRepositories returns every repository backed by the store
+func NewRepositories(store *Store) datastore.Repositories {
+	return datastore.Repositories{
+		Users:         NewUserRepo(store),
+		Reels:         NewReelRepo(store),
+		Videos:        NewVideoRepo(store),
+		Outbox:        NewOutboxRepo(store),
+		ViewTokens:    NewViewTokenRepo(store),
+		Audit:         NewAuditRepo(store),
+		Inbox:         NewInboxRepo(store),
+		Gallery:       NewGalleryRepo(store),
+		Shares:        NewShareLinkRepo(store),
+		Responses:     NewResponseRepo(store),
+		Contributions: NewContributionRepo(store),
+	}
+}
+
+func (s *Store) clone() *Store {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &Store{
+		users:         maps.Clone(s.users),
+		videos:        maps.Clone(s.videos),
+		reels:         maps.Clone(s.reels),
+		reminders:     maps.Clone(s.reminders),
+		outbox:        maps.Clone(s.outbox),
+		audit:         slices.Clone(s.audit),
+		hidden:        maps.Clone(s.hidden),
+		viewTokens:    maps.Clone(s.viewTokens),
+		shareLinks:    maps.Clone(s.shareLinks),
+		responses:     maps.Clone(s.responses),
+		contributors:  maps.Clone(s.contributors),
+		contributions: maps.Clone(s.contributions),
+	}
+}
+
+func (s *Store) replace(other *Store) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = other.users
+	s.videos = other.videos
+	s.reels = other.reels
+	s.reminders = other.reminders
+	s.outbox = other.outbox
+	s.audit = other.audit
+	s.hidden = other.hidden
+	s.viewTokens = other.viewTokens
+	s.shareLinks = other.shareLinks
+	s.responses = other.responses
+	s.contributors = other.contributors
+	s.contributions = other.contributions
+}
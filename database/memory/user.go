@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type userRepo struct {
+	store *Store
+}
+
+func NewUserRepo(store *Store) datastore.UserRepository {
+	return &userRepo{store: store}
+}
+
+func (u userRepo) GetUserByID(ctx context.Context, userID string) (*datastore.User, error) {
+	return u.findUser(func(user datastore.User) bool { return user.UID == userID })
+}
+
+func (u userRepo) GetUserByEmail(ctx context.Context, email string) (*datastore.User, error) {
+	return u.findUser(func(user datastore.User) bool { return user.Email == email })
+}
+
+func (u userRepo) GetUserByEmailVerificationToken(ctx context.Context, token string) (*datastore.User, error) {
+	return u.findUser(func(user datastore.User) bool { return user.EmailVerificationToken == token })
+}
+
+func (u userRepo) GetUserByResetPasswordToken(ctx context.Context, token string) (*datastore.User, error) {
+	return u.findUser(func(user datastore.User) bool { return user.ResetPasswordToken == token })
+}
+
+func (u userRepo) GetUserByCalendarToken(ctx context.Context, token string) (*datastore.User, error) {
+	return u.findUser(func(user datastore.User) bool { return user.CalendarToken.Valid && user.CalendarToken.String == token })
+}
+
+func (u userRepo) CreateUser(ctx context.Context, user *datastore.User, messages ...datastore.OutboxMessage) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	for _, existing := range u.store.users {
+		if existing.Email == user.Email && !existing.DeletedAt.Valid {
+			return violation(datastore.ErrDuplicateUserEmail, datastore.ErrDuplicate)
+		}
+	}
+
+	timestamp := now()
+	user.CreatedAt = timestamp
+	user.UpdatedAt = timestamp
+	user.DeletedAt = null.Time{}
+	user.Version = 1
+
+	u.store.users[user.UID] = *user
+	u.store.enqueue(messages)
+
+	return nil
+}
+
+func (u userRepo) UpdateUser(ctx context.Context, user *datastore.User, messages ...datastore.OutboxMessage) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	existing, ok := u.store.users[user.UID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrUserNotUpdated
+	}
+
+	if existing.Version != user.Version {
+		return datastore.ErrVersionConflict
+	}
+
+	for id, other := range u.store.users {
+		if id != user.UID && other.Email == user.Email && !other.DeletedAt.Valid {
+			return violation(datastore.ErrDuplicateUserEmail, datastore.ErrDuplicate)
+		}
+	}
+
+	existing.Firstname = user.Firstname
+	existing.Lastname = user.Lastname
+	existing.Email = user.Email
+	existing.Password = user.Password
+	existing.EmailVerified = user.EmailVerified
+	existing.ResetPasswordToken = user.ResetPasswordToken
+	existing.EmailVerificationToken = user.EmailVerificationToken
+	existing.ResetPasswordExpiresAt = user.ResetPasswordExpiresAt
+	existing.EmailVerificationExpiresAt = user.EmailVerificationExpiresAt
+	existing.CalendarToken = user.CalendarToken
+	existing.UpdatedAt = now()
+	existing.Version++
+
+	u.store.users[user.UID] = existing
+	u.store.enqueue(messages)
+
+	user.Version = existing.Version
+	user.UpdatedAt = existing.UpdatedAt
+
+	return nil
+}
+
+func (u userRepo) DeleteUser(ctx context.Context, userID string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	existing, ok := u.store.users[userID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrUserNotDeleted
+	}
+
+	existing.DeletedAt = null.TimeFrom(now())
+	u.store.users[userID] = existing
+
+	return nil
+}
+
+func (u userRepo) findUser(match func(datastore.User) bool) (*datastore.User, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	for _, user := range u.store.users {
+		if !user.DeletedAt.Valid && match(user) {
+			return &user, nil
+		}
+	}
+
+	return nil, datastore.ErrUserNotFound
+}
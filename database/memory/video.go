@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type videoRepo struct {
+	store *Store
+}
+
+func NewVideoRepo(store *Store) datastore.VideoRepository {
+	return &videoRepo{store: store}
+}
+
+func (v videoRepo) GetVideoByID(ctx context.Context, id string) (*datastore.Video, error) {
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	video, ok := v.store.videos[id]
+	if !ok || video.DeletedAt.Valid {
+		return nil, datastore.ErrVideoNotFound
+	}
+
+	return &video, nil
+}
+
+func (v videoRepo) GetVideosPendingPackaging(ctx context.Context, limit int, retryAfter time.Duration) ([]datastore.Video, error) {
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	retryFrom := now().Add(-retryAfter)
+
+	var videos []datastore.Video
+	for _, video := range v.store.videos {
+		if video.HLSMasterKey.Valid || video.DeletedAt.Valid || video.PackagingAttempts >= datastore.MaxPackagingAttempts {
+			continue
+		}
+
+		if video.PackagingFailedAt.Valid && video.PackagingFailedAt.Time.After(retryFrom) {
+			continue
+		}
+
+		videos = append(videos, video)
+	}
+
+	sort.Slice(videos, func(i, j int) bool {
+		if videos[i].PackagingAttempts != videos[j].PackagingAttempts {
+			return videos[i].PackagingAttempts < videos[j].PackagingAttempts
+		}
+		return videos[i].UID < videos[j].UID
+	})
+
+	if len(videos) > limit {
+		videos = videos[:limit]
+	}
+
+	return videos, nil
+}
+
+func (v videoRepo) CreateVideo(ctx context.Context, video *datastore.Video) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	timestamp := now()
+	video.HLSMasterKey = null.String{}
+	video.PackagingAttempts = 0
+	video.PackagingFailedAt = null.Time{}
+	video.CreatedAt = timestamp
+	video.UpdatedAt = timestamp
+	video.DeletedAt = null.Time{}
+
+	v.store.videos[video.UID] = *video
+
+	return nil
+}
+
+func (v videoRepo) UpdateVideo(ctx context.Context, video *datastore.Video) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	existing, ok := v.store.videos[video.UID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrVideoNotUpdated
+	}
+
+	existing.Key = video.Key
+	existing.FileFormat = video.FileFormat
+	existing.SizeMB = video.SizeMB
+	existing.HLSMasterKey = video.HLSMasterKey
+	existing.UpdatedAt = now()
+
+	v.store.videos[video.UID] = existing
+
+	return nil
+}
+
+func (v videoRepo) RecordPackagingFailure(ctx context.Context, videoID string) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	existing, ok := v.store.videos[videoID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrVideoNotUpdated
+	}
+
+	existing.PackagingAttempts++
+	existing.PackagingFailedAt = null.TimeFrom(now())
+	v.store.videos[videoID] = existing
+
+	return nil
+}
+
+func (v videoRepo) DeleteVideo(ctx context.Context, videoID string) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	existing, ok := v.store.videos[videoID]
+	if !ok || existing.DeletedAt.Valid {
+		return datastore.ErrVideoNotDeleted
+	}
+
+	existing.DeletedAt = null.TimeFrom(now())
+	v.store.videos[videoID] = existing
+
+	return nil
+}
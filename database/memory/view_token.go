@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+type viewTokenRepo struct {
+	store *Store
+}
+
+func NewViewTokenRepo(store *Store) datastore.ViewTokenRepository {
+	return &viewTokenRepo{store: store}
+}
+
+// GetViewTokenByID only returns tokens that have neither expired nor been revoked
+func (v viewTokenRepo) GetViewTokenByID(ctx context.Context, id string) (*datastore.ViewToken, error) {
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	token, ok := v.store.viewTokens[id]
+	if !ok || token.RevokedAt.Valid || !token.ExpiresAt.After(now()) {
+		return nil, datastore.ErrViewTokenNotFound
+	}
+
+	return &token, nil
+}
+
+func (v viewTokenRepo) CreateViewToken(ctx context.Context, token *datastore.ViewToken) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	if _, ok := v.store.reels[token.ReelID]; !ok {
+		return violation(datastore.ErrReelNotFound, datastore.ErrReferenceNotFound)
+	}
+
+	token.RevokedAt = null.Time{}
+	token.CreatedAt = now()
+	v.store.viewTokens[token.UID] = *token
+
+	return nil
+}
+
+func (v viewTokenRepo) RevokeViewTokens(ctx context.Context, reelID string, recipientID string) error {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	timestamp := now()
+	for id, token := range v.store.viewTokens {
+		if token.ReelID == reelID && token.RecipientID == recipientID && !token.RevokedAt.Valid {
+			token.RevokedAt = null.TimeFrom(timestamp)
+			v.store.viewTokens[id] = token
+		}
+	}
+
+	return nil
+}
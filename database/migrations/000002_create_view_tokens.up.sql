@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "view_tokens" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"recipient_id" CHAR(26) NOT NULL,
+	"expires_at" TIMESTAMPTZ NOT NULL,
+	"revoked_at" TIMESTAMPTZ,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW())
+);
+
+CREATE INDEX IF NOT EXISTS view_tokens_reel_recipient_idx ON view_tokens (reel_id, recipient_id);
@@ -0,0 +1 @@
+ALTER TABLE "videos" DROP COLUMN IF EXISTS "hls_master_key";
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "reel_reminders";
+ALTER TABLE "reels" DROP COLUMN IF EXISTS "suppress_reminders";
@@ -0,0 +1,9 @@
+ALTER TABLE "reels" ADD COLUMN IF NOT EXISTS "suppress_reminders" BOOLEAN NOT NULL DEFAULT(false);
+
+CREATE TABLE IF NOT EXISTS "reel_reminders" (
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"days_before" INTEGER NOT NULL,
+	"sent_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+
+	PRIMARY KEY (reel_id, days_before)
+);
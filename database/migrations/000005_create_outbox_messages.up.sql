@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "outbox_messages" (
+	"id" CHAR(26) PRIMARY KEY,
+	"idempotency_key" VARCHAR(255) NOT NULL,
+	"topic" VARCHAR(255) NOT NULL,
+	"payload" JSONB NOT NULL,
+	"attempts" INTEGER NOT NULL DEFAULT(0),
+	"available_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+	"locked_until" TIMESTAMPTZ,
+	"dispatched_at" TIMESTAMPTZ,
+	"last_error" TEXT,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+
+	CONSTRAINT outbox_messages_idempotency_key UNIQUE (idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (available_at) WHERE dispatched_at IS NULL;
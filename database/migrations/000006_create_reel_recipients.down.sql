@@ -0,0 +1,16 @@
+ALTER TABLE "reels" ADD COLUMN IF NOT EXISTS "recipients" JSONB NOT NULL DEFAULT('[]');
+
+UPDATE reels SET recipients = COALESCE((
+	SELECT jsonb_agg(jsonb_build_object(
+		'uid', rr.id,
+		'email', rr.email,
+		'created_at', rr.created_at,
+		'deleted_at', rr.deleted_at
+	) ORDER BY rr.created_at, rr.id)
+	FROM reel_recipients rr
+	WHERE rr.reel_id = reels.id
+), '[]');
+
+ALTER TABLE "reels" ALTER COLUMN "recipients" DROP DEFAULT;
+
+DROP TABLE IF EXISTS "reel_recipients";
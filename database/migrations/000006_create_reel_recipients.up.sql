@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS "reel_recipients" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"email" VARCHAR(255) NOT NULL,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+	"deleted_at" TIMESTAMPTZ
+);
+
+-- an email can only be an active recipient of a reel once, regardless of case
+CREATE UNIQUE INDEX IF NOT EXISTS reel_recipients_reel_email_key ON reel_recipients (reel_id, lower(email)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reel_recipients_email_idx ON reel_recipients (lower(email));
+
+-- move recipients out of the jsonb array keeping their ids and deletion times.
+-- Duplicate active emails on the same reel, compared without case, keep the first one added active and
+-- the rest are moved over as deleted, so nothing in the array is lost
+CREATE TEMPORARY TABLE reel_recipients_backfill AS
+SELECT
+	r->>'uid' AS id,
+	reels.id AS reel_id,
+	r->>'email' AS email,
+	COALESCE((r->>'created_at')::TIMESTAMPTZ, reels.created_at) AS created_at,
+	(r->>'deleted_at')::TIMESTAMPTZ AS deleted_at,
+	CASE WHEN r->>'deleted_at' IS NULL THEN
+		ROW_NUMBER() OVER (PARTITION BY reels.id, lower(r->>'email'), r->>'deleted_at' IS NULL ORDER BY position)
+	END AS active_rank
+FROM reels, jsonb_array_elements(reels.recipients) WITH ORDINALITY AS elements(r, position);
+
+DO $$
+DECLARE
+	duplicate RECORD;
+BEGIN
+	FOR duplicate IN SELECT id, reel_id, email FROM reel_recipients_backfill WHERE active_rank > 1 ORDER BY reel_id, id LOOP
+		RAISE WARNING 'reel % has duplicate recipient % (%), it is kept as deleted', duplicate.reel_id, duplicate.email, duplicate.id;
+	END LOOP;
+END $$;
+
+INSERT INTO reel_recipients (id, reel_id, email, created_at, deleted_at)
+SELECT
+	id,
+	reel_id,
+	email,
+	created_at,
+	CASE WHEN active_rank > 1 THEN NOW() ELSE deleted_at END
+FROM reel_recipients_backfill;
+
+DROP TABLE reel_recipients_backfill;
+
+ALTER TABLE "reels" DROP COLUMN IF EXISTS "recipients";
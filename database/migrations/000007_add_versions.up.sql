@@ -0,0 +1,2 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "version" INTEGER NOT NULL DEFAULT(1);
+ALTER TABLE "reels" ADD COLUMN IF NOT EXISTS "version" INTEGER NOT NULL DEFAULT(1);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "reel_audit_entries" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"actor_id" CHAR(26),
+	"action" VARCHAR(64) NOT NULL,
+	"changes" JSONB NOT NULL DEFAULT('{}'),
+	"request_id" VARCHAR(255),
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW())
+);
+
+CREATE INDEX IF NOT EXISTS reel_audit_entries_reel_idx ON reel_audit_entries (reel_id, created_at);
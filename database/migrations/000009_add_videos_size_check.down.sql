@@ -0,0 +1 @@
+ALTER TABLE "videos" DROP CONSTRAINT IF EXISTS videos_size_mb_check;
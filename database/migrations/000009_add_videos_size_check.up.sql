@@ -0,0 +1,2 @@
+-- not valid skips checking existing rows, new and updated ones must pass
+ALTER TABLE "videos" ADD CONSTRAINT videos_size_mb_check CHECK (size_mb >= 0) NOT VALID;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS reels_user_title_idx;
+DROP INDEX IF EXISTS reels_user_delivery_date_idx;
+DROP INDEX IF EXISTS reels_user_updated_at_idx;
+DROP INDEX IF EXISTS reels_user_created_at_idx;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS reels_user_created_at_idx ON reels (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reels_user_updated_at_idx ON reels (user_id, updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reels_user_delivery_date_idx ON reels (user_id, delivery_date, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reels_user_title_idx ON reels (user_id, title, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS reels_search_vector_idx;
+ALTER TABLE "reels" DROP COLUMN IF EXISTS "search_vector";
@@ -0,0 +1,8 @@
+-- titles weigh more than descriptions when ranking search results
+ALTER TABLE "reels" ADD COLUMN IF NOT EXISTS "search_vector" TSVECTOR
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', title), 'A') ||
+		setweight(to_tsvector('english', description), 'B')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS reels_search_vector_idx ON reels USING GIN (search_vector);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS users_calendar_token_key;
+ALTER TABLE "users" DROP COLUMN IF EXISTS "calendar_token";
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "calendar_token" VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_calendar_token_key ON users (calendar_token);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS reel_recipients_lower_email_idx;
+DROP TABLE IF EXISTS "inbox_hidden_reels";
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "inbox_hidden_reels" (
+	"user_id" CHAR(26) NOT NULL REFERENCES users(id),
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"hidden_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+
+	PRIMARY KEY (user_id, reel_id)
+);
+
+-- inboxes look recipients up by email regardless of case
+CREATE INDEX IF NOT EXISTS reel_recipients_lower_email_idx ON reel_recipients (lower(email)) WHERE deleted_at IS NULL;
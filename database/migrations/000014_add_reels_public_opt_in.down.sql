@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS reels_gallery_idx;
+
+ALTER TABLE reels DROP COLUMN IF EXISTS "public_opt_in_at";
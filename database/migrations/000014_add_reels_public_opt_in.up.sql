@@ -0,0 +1,5 @@
+ALTER TABLE reels ADD COLUMN IF NOT EXISTS "public_opt_in_at" TIMESTAMPTZ;
+
+-- the public gallery lists opted in reels latest delivery first
+CREATE INDEX IF NOT EXISTS reels_gallery_idx ON reels (delivery_date, id)
+WHERE deleted_at IS NULL AND delivery_status = 'delivered' AND private = false AND public_opt_in_at IS NOT NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "share_links" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"password_hash" TEXT,
+	"expires_at" TIMESTAMPTZ,
+	"max_views" INTEGER CHECK (max_views > 0),
+	"views" INTEGER NOT NULL DEFAULT 0,
+	"revoked_at" TIMESTAMPTZ,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW())
+);
+
+CREATE INDEX IF NOT EXISTS share_links_reel_idx ON share_links (reel_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "reel_responses" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"recipient_id" CHAR(26) NOT NULL,
+	"kind" TEXT NOT NULL CHECK (kind IN ('reaction', 'note', 'video')),
+	"body" TEXT NOT NULL DEFAULT '',
+	"video_id" CHAR(26) REFERENCES videos(id),
+	"moderation_status" TEXT NOT NULL CHECK (moderation_status IN ('pending', 'approved', 'rejected')),
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+
+	-- only reply videos, and all of them, have a video
+	CONSTRAINT reel_responses_video_check CHECK ((kind = 'video') = (video_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS reel_responses_reel_idx ON reel_responses (reel_id, moderation_status, created_at);
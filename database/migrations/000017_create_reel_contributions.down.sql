@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "reel_contributions";
+DROP TABLE IF EXISTS "reel_contributors";
+
+ALTER TABLE reels DROP COLUMN IF EXISTS "contribution_deadline";
@@ -0,0 +1,25 @@
+ALTER TABLE reels ADD COLUMN IF NOT EXISTS "contribution_deadline" TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS "reel_contributors" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"email" VARCHAR(255) NOT NULL,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+	"removed_at" TIMESTAMPTZ
+);
+
+-- an email can only be invited to a reel once, until it's removed
+CREATE UNIQUE INDEX IF NOT EXISTS reel_contributors_reel_email_key ON reel_contributors (reel_id, lower(email)) WHERE removed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS "reel_contributions" (
+	"id" CHAR(26) PRIMARY KEY,
+	"reel_id" CHAR(26) NOT NULL REFERENCES reels(id),
+	"contributor_id" CHAR(26) NOT NULL REFERENCES reel_contributors(id),
+	"video_id" CHAR(26) NOT NULL REFERENCES videos(id),
+	"status" TEXT NOT NULL CHECK (status IN ('pending', 'approved', 'rejected')),
+	"position" INTEGER NOT NULL,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW()),
+	"updated_at" TIMESTAMPTZ NOT NULL DEFAULT(NOW())
+);
+
+CREATE INDEX IF NOT EXISTS reel_contributions_reel_idx ON reel_contributions (reel_id, position);
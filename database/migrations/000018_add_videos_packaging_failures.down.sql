@@ -0,0 +1,2 @@
+ALTER TABLE "videos" DROP COLUMN IF EXISTS "packaging_failed_at";
+ALTER TABLE "videos" DROP COLUMN IF EXISTS "packaging_attempts";
@@ -0,0 +1,3 @@
+-- videos that fail to package are retried with a back off and given up on after a few attempts
+ALTER TABLE "videos" ADD COLUMN IF NOT EXISTS "packaging_attempts" INTEGER NOT NULL DEFAULT(0);
+ALTER TABLE "videos" ADD COLUMN IF NOT EXISTS "packaging_failed_at" TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS outbox_messages_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (available_at) WHERE dispatched_at IS NULL;
+
+ALTER TABLE "outbox_messages" DROP COLUMN IF EXISTS "dead_lettered_at";
@@ -0,0 +1,5 @@
+-- messages that can never be dispatched, like ones for an unknown topic, are set aside instead of retried
+ALTER TABLE "outbox_messages" ADD COLUMN IF NOT EXISTS "dead_lettered_at" TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS outbox_messages_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (available_at) WHERE dispatched_at IS NULL AND dead_lettered_at IS NULL;
@@ -0,0 +1,2 @@
+-- entries of purged reels are kept, so existing rows aren't checked against the constraint
+ALTER TABLE "reel_audit_entries" ADD CONSTRAINT "reel_audit_entries_reel_id_fkey" FOREIGN KEY ("reel_id") REFERENCES reels(id) NOT VALID;
@@ -0,0 +1,2 @@
+-- audit entries outlive the reels they describe so support can still answer for purged reels
+ALTER TABLE "reel_audit_entries" DROP CONSTRAINT IF EXISTS "reel_audit_entries_reel_id_fkey";
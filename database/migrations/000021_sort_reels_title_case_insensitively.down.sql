@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS reels_user_title_idx;
+CREATE INDEX IF NOT EXISTS reels_user_title_idx ON reels (user_id, title, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- titles are sorted on lower(title) COLLATE "C", the index has to match for the sort to use it
+DROP INDEX IF EXISTS reels_user_title_idx;
+CREATE INDEX IF NOT EXISTS reels_user_title_idx ON reels (user_id, (lower(title) COLLATE "C"), id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- wrong passwords are counted per link and lock it for a while once there are too many
+ALTER TABLE "share_links" ADD COLUMN IF NOT EXISTS "failed_password_attempts" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "share_links" ADD COLUMN IF NOT EXISTS "locked_until" TIMESTAMPTZ;
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createAuditEntry = `
+	INSERT INTO reel_audit_entries (id, reel_id, actor_id, action, changes, request_id)
+	VALUES ($1,$2,$3,$4,$5,$6);
+	`
+
+	fetchReelAuditEntries = `
+	SELECT
+		id,
+		reel_id,
+		actor_id,
+		action,
+		changes,
+		request_id,
+		created_at
+	FROM reel_audit_entries
+	WHERE reel_id = $1
+	ORDER BY created_at, id;
+	`
+)
+
+// auditRepo only reads, entries are written by the other repositories
+type auditRepo struct {
+	replica sqlx.ExtContext
+}
+
+func NewAuditRepo(db database.Database) datastore.AuditRepository {
+	return &auditRepo{replica: db.GetReplicaDB()}
+}
+
+func (a auditRepo) GetReelAuditEntries(ctx context.Context, reelID string) ([]datastore.AuditEntry, error) {
+	var entries []datastore.AuditEntry
+
+	err := sqlx.SelectContext(ctx, a.replica, &entries, fetchReelAuditEntries, reelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// recordReelAudit appends an entry for a change to a reel made by the auditor in ctx.
+// It should run in the same transaction as the change
+func recordReelAudit(ctx context.Context, q sqlx.ExecerContext, reelID string, action datastore.AuditAction, changes datastore.AuditChanges) error {
+	entry := datastore.NewReelAuditEntry(ctx, reelID, action, changes)
+
+	_, err := q.ExecContext(ctx, createAuditEntry,
+		entry.UID,
+		entry.ReelID,
+		entry.ActorID,
+		entry.Action,
+		entry.Changes,
+		entry.RequestID,
+	)
+
+	return err
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createBulkSavepoint   = `SAVEPOINT bulk_item;`
+	rollbackBulkSavepoint = `ROLLBACK TO SAVEPOINT bulk_item;`
+	releaseBulkSavepoint  = `RELEASE SAVEPOINT bulk_item;`
+)
+
+// runBulk calls fn for n items in one transaction. Each item runs under its own savepoint
+// so a failed item is rolled back on its own and the transaction carries on with the next
+func runBulk(ctx context.Context, db sqlx.ExtContext, n int, fn func(tx sqlx.ExtContext, i int) error) ([]error, error) {
+	results := make([]error, n)
+
+	err := runInTx(ctx, db, func(tx sqlx.ExtContext) error {
+		for i := 0; i < n; i++ {
+			if _, err := tx.ExecContext(ctx, createBulkSavepoint); err != nil {
+				return err
+			}
+
+			if err := fn(tx, i); err != nil {
+				results[i] = err
+
+				if _, err := tx.ExecContext(ctx, rollbackBulkSavepoint); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, releaseBulkSavepoint); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r reelRepo) CreateReels(ctx context.Context, reels []*datastore.Reel) ([]error, error) {
+	return runBulk(ctx, r.db, len(reels), func(tx sqlx.ExtContext, i int) error {
+		return reelRepo{db: tx, replica: tx}.CreateReel(ctx, reels[i])
+	})
+}
+
+func (r reelRepo) DeleteReels(ctx context.Context, reelIDs []string) ([]error, error) {
+	return runBulk(ctx, r.db, len(reelIDs), func(tx sqlx.ExtContext, i int) error {
+		return reelRepo{db: tx, replica: tx}.DeleteReel(ctx, reelIDs[i])
+	})
+}
+
+func (r reelRepo) AddRecipientsToReels(ctx context.Context, reels []*datastore.Reel, recipients []datastore.Recipients) ([]error, error) {
+	if len(reels) != len(recipients) {
+		return nil, datastore.ErrBulkLengthMismatch
+	}
+
+	return runBulk(ctx, r.db, len(reels), func(tx sqlx.ExtContext, i int) error {
+		return reelRepo{db: tx, replica: tx}.AddRecipients(ctx, reels[i], recipients[i])
+	})
+}
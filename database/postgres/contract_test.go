@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	datastoretest.Run(t, func(t *testing.T) datastoretest.Repositories {
+		db, closeFn := getDB(t)
+		t.Cleanup(closeFn)
+
+		return datastoretest.Repositories{
+			Repositories: newRepositories(db.GetDB()),
+			UnitOfWork:   NewUnitOfWork(db),
+		}
+	})
+}
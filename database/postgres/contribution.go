@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	createContributor = `
+	INSERT INTO reel_contributors (id, reel_id, email)
+	VALUES ($1,$2,$3)
+	RETURNING *;
+	`
+
+	fetchContributorById = `SELECT * FROM reel_contributors WHERE id = $1 AND removed_at IS NULL;`
+
+	fetchContributorsByReel = `
+	SELECT * FROM reel_contributors
+	WHERE reel_id = $1 AND removed_at IS NULL
+	ORDER BY created_at, id;
+	`
+
+	removeContributor = `
+	UPDATE reel_contributors SET
+		removed_at = NOW()
+	WHERE id = $1 AND reel_id = $2 AND removed_at IS NULL;
+	`
+
+	// a removed contributor's clips go unless the owner approved them
+	deleteUnapprovedContributions = `
+	DELETE FROM reel_contributions
+	WHERE contributor_id = $1 AND status <> 'approved'
+	RETURNING video_id;
+	`
+
+	// contributions to a reel are changed one at a time so positions stay unique
+	lockReelContributions = `SELECT id FROM reels WHERE id = $1 FOR UPDATE;`
+
+	countContributorContributions = `SELECT COUNT(*) FROM reel_contributions WHERE contributor_id = $1;`
+
+	createContribution = `
+	INSERT INTO reel_contributions (id, reel_id, contributor_id, video_id, status, position)
+	SELECT $1, $2, $3, $4, $5, COALESCE(MAX(position), 0) + 1
+	FROM reel_contributions
+	WHERE reel_id = $2
+	RETURNING *;
+	`
+
+	fetchContributionsByReel = `
+	SELECT * FROM reel_contributions
+	WHERE reel_id = $1
+	ORDER BY position, id;
+	`
+
+	fetchContributionIdsByReel = `SELECT id FROM reel_contributions WHERE reel_id = $1;`
+
+	setContributionStatus = `
+	UPDATE reel_contributions SET
+		status = $3,
+		updated_at = NOW()
+	WHERE id = $1 AND reel_id = $2;
+	`
+
+	reorderContributions = `
+	UPDATE reel_contributions SET
+		position = ordered.position,
+		updated_at = NOW()
+	FROM unnest($2::TEXT[]) WITH ORDINALITY AS ordered(id, position)
+	WHERE reel_contributions.id = ordered.id AND reel_contributions.reel_id = $1;
+	`
+
+	deleteContribution = `DELETE FROM reel_contributions WHERE id = $1 AND reel_id = $2 RETURNING video_id;`
+
+	deleteContributionVideo = `DELETE FROM videos WHERE id = $1 RETURNING id, key, hls_master_key;`
+
+	deleteContributionVideos = `DELETE FROM videos WHERE id = ANY($1) RETURNING id, key, hls_master_key;`
+)
+
+type contributionRepo struct {
+	db sqlx.ExtContext
+}
+
+func NewContributionRepo(db database.Database) datastore.ContributionRepository {
+	return &contributionRepo{db: db.GetDB()}
+}
+
+func (c contributionRepo) GetContributorByID(ctx context.Context, id string) (*datastore.Contributor, error) {
+	contributor := &datastore.Contributor{}
+	if err := c.db.QueryRowxContext(ctx, fetchContributorById, id).StructScan(contributor); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrContributorNotFound
+		}
+		return nil, err
+	}
+
+	return contributor, nil
+}
+
+func (c contributionRepo) GetContributorsByReel(ctx context.Context, reelID string) ([]datastore.Contributor, error) {
+	contributors := []datastore.Contributor{}
+	if err := sqlx.SelectContext(ctx, c.db, &contributors, fetchContributorsByReel, reelID); err != nil {
+		return nil, err
+	}
+
+	return contributors, nil
+}
+
+func (c contributionRepo) CreateContributor(ctx context.Context, contributor *datastore.Contributor, messages ...datastore.OutboxMessage) error {
+	return runInTx(ctx, c.db, func(tx sqlx.ExtContext) error {
+		row := tx.QueryRowxContext(ctx, createContributor, contributor.UID, contributor.ReelID, contributor.Email)
+		if err := row.StructScan(contributor); err != nil {
+			return err
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
+}
+
+func (c contributionRepo) RemoveContributor(ctx context.Context, reelID string, id string) ([]datastore.Video, error) {
+	videos := []datastore.Video{}
+	err := runInTx(ctx, c.db, func(tx sqlx.ExtContext) error {
+		if err := execAffectingOne(ctx, tx, datastore.ErrContributorNotFound, removeContributor, id, reelID); err != nil {
+			return err
+		}
+
+		var videoIDs []string
+		if err := sqlx.SelectContext(ctx, tx, &videoIDs, deleteUnapprovedContributions, id); err != nil {
+			return err
+		}
+
+		return sqlx.SelectContext(ctx, tx, &videos, deleteContributionVideos, pq.Array(videoIDs))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+func (c contributionRepo) GetContributionsByReel(ctx context.Context, reelID string) ([]datastore.Contribution, error) {
+	contributions := []datastore.Contribution{}
+	if err := sqlx.SelectContext(ctx, c.db, &contributions, fetchContributionsByReel, reelID); err != nil {
+		return nil, err
+	}
+
+	return contributions, nil
+}
+
+func (c contributionRepo) CreateContribution(ctx context.Context, contribution *datastore.Contribution, maxPerContributor int) error {
+	return runInTx(ctx, c.db, func(tx sqlx.ExtContext) error {
+		if err := lockContributions(ctx, tx, contribution.ReelID); err != nil {
+			return err
+		}
+
+		var added int
+		if err := tx.QueryRowxContext(ctx, countContributorContributions, contribution.ContributorID).Scan(&added); err != nil {
+			return err
+		}
+
+		if added >= maxPerContributor {
+			return datastore.ErrContributionLimitReached
+		}
+
+		row := tx.QueryRowxContext(ctx, createContribution,
+			contribution.UID,
+			contribution.ReelID,
+			contribution.ContributorID,
+			contribution.VideoID,
+			contribution.Status,
+		)
+
+		return row.StructScan(contribution)
+	})
+}
+
+func (c contributionRepo) SetContributionStatus(ctx context.Context, reelID string, id string, status datastore.ContributionStatus) error {
+	return classifyError(execAffectingOne(ctx, c.db, datastore.ErrContributionNotFound, setContributionStatus, id, reelID, status))
+}
+
+func (c contributionRepo) ReorderContributions(ctx context.Context, reelID string, ids []string) error {
+	return runInTx(ctx, c.db, func(tx sqlx.ExtContext) error {
+		if err := lockContributions(ctx, tx, reelID); err != nil {
+			return err
+		}
+
+		var existing []string
+		if err := sqlx.SelectContext(ctx, tx, &existing, fetchContributionIdsByReel, reelID); err != nil {
+			return err
+		}
+
+		if !sameContributions(existing, ids) {
+			return datastore.ErrInvalidContributionOrder
+		}
+
+		_, err := tx.ExecContext(ctx, reorderContributions, reelID, pq.Array(ids))
+		return err
+	})
+}
+
+func (c contributionRepo) DeleteContribution(ctx context.Context, reelID string, id string) (*datastore.Video, error) {
+	video := &datastore.Video{}
+	err := runInTx(ctx, c.db, func(tx sqlx.ExtContext) error {
+		var videoID string
+		if err := tx.QueryRowxContext(ctx, deleteContribution, id, reelID).Scan(&videoID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return datastore.ErrContributionNotFound
+			}
+			return err
+		}
+
+		return tx.QueryRowxContext(ctx, deleteContributionVideo, videoID).StructScan(video)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+func lockContributions(ctx context.Context, tx sqlx.ExtContext, reelID string) error {
+	var id string
+	if err := tx.QueryRowxContext(ctx, lockReelContributions, reelID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return datastore.ErrReelNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// sameContributions reports whether ids holds each of the existing contributions exactly once
+func sameContributions(existing []string, ids []string) bool {
+	if len(existing) != len(ids) {
+		return false
+	}
+
+	existing, ids = slices.Clone(existing), slices.Clone(ids)
+	slices.Sort(existing)
+	slices.Sort(ids)
+
+	return slices.Equal(existing, ids)
+}
+
+// execAffectingOne runs a statement that should change a single row, returning notFound when it changed none
+func execAffectingOne(ctx context.Context, db sqlx.ExecerContext, notFound error, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/lib/pq"
+)
+
+// postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation      pq.ErrorCode = "23505"
+	foreignKeyViolation  pq.ErrorCode = "23503"
+	checkViolation       pq.ErrorCode = "23514"
+	serializationFailure pq.ErrorCode = "40001"
+	deadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// constraintErrors are the datastore errors violating a specific constraint means
+var constraintErrors = map[string]error{
+	"users_email_key":                        datastore.ErrDuplicateUserEmail,
+	"reel_video_keys":                        datastore.ErrReelVideoInUse,
+	"reel_recipients_reel_email_key":         datastore.ErrDuplicateRecipient,
+	"reels_user_id_fkey":                     datastore.ErrUserNotFound,
+	"reels_video_id_fkey":                    datastore.ErrVideoNotFound,
+	"reel_recipients_reel_id_fkey":           datastore.ErrReelNotFound,
+	"view_tokens_reel_id_fkey":               datastore.ErrReelNotFound,
+	"share_links_reel_id_fkey":               datastore.ErrReelNotFound,
+	"reel_responses_reel_id_fkey":            datastore.ErrReelNotFound,
+	"reel_responses_video_id_fkey":           datastore.ErrVideoNotFound,
+	"reel_contributors_reel_id_fkey":         datastore.ErrReelNotFound,
+	"reel_contributors_reel_email_key":       datastore.ErrDuplicateContributor,
+	"reel_contributions_contributor_id_fkey": datastore.ErrContributorNotFound,
+	"reel_contributions_video_id_fkey":       datastore.ErrVideoNotFound,
+}
+
+// classifiedError is a driver error translated into datastore errors. It matches
+// them with errors.Is while keeping the *pq.Error reachable with errors.As
+type classifiedError struct {
+	pqErr   *pq.Error
+	matches []error
+}
+
+func (e *classifiedError) Error() string {
+	return e.matches[0].Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return append(append([]error(nil), e.matches...), e.pqErr)
+}
+
+// classifyError translates integrity and concurrency errors from postgres into datastore errors.
+// A violated constraint the datastore knows about matches its specific error, like
+// ErrDuplicateUserEmail, as well as the general one for its class, like ErrDuplicate.
+// Any other error is returned as is
+func classifyError(err error) error {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	var class error
+	switch pqErr.Code {
+	case uniqueViolation:
+		class = datastore.ErrDuplicate
+	case foreignKeyViolation:
+		class = datastore.ErrReferenceNotFound
+	case checkViolation:
+		class = datastore.ErrConstraintViolation
+	case serializationFailure, deadlockDetected:
+		class = datastore.ErrSerializationFailure
+	default:
+		return err
+	}
+
+	if specific, ok := constraintErrors[pqErr.Constraint]; ok {
+		return &classifiedError{pqErr: pqErr, matches: []error{specific, class}}
+	}
+
+	return &classifiedError{pqErr: pqErr, matches: []error{class}}
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		matches []error
+	}{
+		{
+			name:    "unique violation on a known constraint",
+			err:     &pq.Error{Code: uniqueViolation, Constraint: "users_email_key"},
+			matches: []error{datastore.ErrDuplicateUserEmail, datastore.ErrDuplicate},
+		},
+		{
+			name:    "unique violation on an unknown constraint",
+			err:     &pq.Error{Code: uniqueViolation, Constraint: "some_key"},
+			matches: []error{datastore.ErrDuplicate},
+		},
+		{
+			name:    "foreign key violation",
+			err:     &pq.Error{Code: foreignKeyViolation, Constraint: "reels_video_id_fkey"},
+			matches: []error{datastore.ErrVideoNotFound, datastore.ErrReferenceNotFound},
+		},
+		{
+			name:    "check violation",
+			err:     &pq.Error{Code: checkViolation, Constraint: "videos_size_mb_check"},
+			matches: []error{datastore.ErrConstraintViolation},
+		},
+		{
+			name:    "serialization failure",
+			err:     &pq.Error{Code: serializationFailure},
+			matches: []error{datastore.ErrSerializationFailure},
+		},
+		{
+			name:    "deadlock",
+			err:     &pq.Error{Code: deadlockDetected},
+			matches: []error{datastore.ErrSerializationFailure},
+		},
+		{
+			name:    "wrapped driver error",
+			err:     fmt.Errorf("insert: %w", &pq.Error{Code: uniqueViolation, Constraint: "reel_video_keys"}),
+			matches: []error{datastore.ErrReelVideoInUse, datastore.ErrDuplicate},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classifyError(tc.err)
+
+			for _, match := range tc.matches {
+				require.ErrorIs(t, err, match)
+			}
+			require.Equal(t, tc.matches[0].Error(), err.Error())
+
+			// the driver error is still there for logging
+			var pqErr *pq.Error
+			require.True(t, errors.As(err, &pqErr))
+
+			// classifying twice changes nothing
+			require.Equal(t, err, classifyError(err))
+		})
+	}
+
+	t.Run("errors that aren't classified are returned as is", func(t *testing.T) {
+		require.Nil(t, classifyError(nil))
+		require.Equal(t, context.Canceled, classifyError(context.Canceled))
+
+		notNull := &pq.Error{Code: "23502"}
+		require.Equal(t, error(notNull), classifyError(notNull))
+	})
+}
+
+func TestCreateReelMissingReferences(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	reelRepo := NewReelRepo(db)
+	user := seedUser(t, db)
+
+	err := reelRepo.CreateReel(context.Background(), generateReel("missing-video", user.UID))
+	require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+	require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+
+	err = reelRepo.CreateReel(context.Background(), generateReel(seedVideo(t, db).UID, "missing-user"))
+	require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+}
+
+func TestUpdateReelVideoInUse(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	reelRepo := NewReelRepo(db)
+	first := seedReel(t, db)
+	second := seedReel(t, db)
+
+	second.VideoID = first.VideoID
+	err := reelRepo.UpdateReel(context.Background(), second)
+	require.ErrorIs(t, err, datastore.ErrReelVideoInUse)
+	require.ErrorIs(t, err, datastore.ErrDuplicate)
+}
+
+func TestUpdateUserDuplicateEmail(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	userRepo := NewUserRepo(db)
+	first := seedUser(t, db)
+	second := seedUser(t, db)
+
+	second.Email = first.Email
+	err := userRepo.UpdateUser(context.Background(), second)
+	require.ErrorIs(t, err, datastore.ErrDuplicateUserEmail)
+	require.ErrorIs(t, err, datastore.ErrDuplicate)
+}
+
+func TestVideoCheckViolation(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	videoRepo := NewVideoRepo(db)
+
+	video := generateVideo()
+	video.SizeMB = -1
+	require.ErrorIs(t, videoRepo.CreateVideo(context.Background(), video), datastore.ErrConstraintViolation)
+
+	video = seedVideo(t, db)
+	video.SizeMB = -1
+	require.ErrorIs(t, videoRepo.UpdateVideo(context.Background(), video), datastore.ErrConstraintViolation)
+}
+
+func TestDeleteVideoReturnsErrors(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	videoRepo := NewVideoRepo(db)
+	video := seedVideo(t, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.Error(t, videoRepo.DeleteVideo(ctx, video.UID))
+
+	_, err := videoRepo.GetVideoByID(context.Background(), video.UID)
+	require.NoError(t, err)
+}
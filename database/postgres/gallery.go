@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	fetchGalleryItems = `
+	SELECT
+		reels.id AS reel_id,
+		reels.title,
+		reels.description,
+		reels.delivery_date,
+		COALESCE(users.first_name, '') AS sender_name,
+		reels.video_id
+	FROM reels
+	LEFT JOIN users ON users.id = reels.user_id
+	WHERE `
+
+	galleryExistsWhere = `SELECT EXISTS (SELECT 1 FROM reels WHERE `
+
+	galleryShared     = `reels.deleted_at IS NULL AND reels.delivery_status = 'delivered' AND reels.private = false AND reels.public_opt_in_at IS NOT NULL`
+	galleryReel       = `reels.id = ?`
+	galleryOrderDesc  = `reels.delivery_date DESC, reels.id DESC`
+	galleryOrderAsc   = `reels.delivery_date ASC, reels.id ASC`
+	galleryBefore     = `(reels.delivery_date, reels.id) < (?, ?)`
+	galleryAtOrBefore = `(reels.delivery_date, reels.id) <= (?, ?)`
+	galleryAfter      = `(reels.delivery_date, reels.id) > (?, ?)`
+	galleryAtOrAfter  = `(reels.delivery_date, reels.id) >= (?, ?)`
+)
+
+type galleryRepo struct {
+	replica sqlx.ExtContext
+}
+
+func NewGalleryRepo(db database.Database) datastore.GalleryRepository {
+	return &galleryRepo{replica: db.GetReplicaDB()}
+}
+
+func (r galleryRepo) GetGalleryPaged(ctx context.Context, pageable datastore.Pageable) ([]datastore.GalleryItem, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.GallerySort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	order, ahead, behind := galleryOrderDesc, galleryBefore, galleryAtOrAfter
+	if cursor.Direction == datastore.PrevPage {
+		order, ahead, behind = galleryOrderAsc, galleryAfter, galleryAtOrBefore
+	}
+
+	where := conditions{}.and(galleryShared)
+	page, behindCursor := where, where
+
+	if cursor.ID != "" {
+		deliveredAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+
+		page = where.and(ahead, deliveredAt, cursor.ID)
+		behindCursor = where.and(behind, deliveredAt, cursor.ID)
+	}
+
+	query := r.replica.Rebind(fetchGalleryItems + page.String() + ` ORDER BY ` + order + ` LIMIT ?`)
+	args := append(append([]any{}, page.args...), pageable.Limit())
+
+	var items []datastore.GalleryItem
+	if err := sqlx.SelectContext(ctx, r.replica, &items, query, args...); err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	var hasBehind bool
+	if cursor.ID != "" {
+		query := r.replica.Rebind(galleryExistsWhere + behindCursor.String() + `)`)
+		if err := sqlx.GetContext(ctx, r.replica, &hasBehind, query, behindCursor.args...); err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+	}
+
+	positions := make([]datastore.Cursor, len(items))
+	for i := range items {
+		positions[i] = datastore.GalleryPosition(items[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	items = items[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(items)
+	}
+
+	return items, pagination, nil
+}
+
+func (r galleryRepo) GetGalleryItem(ctx context.Context, reelID string) (*datastore.GalleryItem, error) {
+	where := conditions{}.and(galleryShared).and(galleryReel, reelID)
+
+	var item datastore.GalleryItem
+	err := sqlx.GetContext(ctx, r.replica, &item, r.replica.Rebind(fetchGalleryItems+where.String()), where.args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrReelNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	fetchInboxItems = `
+	SELECT
+		reels.id AS reel_id,
+		rr.id AS recipient_id,
+		reels.title,
+		reels.description,
+		reels.delivery_date,
+		hidden.reel_id IS NOT NULL AS hidden
+	`
+
+	// the user id for the hidden join comes before the conditions' arguments
+	fromInboxWhere = `
+	FROM reel_recipients rr
+	JOIN reels ON reels.id = rr.reel_id
+	LEFT JOIN inbox_hidden_reels hidden ON hidden.reel_id = reels.id AND hidden.user_id = ?
+	WHERE `
+
+	inboxExistsWhere = `SELECT EXISTS (SELECT 1 ` + fromInboxWhere
+
+	inboxDelivered  = `reels.deleted_at IS NULL AND reels.delivery_status = 'delivered' AND rr.deleted_at IS NULL`
+	inboxRecipient  = `lower(rr.email) = lower(?)`
+	inboxHidden     = `(hidden.reel_id IS NOT NULL) = ?`
+	inboxReel       = `reels.id = ?`
+	inboxOrderDesc  = `reels.delivery_date DESC, reels.id DESC`
+	inboxOrderAsc   = `reels.delivery_date ASC, reels.id ASC`
+	inboxBefore     = `(reels.delivery_date, reels.id) < (?, ?)`
+	inboxAtOrBefore = `(reels.delivery_date, reels.id) <= (?, ?)`
+	inboxAfter      = `(reels.delivery_date, reels.id) > (?, ?)`
+	inboxAtOrAfter  = `(reels.delivery_date, reels.id) >= (?, ?)`
+
+	hideInboxItem = `
+	INSERT INTO inbox_hidden_reels (user_id, reel_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;
+	`
+
+	unhideInboxItem = `DELETE FROM inbox_hidden_reels WHERE user_id = $1 AND reel_id = $2;`
+)
+
+type inboxRepo struct {
+	db sqlx.ExtContext
+	// replica serves listings, reads that lead to a write use db
+	replica sqlx.ExtContext
+}
+
+func NewInboxRepo(db database.Database) datastore.InboxRepository {
+	return &inboxRepo{db: db.GetDB(), replica: db.GetReplicaDB()}
+}
+
+func (r inboxRepo) GetInboxPaged(ctx context.Context, userID string, email string, filter datastore.InboxFilter, pageable datastore.Pageable) ([]datastore.InboxItem, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.InboxSort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	order, ahead, behind := inboxOrderDesc, inboxBefore, inboxAtOrAfter
+	if cursor.Direction == datastore.PrevPage {
+		order, ahead, behind = inboxOrderAsc, inboxAfter, inboxAtOrBefore
+	}
+
+	where := conditions{}.and(inboxDelivered).and(inboxRecipient, email).and(inboxHidden, filter.Hidden)
+	page, behindCursor := where, where
+
+	if cursor.ID != "" {
+		deliveredAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+
+		page = where.and(ahead, deliveredAt, cursor.ID)
+		behindCursor = where.and(behind, deliveredAt, cursor.ID)
+	}
+
+	query := r.replica.Rebind(fetchInboxItems + fromInboxWhere + page.String() + ` ORDER BY ` + order + ` LIMIT ?`)
+	args := append(append([]any{userID}, page.args...), pageable.Limit())
+
+	var items []datastore.InboxItem
+	if err := sqlx.SelectContext(ctx, r.replica, &items, query, args...); err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	var hasBehind bool
+	if cursor.ID != "" {
+		query := r.replica.Rebind(inboxExistsWhere + behindCursor.String() + `)`)
+
+		err := sqlx.GetContext(ctx, r.replica, &hasBehind, query, append([]any{userID}, behindCursor.args...)...)
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+	}
+
+	positions := make([]datastore.Cursor, len(items))
+	for i := range items {
+		positions[i] = datastore.InboxPosition(items[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	items = items[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(items)
+	}
+
+	return items, pagination, nil
+}
+
+func (r inboxRepo) SetInboxItemHidden(ctx context.Context, userID string, email string, reelID string, hidden bool) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		where := conditions{}.and(inboxDelivered).and(inboxRecipient, email).and(inboxReel, reelID)
+		query := tx.Rebind(inboxExistsWhere + where.String() + `)`)
+
+		var inInbox bool
+		if err := sqlx.GetContext(ctx, tx, &inInbox, query, append([]any{userID}, where.args...)...); err != nil {
+			return err
+		}
+
+		if !inInbox {
+			return datastore.ErrInboxItemNotFound
+		}
+
+		statement := unhideInboxItem
+		if hidden {
+			statement = hideInboxItem
+		}
+
+		_, err := tx.ExecContext(ctx, statement, userID, reelID)
+		return err
+	})
+}
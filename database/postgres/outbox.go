@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// messages that were already enqueued under the same idempotency key are ignored
+	enqueueOutboxMessage = `
+	INSERT INTO outbox_messages (id, idempotency_key, topic, payload)
+	VALUES ($1,$2,$3,$4)
+	ON CONFLICT (idempotency_key) DO NOTHING;
+	`
+
+	claimPendingOutboxMessages = `
+	UPDATE outbox_messages SET
+		locked_until = NOW() + make_interval(secs => $2),
+		attempts = attempts + 1
+	WHERE id IN (
+		SELECT id FROM outbox_messages
+		WHERE dispatched_at IS NULL
+		AND dead_lettered_at IS NULL
+		AND available_at <= NOW()
+		AND (locked_until IS NULL OR locked_until < NOW())
+		ORDER BY available_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *;
+	`
+
+	markOutboxMessageDispatched = `
+	UPDATE outbox_messages SET
+		dispatched_at = NOW(),
+		locked_until = NULL,
+		last_error = NULL
+	WHERE id = $1;
+	`
+
+	markOutboxMessageFailed = `
+	UPDATE outbox_messages SET
+		last_error = $2,
+		available_at = $3,
+		locked_until = NULL
+	WHERE id = $1;
+	`
+
+	markOutboxMessageDeadLettered = `
+	UPDATE outbox_messages SET
+		last_error = $2,
+		dead_lettered_at = NOW(),
+		locked_until = NULL
+	WHERE id = $1;
+	`
+)
+
+type outboxRepo struct {
+	db sqlx.ExtContext
+}
+
+func NewOutboxRepo(db database.Database) datastore.OutboxRepository {
+	return &outboxRepo{db: db.GetDB()}
+}
+
+func (o outboxRepo) EnqueueMessages(ctx context.Context, messages ...datastore.OutboxMessage) error {
+	return enqueueMessages(ctx, o.db, messages)
+}
+
+func (o outboxRepo) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]datastore.OutboxMessage, error) {
+	var messages []datastore.OutboxMessage
+
+	err := sqlx.SelectContext(ctx, o.db, &messages, claimPendingOutboxMessages, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (o outboxRepo) MarkMessageDispatched(ctx context.Context, messageID string) error {
+	_, err := o.db.ExecContext(ctx, markOutboxMessageDispatched, messageID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o outboxRepo) MarkMessageFailed(ctx context.Context, messageID string, reason string, retryAt time.Time) error {
+	_, err := o.db.ExecContext(ctx, markOutboxMessageFailed, messageID, reason, retryAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o outboxRepo) MarkMessageDeadLettered(ctx context.Context, messageID string, reason string) error {
+	_, err := o.db.ExecContext(ctx, markOutboxMessageDeadLettered, messageID, reason)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func enqueueMessages(ctx context.Context, q sqlx.ExecerContext, messages []datastore.OutboxMessage) error {
+	for _, message := range messages {
+		_, err := q.ExecContext(ctx, enqueueOutboxMessage,
+			message.UID,
+			message.IdempotencyKey,
+			message.Topic,
+			message.Payload,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withOutbox runs fn against the database. When there are messages to enqueue,
+// fn runs in a transaction that also writes the messages to the outbox so the
+// change and its side effects are committed together or not at all
+func withOutbox(ctx context.Context, db sqlx.ExtContext, messages []datastore.OutboxMessage, fn func(sqlx.ExtContext) error) error {
+	if len(messages) == 0 {
+		return classifyError(fn(db))
+	}
+
+	return runInTx(ctx, db, func(tx sqlx.ExtContext) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
+}
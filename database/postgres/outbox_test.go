@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueMessages(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	outboxRepo := NewOutboxRepo(db)
+	message := generateOutboxMessage()
+
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), message))
+
+	// enqueueing the same key again is a no-op
+	duplicate := generateOutboxMessage()
+	duplicate.IdempotencyKey = message.IdempotencyKey
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), duplicate))
+
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+
+	require.Len(t, messages, 1)
+	require.Equal(t, message.UID, messages[0].UID)
+	require.Equal(t, 1, messages[0].Attempts)
+}
+
+func TestClaimPendingMessages(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	outboxRepo := NewOutboxRepo(db)
+
+	dispatched := generateOutboxMessage()
+	failed := generateOutboxMessage()
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), dispatched, failed))
+
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	// claimed messages are hidden until their lease expires
+	messages, err = outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 0)
+
+	require.NoError(t, outboxRepo.MarkMessageDispatched(context.Background(), dispatched.UID))
+	require.NoError(t, outboxRepo.MarkMessageFailed(context.Background(), failed.UID, "smtp unavailable", time.Now().Add(-time.Second)))
+
+	messages, err = outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+
+	require.Len(t, messages, 1)
+	require.Equal(t, failed.UID, messages[0].UID)
+	require.Equal(t, 2, messages[0].Attempts)
+	require.Equal(t, "smtp unavailable", messages[0].LastError.String)
+}
+
+func TestUpdateReelWithMessages(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	reelRepo := NewReelRepo(db)
+	outboxRepo := NewOutboxRepo(db)
+	reel := seedReel(t, db)
+
+	reel.DeliveryStatus = datastore.DeliveredReelStatus
+	message := generateOutboxMessage()
+	require.NoError(t, reelRepo.UpdateReel(context.Background(), reel, message))
+
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	// a failed update doesn't leave its messages behind
+	missingReel := generateReel(reel.VideoID, reel.UserID.String)
+	require.ErrorIs(t, reelRepo.UpdateReel(context.Background(), missingReel, generateOutboxMessage()), datastore.ErrReelNotUpdated)
+
+	messages, err = outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 0)
+}
+
+func generateOutboxMessage() datastore.OutboxMessage {
+	payload, _ := json.Marshal(map[string]string{"to": "recipient@gmail.com"})
+
+	return datastore.OutboxMessage{
+		UID:            ulid.Make().String(),
+		IdempotencyKey: ulid.Make().String(),
+		Topic:          "email",
+		Payload:        payload,
+	}
+}
@@ -1,30 +1,109 @@
 package postgres
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/ayo-awe/memoreel-be/config"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
 
+const maxConnectBackoff = 30 * time.Second
+
 type PostgresDB struct {
-	dbx *sqlx.DB
+	dbx     *sqlx.DB
+	replica *sqlx.DB
 }
 
+// sleep is swapped out in tests so retries don't wait
+var sleep = time.Sleep
+
+// NewDB connects to the primary, and the read replica when one is configured, retrying
+// with exponential backoff while they aren't reachable
 func NewDB(config config.Configuration) (*PostgresDB, error) {
-	db, err := sqlx.Connect("postgres", config.Database.BuildDSN())
+	cfg := config.Database
+
+	db, err := connect(cfg)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("connecting to postgres: %w", err)
 	}
 
-	pgDB := &PostgresDB{dbx: db}
+	pgDB := &PostgresDB{dbx: db, replica: db}
+
+	if cfg.HasReplica() {
+		replica, err := connect(cfg.Replica())
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("connecting to postgres replica: %w", err)
+		}
+
+		pgDB.replica = replica
+	}
 
 	return pgDB, nil
 }
 
+// connect opens a connection pool configured by cfg
+func connect(cfg config.DatabaseConfiguration) (*sqlx.DB, error) {
+	var db *sqlx.DB
+	err := retry(cfg.ConnectRetries, cfg.ConnectBackoff, func() error {
+		var err error
+		db, err = sqlx.Connect("postgres", cfg.BuildDSN())
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db, nil
+}
+
+// retry calls fn until it succeeds or has been retried retries times, doubling the wait
+// between attempts up to maxConnectBackoff
+func retry(retries int, backoff time.Duration, fn func() error) error {
+	err := fn()
+
+	for attempt := 0; err != nil && attempt < retries; attempt++ {
+		sleep(backoff)
+
+		backoff = min(backoff*2, maxConnectBackoff)
+		err = fn()
+	}
+
+	return err
+}
+
 func (p *PostgresDB) GetDB() *sqlx.DB {
 	return p.dbx
 }
 
+func (p *PostgresDB) GetReplicaDB() *sqlx.DB {
+	return p.replica
+}
+
+// Stats reports on the primary's connection pool
+func (p *PostgresDB) Stats() sql.DBStats {
+	return p.dbx.Stats()
+}
+
+// ReplicaStats reports on the replica's connection pool
+func (p *PostgresDB) ReplicaStats() sql.DBStats {
+	return p.replica.Stats()
+}
+
 func (p *PostgresDB) Close() error {
-	return p.dbx.Close()
+	if p.replica == p.dbx {
+		return p.dbx.Close()
+	}
+
+	return errors.Join(p.dbx.Close(), p.replica.Close())
 }
@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ayo-awe/memoreel-be/config"
 	"github.com/ayo-awe/memoreel-be/database"
@@ -19,6 +21,9 @@ func getDB(t *testing.T) (database.Database, func()) {
 	require.NoError(t, config.LoadConfig())
 	cfg := config.Get(config.Test)
 
+	// the test database is expected to be up already
+	cfg.Database.ConnectRetries = 0
+
 	db, err := NewDB(cfg)
 	_db = db
 
@@ -32,6 +37,16 @@ func getDB(t *testing.T) (database.Database, func()) {
 
 func (p *PostgresDB) truncateTables() error {
 	tables := `
+		outbox_messages,
+		view_tokens,
+		reel_reminders,
+		reel_audit_entries,
+		inbox_hidden_reels,
+		share_links,
+		reel_responses,
+		reel_contributions,
+		reel_contributors,
+		reel_recipients,
 		reels,
 		videos,
 		users
@@ -40,3 +55,44 @@ func (p *PostgresDB) truncateTables() error {
 	_, err := p.dbx.ExecContext(context.Background(), fmt.Sprintf("TRUNCATE %s CASCADE;", tables))
 	return err
 }
+
+func TestRetry(t *testing.T) {
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	defer func() { sleep = time.Sleep }()
+
+	unreachable := errors.New("connection refused")
+
+	calls := 0
+	err := retry(3, time.Second, func() error {
+		calls++
+		if calls < 3 {
+			return unreachable
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+
+	// gives up once the retries run out, with waits capped
+	waits = nil
+	calls = 0
+	err = retry(3, 20*time.Second, func() error {
+		calls++
+		return unreachable
+	})
+
+	require.ErrorIs(t, err, unreachable)
+	require.Equal(t, 4, calls)
+	require.Equal(t, []time.Duration{20 * time.Second, maxConnectBackoff, maxConnectBackoff}, waits)
+}
+
+func TestReplicaDefaultsToPrimary(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	// without a replica configured reads and writes share the primary's pool
+	require.Same(t, db.GetDB(), db.GetReplicaDB())
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"slices"
+	"strings"
+)
+
+// conditions builds a WHERE clause out of fixed SQL fragments. Values only ever travel as
+// bound arguments, never in the query text, so nothing a user sends can change the SQL
+type conditions struct {
+	clauses []string
+	args    []any
+}
+
+// and returns the conditions with clause added, it takes a ? placeholder for each arg.
+// The receiver is left untouched so a base set of conditions can be extended more than once
+func (c conditions) and(clause string, args ...any) conditions {
+	return conditions{
+		clauses: append(slices.Clip(c.clauses), clause),
+		args:    append(slices.Clip(c.args), args...),
+	}
+}
+
+func (c conditions) String() string {
+	if len(c.clauses) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(c.clauses, " AND ")
+}
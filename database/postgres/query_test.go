@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditions(t *testing.T) {
+	require.Equal(t, "TRUE", conditions{}.String())
+
+	base := conditions{}.and("deleted_at IS NULL").and("user_id = ?", "user")
+	require.Equal(t, "deleted_at IS NULL AND user_id = ?", base.String())
+	require.Equal(t, []any{"user"}, base.args)
+
+	// extending the same conditions twice doesn't let one leak into the other
+	private := base.and("private = ?", true)
+	titled := base.and("title = ?", "x")
+
+	require.Equal(t, "deleted_at IS NULL AND user_id = ? AND private = ?", private.String())
+	require.Equal(t, []any{"user", true}, private.args)
+	require.Equal(t, "deleted_at IS NULL AND user_id = ? AND title = ?", titled.String())
+	require.Equal(t, []any{"user", "x"}, titled.args)
+	require.Len(t, base.args, 1)
+}
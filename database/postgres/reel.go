@@ -5,33 +5,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ayo-awe/memoreel-be/database"
 	"github.com/ayo-awe/memoreel-be/datastore"
 	"github.com/jmoiron/sqlx"
-)
-
-var (
-	ErrReelNotCreated          = errors.New("reel could not be created")
-	ErrReelNotUpdated          = errors.New("reel could not be updated")
-	ErrReelRecipientNotDeleted = errors.New("reel recipient could not be deleted")
-	ErrReelRecipientsNotAdded  = errors.New("reel recipients could not added")
-	ErrReelNotDeleted          = errors.New("reel could not be deleted")
+	"github.com/lib/pq"
+	"gopkg.in/guregu/null.v4"
 )
 
 const (
-	createReel = `
-	INSERT INTO reels (
-		id, user_id, video_id, email,
-		title, description, private, recipients,
-		email_confirmation_token, delivery_status, delivery_date
-	)
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
-	RETURNING *;
-	`
-
-	fetchReel = `
-	SELECT
+	// reelColumns selects a reel along with its active recipients aggregated
+	// into a json array, the shape datastore.Recipients scans from
+	reelColumns = `
 		id,
 		user_id,
 		video_id,
@@ -39,48 +26,94 @@ const (
 		title,
 		description,
 		private,
-		recipients,
+		COALESCE((
+			SELECT jsonb_agg(jsonb_build_object(
+				'uid', rr.id,
+				'email', rr.email,
+				'created_at', rr.created_at,
+				'deleted_at', rr.deleted_at
+			) ORDER BY rr.created_at, rr.id)
+			FROM reel_recipients rr
+			WHERE rr.reel_id = reels.id AND rr.deleted_at IS NULL
+		), '[]') AS recipients,
 		email_confirmation_token,
 		delivery_status,
 		delivery_date,
+		suppress_reminders,
+		public_opt_in_at,
+		contribution_deadline,
 		updated_at,
 		created_at,
-		deleted_at
+		deleted_at,
+		version
+	`
+
+	createReel = `
+	INSERT INTO reels (
+		id, user_id, video_id, email,
+		title, description, private,
+		email_confirmation_token, delivery_status, delivery_date,
+		suppress_reminders, public_opt_in_at, contribution_deadline
+	)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13);
+	`
+
+	fetchReel = `
+	SELECT` + reelColumns + `
 	FROM reels
 	WHERE %s = $1 AND deleted_at IS NULL;
 	`
 
-	fetchReelsPaged = `
-	SELECT
-		id,
-		user_id,
-		video_id,
-		email,
-		title,
-		description,
-		private,
-		recipients,
-		email_confirmation_token,
-		delivery_status,
-		delivery_date,
-		updated_at,
-		created_at,
-		deleted_at
+	// the listing queries add their conditions and order to these
+	fetchReels = `
+	SELECT` + reelColumns
+
+	fromReelsWhere = `
+	FROM reels
+	WHERE `
+
+	// websearch_to_tsquery accepts any input, stray operators and quotes are ignored rather than failing the query
+	reelsSearchQuery = `websearch_to_tsquery('english', ?)`
+	reelsSearchRank  = `ts_rank(search_vector, ` + reelsSearchQuery + `)`
+	selectSearchRank = `, ` + reelsSearchRank + ` AS search_rank`
+
+	reelsExistWhere = `
+	SELECT EXISTS (SELECT 1 FROM reels WHERE `
+
+	reelsNotDeleted           = `deleted_at IS NULL`
+	reelsFilterUser           = `user_id = ?`
+	reelsFilterDeliveryStatus = `delivery_status = ?`
+	reelsFilterDeliveryFrom   = `delivery_date >= ?`
+	reelsFilterDeliveryTo     = `delivery_date < ?`
+	reelsFilterCreatedFrom    = `created_at >= ?`
+	reelsFilterCreatedTo      = `created_at < ?`
+	reelsFilterPrivate        = `private = ?`
+	reelsFilterHasRecipients  = `EXISTS (SELECT 1 FROM reel_recipients rr WHERE rr.reel_id = reels.id AND rr.deleted_at IS NULL)`
+	reelsFilterNoRecipients   = `NOT ` + reelsFilterHasRecipients
+	reelsFilterRecipientEmail = `EXISTS (SELECT 1 FROM reel_recipients rr WHERE rr.reel_id = reels.id AND rr.deleted_at IS NULL AND lower(rr.email) = lower(?))`
+	reelsFilterSearch         = `search_vector @@ ` + reelsSearchQuery
+
+	// reels scheduled for delivery within the next n days that haven't had the n day reminder
+	fetchReelsDueForReminder = `
+	SELECT` + reelColumns + `
 	FROM reels
 	WHERE deleted_at IS NULL
-	%s
-	AND id < :cursor
-	ORDER BY id DESC
-	LIMIT :limit;
-	`
-
-	baseReelsFilter = `
-	AND user_id = :user_id
+	AND suppress_reminders = false
+	AND delivery_status = 'scheduled'
+	AND delivery_date > NOW()
+	AND delivery_date <= NOW() + make_interval(days => $1)
+	AND NOT EXISTS (
+		SELECT 1 FROM reel_reminders rm
+		WHERE rm.reel_id = reels.id AND rm.days_before = $1
+	)
+	ORDER BY delivery_date
+	LIMIT $2;
 	`
 
-	reelDeliveryStatusFilter = `
-	%s
-	AND delivery_status = :delivery_status
+	recordReminders = `
+	INSERT INTO reel_reminders (reel_id, days_before)
+	SELECT $1, unnest($2::INTEGER[])
+	ON CONFLICT DO NOTHING;
 	`
 
 	updateReel = `
@@ -94,52 +127,147 @@ const (
 		delivery_status = $8,
 		delivery_date = $9,
 		email_confirmation_token = $10,
-		updated_at = NOW()
-	WHERE id = $1 AND deleted_at IS NULL;
+		suppress_reminders = $11,
+		contribution_deadline = $12,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND version = $13
+	RETURNING version, updated_at;
+	`
+
+	// the audited columns of a reel, locked until the end of the transaction
+	fetchReelForUpdate = `
+	SELECT
+		id,
+		user_id,
+		video_id,
+		email,
+		title,
+		description,
+		private,
+		delivery_status,
+		delivery_date,
+		suppress_reminders,
+		contribution_deadline,
+		version
+	FROM reels
+	WHERE id = $1 AND deleted_at IS NULL
+	FOR UPDATE;
 	`
 
 	assignReelsToUserByEmail = `
 	UPDATE reels SET
-		user_id = $2
+		user_id = $2,
+		version = version + 1
 	WHERE user_id IS NULL
 	AND email = $1
-	AND deleted_at IS NULL;
+	AND deleted_at IS NULL
+	RETURNING id;
 	`
 
-	// postgres jsonb array concatenation
-	addRecipients = `
+	// recipient changes bump the reel's version so clients holding the old one notice
+	touchReel = `
 	UPDATE reels SET
-		recipients = recipients || $2,
-		updated_at = NOW()
-	WHERE id = $1 AND deleted_at IS NULL;
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL
+	RETURNING version;
+	`
+
+	createRecipient = `
+	INSERT INTO reel_recipients (id, reel_id, email, created_at, deleted_at)
+	VALUES ($1,$2,$3,$4,$5);
 	`
 
 	deleteRecipient = `
-	UPDATE reels
-		SET recipients = (
-			SELECT jsonb_agg(
-				CASE
-					WHEN r->>'uid' = $2 AND r->>'deleted_at' IS NULL THEN jsonb_set(r, '{deleted_at}', to_jsonb(NOW()))
-					ELSE r
-				END
-			)
-			FROM jsonb_array_elements(recipients) r
-		)
-	WHERE id = $1 AND deleted_at IS NULL;
+	UPDATE reel_recipients SET
+		deleted_at = NOW()
+	WHERE id = $2
+	AND reel_id = $1
+	AND deleted_at IS NULL
+	AND EXISTS (
+		SELECT 1 FROM reels
+		WHERE reels.id = $1 AND reels.deleted_at IS NULL
+	);
 	`
 
 	deleteReel = `
 	UPDATE reels SET
 		deleted_at = NOW()
 	WHERE id = $1 AND deleted_at IS NULL;`
+
+	fetchDeletedReel = `
+	SELECT` + reelColumns + `
+	FROM reels
+	WHERE id = $1 AND deleted_at IS NOT NULL;
+	`
+
+	reelsDeleted      = `deleted_at IS NOT NULL`
+	reelsDeletedAfter = `deleted_at >= ?`
+
+	restoreReel = `
+	UPDATE reels SET
+		deleted_at = NULL,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at >= $2
+	RETURNING version, updated_at;
+	`
+
+	// skip locked lets several purge jobs run side by side without working on the same reels
+	fetchReelsToPurge = `
+	SELECT id, video_id
+	FROM reels
+	WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	ORDER BY deleted_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED;
+	`
+
+	purgeReelViewTokens = `DELETE FROM view_tokens WHERE reel_id = ANY($1);`
+
+	purgeReelReminders = `DELETE FROM reel_reminders WHERE reel_id = ANY($1);`
+
+	purgeReelRecipients = `DELETE FROM reel_recipients WHERE reel_id = ANY($1);`
+
+	purgeReelInboxHidden = `DELETE FROM inbox_hidden_reels WHERE reel_id = ANY($1);`
+
+	purgeReelShareLinks = `DELETE FROM share_links WHERE reel_id = ANY($1);`
+
+	// reply videos go along with their responses
+	purgeReelVideoResponses = `DELETE FROM reel_responses WHERE reel_id = ANY($1) AND video_id IS NOT NULL RETURNING video_id;`
+
+	// as do contributed clips
+	purgeReelContributions = `DELETE FROM reel_contributions WHERE reel_id = ANY($1) RETURNING video_id;`
+
+	purgeReelContributors = `DELETE FROM reel_contributors WHERE reel_id = ANY($1);`
+
+	purgeReelResponses = `DELETE FROM reel_responses WHERE reel_id = ANY($1);`
+
+	purgeReels = `DELETE FROM reels WHERE id = ANY($1);`
+
+	// videos are only removed once no reel, deleted or not, points at them
+	purgeUnreferencedVideos = `
+	DELETE FROM videos
+	WHERE id = ANY($1)
+	AND NOT EXISTS (
+		SELECT 1 FROM reels WHERE reels.video_id = videos.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM reel_responses WHERE reel_responses.video_id = videos.id
+	)
+	RETURNING id, key, hls_master_key;
+	`
 )
 
 type reelRepo struct {
-	db *sqlx.DB
+	db sqlx.ExtContext
+	// replica serves listings, reads that lead to a write use db
+	replica sqlx.ExtContext
 }
 
 func NewReelRepo(db database.Database) datastore.ReelRepository {
-	return &reelRepo{db: db.GetDB()}
+	return &reelRepo{db: db.GetDB(), replica: db.GetReplicaDB()}
 }
 
 func (r reelRepo) GetReelByID(ctx context.Context, id string) (*datastore.Reel, error) {
@@ -170,135 +298,325 @@ func (r reelRepo) GetReelByEmailConfirmationToken(ctx context.Context, token str
 	return reel, nil
 }
 
-func (r reelRepo) GetReelsPaged(ctx context.Context, userID string, filter datastore.ReelFilter, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
-	queryFilter := baseReelsFilter
-	args := map[string]interface{}{
-		"user_id": userID,
-		"cursor":  pageable.Cursor,
-		"limit":   pageable.Limit(),
+// reelSortKeys holds the SQL to order and page reels by a column, ties broken on id
+type reelSortKeys struct {
+	asc, desc string
+	// keyset comparisons against a cursor's (value, id)
+	after, atOrAfter, before, atOrBefore string
+}
+
+// newReelSortKeys orders by column in the select list and compares on key, which is
+// the column itself unless it's computed. value is how the cursor's value is bound to
+// compare against key, usually just a placeholder
+func newReelSortKeys(column, key, value string) reelSortKeys {
+	return reelSortKeys{
+		asc:        column + ` ASC, id ASC`,
+		desc:       column + ` DESC, id DESC`,
+		after:      `(` + key + `, id) > (` + value + `, ?)`,
+		atOrAfter:  `(` + key + `, id) >= (` + value + `, ?)`,
+		before:     `(` + key + `, id) < (` + value + `, ?)`,
+		atOrBefore: `(` + key + `, id) <= (` + value + `, ?)`,
 	}
+}
+
+// reelsTitleKey sorts titles ignoring case, comparing bytes rather than going by the
+// database's collation so the order is the same wherever the database runs
+const reelsTitleKey = `lower(title) COLLATE "C"`
+
+var reelSorts = map[string]reelSortKeys{
+	datastore.ReelSortCreatedAt:    newReelSortKeys("created_at", "created_at", "?"),
+	datastore.ReelSortUpdatedAt:    newReelSortKeys("updated_at", "updated_at", "?"),
+	datastore.ReelSortDeliveryDate: newReelSortKeys("delivery_date", "delivery_date", "?"),
+	datastore.ReelSortTitle:        newReelSortKeys(reelsTitleKey, reelsTitleKey, `lower(?) COLLATE "C"`),
+	// the rank takes the search query as its first argument
+	datastore.ReelSortRelevance: newReelSortKeys("search_rank", reelsSearchRank, "?"),
+}
+
+// reelFilterConditions narrows reels to a user's undeleted reels that match the filter
+func reelFilterConditions(userID string, filter datastore.ReelFilter) conditions {
+	where := conditions{}.and(reelsNotDeleted).and(reelsFilterUser, userID)
 
 	if filter.DeliveryStatus.IsValid() {
-		args["delivery_status"] = filter.DeliveryStatus
-		queryFilter = fmt.Sprintf(reelDeliveryStatusFilter, queryFilter)
+		where = where.and(reelsFilterDeliveryStatus, filter.DeliveryStatus)
+	}
+
+	if filter.DeliveryFrom.Valid {
+		where = where.and(reelsFilterDeliveryFrom, filter.DeliveryFrom.Time)
+	}
+
+	if filter.DeliveryTo.Valid {
+		where = where.and(reelsFilterDeliveryTo, filter.DeliveryTo.Time)
+	}
+
+	if filter.CreatedFrom.Valid {
+		where = where.and(reelsFilterCreatedFrom, filter.CreatedFrom.Time)
+	}
+
+	if filter.CreatedTo.Valid {
+		where = where.and(reelsFilterCreatedTo, filter.CreatedTo.Time)
+	}
+
+	if filter.Private.Valid {
+		where = where.and(reelsFilterPrivate, filter.Private.Bool)
+	}
+
+	if filter.HasRecipients.Valid {
+		if filter.HasRecipients.Bool {
+			where = where.and(reelsFilterHasRecipients)
+		} else {
+			where = where.and(reelsFilterNoRecipients)
+		}
+	}
+
+	if filter.RecipientEmail != "" {
+		where = where.and(reelsFilterRecipientEmail, filter.RecipientEmail)
+	}
+
+	if filter.Query != "" {
+		where = where.and(reelsFilterSearch, filter.Query)
 	}
 
-	query := fetchReelsPaged
-	query = fmt.Sprintf(query, queryFilter)
+	return where
+}
 
-	rows, err := r.db.NamedQueryContext(ctx, query, args)
+func (r reelRepo) GetReelsPaged(ctx context.Context, userID string, filter datastore.ReelFilter, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
+	sort, err := datastore.ReelSort(pageable.Sort, filter)
 	if err != nil {
 		return nil, datastore.PaginationData{}, err
 	}
 
+	cursor, err := pageable.Position(sort)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
+
+	// a previous page is fetched in reverse from the cursor
+	pageSort := sort
+	if cursor.Direction == datastore.PrevPage {
+		pageSort = sort.Reverse()
+	}
+
+	keys := reelSorts[sort.Field]
+	order, ahead, behind := keys.asc, keys.after, keys.atOrBefore
+	if pageSort.Order == datastore.SortDesc {
+		order, ahead, behind = keys.desc, keys.before, keys.atOrAfter
+	}
+
+	// page holds the reels ahead of the cursor, behindCursor the ones already passed
+	where := reelFilterConditions(userID, filter)
+	page, behindCursor := where, where
+
+	if cursor.ID != "" {
+		var keyArgs []any
+		switch sort.Field {
+		case datastore.ReelSortTitle:
+			keyArgs = []any{cursor.Value, cursor.ID}
+		case datastore.ReelSortRelevance:
+			rank, err := cursor.Float()
+			if err != nil {
+				return nil, datastore.PaginationData{}, err
+			}
+			keyArgs = []any{filter.Query, rank, cursor.ID}
+		default:
+			value, err := cursor.Time()
+			if err != nil {
+				return nil, datastore.PaginationData{}, err
+			}
+			keyArgs = []any{value, cursor.ID}
+		}
+
+		page = where.and(ahead, keyArgs...)
+		behindCursor = where.and(behind, keyArgs...)
+	}
+
+	query := fetchReels
+	var args []any
+	if filter.Query != "" {
+		query += selectSearchRank
+		args = append(args, filter.Query)
+	}
+
+	query = r.replica.Rebind(query + fromReelsWhere + page.String() + ` ORDER BY ` + order + ` LIMIT ?`)
+	args = append(append(args, page.args...), pageable.Limit())
+
 	var reels []datastore.Reel
-	for rows.Next() {
-		reel := datastore.Reel{}
-		err = rows.StructScan(&reel)
+	err = sqlx.SelectContext(ctx, r.replica, &reels, query, args...)
+	if err != nil {
+		return nil, datastore.PaginationData{}, err
+	}
 
+	var hasBehind bool
+	if cursor.ID != "" {
+		query := r.replica.Rebind(reelsExistWhere + behindCursor.String() + `)`)
+
+		err = sqlx.GetContext(ctx, r.replica, &hasBehind, query, behindCursor.args...)
 		if err != nil {
 			return nil, datastore.PaginationData{}, err
 		}
-
-		reels = append(reels, reel)
 	}
 
-	ids := make([]string, len(reels))
+	positions := make([]datastore.Cursor, len(reels))
 	for i := range reels {
-		ids[i] = reels[i].UID
+		positions[i] = datastore.ReelPosition(reels[i], sort)
 	}
 
-	if len(reels) > pageable.PerPage {
-		reels = reels[:len(reels)-1]
-	}
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	reels = reels[:n]
 
-	pagination := &datastore.PaginationData{}
-	pagination.Build(pageable, ids)
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(reels)
+	}
 
-	return reels, *pagination, nil
+	return reels, pagination, nil
 }
 
-func (r reelRepo) CreateReel(ctx context.Context, reel *datastore.Reel) error {
-
-	row := r.db.QueryRowxContext(ctx, createReel,
-		reel.UID,
-		reel.UserID,
-		reel.VideoID,
-		reel.Email,
-		reel.Title,
-		reel.Description,
-		reel.Private,
-		reel.Recipients,
-		reel.EmailConfirmationToken,
-		reel.DeliveryStatus,
-		reel.DeliveryDate,
-	)
+func (r reelRepo) GetReelsDueForReminder(ctx context.Context, daysBefore int, limit int) ([]datastore.Reel, error) {
+	var reels []datastore.Reel
 
-	err := row.StructScan(reel)
+	err := sqlx.SelectContext(ctx, r.db, &reels, fetchReelsDueForReminder, daysBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return reels, nil
+}
 
+func (r reelRepo) RecordReminders(ctx context.Context, reelID string, daysBefore []int) error {
+	_, err := r.db.ExecContext(ctx, recordReminders, reelID, pq.Array(daysBefore))
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	return nil
 }
 
-func (r reelRepo) UpdateReel(ctx context.Context, reel *datastore.Reel) error {
-	res, err := r.db.ExecContext(ctx, updateReel,
-		reel.UID,
-		reel.UserID,
-		reel.VideoID,
-		reel.Email,
-		reel.Title,
-		reel.Description,
-		reel.Private,
-		reel.DeliveryStatus,
-		reel.DeliveryDate,
-		reel.EmailConfirmationToken)
+func (r reelRepo) CreateReel(ctx context.Context, reel *datastore.Reel, messages ...datastore.OutboxMessage) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		_, err := tx.ExecContext(ctx, createReel,
+			reel.UID,
+			reel.UserID,
+			reel.VideoID,
+			reel.Email,
+			reel.Title,
+			reel.Description,
+			reel.Private,
+			reel.EmailConfirmationToken,
+			reel.DeliveryStatus,
+			reel.DeliveryDate,
+			reel.SuppressReminders,
+			reel.PublicOptInAt,
+			reel.ContributionDeadline,
+		)
 
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+		if err := insertRecipients(ctx, tx, reel.UID, reel.Recipients); err != nil {
+			return err
+		}
 
-	if rowsAffected < 1 {
-		return ErrReelNotUpdated
-	}
+		err = tx.QueryRowxContext(ctx, fmt.Sprintf(fetchReel, "id"), reel.UID).StructScan(reel)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if err := recordReelAudit(ctx, tx, reel.UID, datastore.ReelCreatedAction, datastore.NewReelChanges(*reel)); err != nil {
+			return err
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
+}
+
+func (r reelRepo) UpdateReel(ctx context.Context, reel *datastore.Reel, messages ...datastore.OutboxMessage) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		// the row stays locked until commit, so the audit entry diffs against the version being replaced
+		before := datastore.Reel{}
+		err := tx.QueryRowxContext(ctx, fetchReelForUpdate, reel.UID).StructScan(&before)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return datastore.ErrReelNotUpdated
+			}
+			return err
+		}
+
+		if before.Version != reel.Version {
+			return datastore.ErrVersionConflict
+		}
+
+		row := tx.QueryRowxContext(ctx, updateReel,
+			reel.UID,
+			reel.UserID,
+			reel.VideoID,
+			reel.Email,
+			reel.Title,
+			reel.Description,
+			reel.Private,
+			reel.DeliveryStatus,
+			reel.DeliveryDate,
+			reel.EmailConfirmationToken,
+			reel.SuppressReminders,
+			reel.ContributionDeadline,
+			reel.Version)
+
+		if err := row.Scan(&reel.Version, &reel.UpdatedAt); err != nil {
+			return err
+		}
+
+		if changes := datastore.DiffReels(before, *reel); len(changes) > 0 {
+			if err := recordReelAudit(ctx, tx, reel.UID, datastore.ReelUpdatedAction, changes); err != nil {
+				return err
+			}
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
 }
 
 func (r reelRepo) AssignReelsToUserByEmail(ctx context.Context, email string, userID string) error {
-	_, err := r.db.ExecContext(ctx, assignReelsToUserByEmail, email, userID)
-	if err != nil {
-		return err
-	}
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		var reelIDs []string
+		if err := sqlx.SelectContext(ctx, tx, &reelIDs, assignReelsToUserByEmail, email, userID); err != nil {
+			return err
+		}
 
-	return nil
+		changes := datastore.AuditChanges{"user_id": {To: userID}}
+		for _, reelID := range reelIDs {
+			if err := recordReelAudit(ctx, tx, reelID, datastore.ReelUpdatedAction, changes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (r reelRepo) AddRecipients(ctx context.Context, reel *datastore.Reel, newRecipients datastore.Recipients) error {
 	reel.Recipients = append(reel.Recipients, newRecipients...)
 
-	res, err := r.db.ExecContext(ctx, addRecipients, reel.UID, newRecipients)
-	if err != nil {
-		return err
-	}
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		var version int
+		err := tx.QueryRowxContext(ctx, touchReel, reel.UID).Scan(&version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return datastore.ErrReelRecipientsNotAdded
+			}
+			return err
+		}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+		if err := insertRecipients(ctx, tx, reel.UID, newRecipients); err != nil {
+			return err
+		}
 
-	if rowsAffected < 1 {
-		return ErrReelRecipientsNotAdded
-	}
+		changes := datastore.AuditChanges{"recipients": {To: datastore.RecipientEmails(newRecipients)}}
+		if err := recordReelAudit(ctx, tx, reel.UID, datastore.RecipientsAddedAction, changes); err != nil {
+			return err
+		}
 
-	return nil
+		reel.Version = version
+		return nil
+	})
 }
 
 func (r reelRepo) DeleteRecipient(ctx context.Context, reel *datastore.Reel, recipientID string) error {
@@ -307,37 +625,231 @@ func (r reelRepo) DeleteRecipient(ctx context.Context, reel *datastore.Reel, rec
 		return datastore.ErrRecipientNotFound
 	}
 
-	res, err := r.db.ExecContext(ctx, deleteRecipient, reel.UID, recipientID)
-	if err != nil {
-		return err
-	}
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		res, err := tx.ExecContext(ctx, deleteRecipient, reel.UID, recipientID)
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected < 1 {
+			return datastore.ErrReelRecipientNotDeleted
+		}
+
+		changes := datastore.AuditChanges{"recipients": {From: []string{recipient.Email}}}
+		if err := recordReelAudit(ctx, tx, reel.UID, datastore.RecipientRemovedAction, changes); err != nil {
+			return err
+		}
+
+		return tx.QueryRowxContext(ctx, touchReel, reel.UID).Scan(&reel.Version)
+	})
+}
 
-	if rowsAffected < 1 {
-		return ErrReelRecipientNotDeleted
+func (r reelRepo) DeleteReel(ctx context.Context, reelID string) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		res, err := tx.ExecContext(ctx, deleteReel, reelID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected < 1 {
+			return datastore.ErrReelNotDeleted
+		}
+
+		return recordReelAudit(ctx, tx, reelID, datastore.ReelDeletedAction, nil)
+	})
+}
+
+func insertRecipients(ctx context.Context, q sqlx.ExecerContext, reelID string, recipients datastore.Recipients) error {
+	for _, recipient := range recipients {
+		createdAt := recipient.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		_, err := q.ExecContext(ctx, createRecipient,
+			recipient.UID,
+			reelID,
+			recipient.Email,
+			createdAt,
+			recipient.DeletedAt,
+		)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (r reelRepo) DeleteReel(ctx context.Context, reelID string) error {
-	res, err := r.db.ExecContext(ctx, deleteReel, reelID)
+// trashSortKeys pages the trash, most recently deleted first
+var trashSortKeys = newReelSortKeys("deleted_at", "deleted_at", "?")
+
+// GetDeletedReelsPaged reads from the primary, the trash is usually looked at right
+// after deleting a reel and a lagging replica wouldn't list it yet
+func (r reelRepo) GetDeletedReelsPaged(ctx context.Context, userID string, deletedAfter time.Time, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
+	cursor, err := pageable.FixedPosition(datastore.TrashSort)
 	if err != nil {
-		return err
+		return nil, datastore.PaginationData{}, err
+	}
+
+	order, ahead, behind := trashSortKeys.desc, trashSortKeys.before, trashSortKeys.atOrAfter
+	if cursor.Direction == datastore.PrevPage {
+		order, ahead, behind = trashSortKeys.asc, trashSortKeys.after, trashSortKeys.atOrBefore
+	}
+
+	where := conditions{}.and(reelsDeleted).and(reelsFilterUser, userID).and(reelsDeletedAfter, deletedAfter)
+	page, behindCursor := where, where
+
+	if cursor.ID != "" {
+		deletedAt, err := cursor.Time()
+		if err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+
+		page = where.and(ahead, deletedAt, cursor.ID)
+		behindCursor = where.and(behind, deletedAt, cursor.ID)
+	}
+
+	query := r.db.Rebind(fetchReels + fromReelsWhere + page.String() + ` ORDER BY ` + order + ` LIMIT ?`)
+	args := append(append([]any{}, page.args...), pageable.Limit())
+
+	var reels []datastore.Reel
+	if err := sqlx.SelectContext(ctx, r.db, &reels, query, args...); err != nil {
+		return nil, datastore.PaginationData{}, err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	var hasBehind bool
+	if cursor.ID != "" {
+		query := r.db.Rebind(reelsExistWhere + behindCursor.String() + `)`)
+		if err := sqlx.GetContext(ctx, r.db, &hasBehind, query, behindCursor.args...); err != nil {
+			return nil, datastore.PaginationData{}, err
+		}
+	}
+
+	positions := make([]datastore.Cursor, len(reels))
+	for i := range reels {
+		positions[i] = datastore.TrashPosition(reels[i])
+	}
+
+	n, pagination := datastore.Page(pageable, cursor, positions, hasBehind)
+	reels = reels[:n]
+
+	if cursor.Direction == datastore.PrevPage {
+		slices.Reverse(reels)
+	}
+
+	return reels, pagination, nil
+}
+
+func (r reelRepo) GetDeletedReelByID(ctx context.Context, reelID string) (*datastore.Reel, error) {
+	reel := &datastore.Reel{}
+	err := r.db.QueryRowxContext(ctx, fetchDeletedReel, reelID).StructScan(reel)
+
 	if err != nil {
-		return err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrReelNotFound
+		}
+		return nil, err
 	}
 
-	if rowsAffected < 1 {
-		return ErrReelNotDeleted
+	return reel, nil
+}
+
+// RestoreReel brings a reel back from the trash. It fails with ErrReelVideoInUse when another
+// active reel has since been created from the same video
+func (r reelRepo) RestoreReel(ctx context.Context, reel *datastore.Reel, deletedAfter time.Time) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		err := tx.QueryRowxContext(ctx, restoreReel, reel.UID, deletedAfter).Scan(&reel.Version, &reel.UpdatedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return datastore.ErrReelNotRestored
+			}
+			return err
+		}
+
+		reel.DeletedAt = null.Time{}
+
+		return recordReelAudit(ctx, tx, reel.UID, datastore.ReelRestoredAction, nil)
+	})
+}
+
+func (r reelRepo) PurgeDeletedReels(ctx context.Context, deletedBefore time.Time, limit int) (int, []datastore.Video, error) {
+	var purged int
+	var videos []datastore.Video
+
+	err := runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		rows, err := tx.QueryxContext(ctx, fetchReelsToPurge, deletedBefore, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var reelIDs, videoIDs []string
+		for rows.Next() {
+			var reelID, videoID string
+			if err := rows.Scan(&reelID, &videoID); err != nil {
+				return err
+			}
+
+			reelIDs = append(reelIDs, reelID)
+			videoIDs = append(videoIDs, videoID)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(reelIDs) == 0 {
+			return nil
+		}
+
+		var replyVideoIDs []string
+		if err := sqlx.SelectContext(ctx, tx, &replyVideoIDs, purgeReelVideoResponses, pq.Array(reelIDs)); err != nil {
+			return err
+		}
+		videoIDs = append(videoIDs, replyVideoIDs...)
+
+		var contributedVideoIDs []string
+		if err := sqlx.SelectContext(ctx, tx, &contributedVideoIDs, purgeReelContributions, pq.Array(reelIDs)); err != nil {
+			return err
+		}
+		videoIDs = append(videoIDs, contributedVideoIDs...)
+
+		for _, query := range []string{purgeReelViewTokens, purgeReelReminders, purgeReelRecipients, purgeReelInboxHidden, purgeReelShareLinks, purgeReelResponses, purgeReelContributors, purgeReels} {
+			if _, err := tx.ExecContext(ctx, query, pq.Array(reelIDs)); err != nil {
+				return err
+			}
+		}
+
+		if err := sqlx.SelectContext(ctx, tx, &videos, purgeUnreferencedVideos, pq.Array(videoIDs)); err != nil {
+			return err
+		}
+
+		// the audit log is kept, ending with the purge
+		for _, reelID := range reelIDs {
+			if err := recordReelAudit(ctx, tx, reelID, datastore.ReelPurgedAction, nil); err != nil {
+				return err
+			}
+		}
+
+		purged = len(reelIDs)
+		return nil
+	})
+
+	if err != nil {
+		return 0, nil, err
 	}
 
-	return nil
+	return purged, videos, nil
 }
@@ -97,32 +97,32 @@ func TestGetReelsPaged(t *testing.T) {
 	}
 
 	// Fetch with empty filter
-	pageable := datastore.Pageable{PerPage: 20, Cursor: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"}
+	pageable := datastore.Pageable{PerPage: 20}
 	filter := datastore.ReelFilter{}
 	reels, PaginationData, err := reelRepo.GetReelsPaged(context.Background(), user.UID, filter, pageable)
 	require.NoError(t, err)
 
 	require.Len(t, reels, 8)
-	require.False(t, PaginationData.HasMorePages)
+	require.False(t, PaginationData.HasNextPage)
 
 	// Filter with delivery status scheduled
-	pageable = datastore.Pageable{PerPage: 4, Cursor: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"}
+	pageable = datastore.Pageable{PerPage: 4}
 	filter = datastore.ReelFilter{DeliveryStatus: datastore.ScheduledReelStatus}
 	reels, PaginationData, err = reelRepo.GetReelsPaged(context.Background(), user.UID, filter, pageable)
 	require.NoError(t, err)
 
 	require.Len(t, reels, 3)
-	require.False(t, PaginationData.HasMorePages)
+	require.False(t, PaginationData.HasNextPage)
 
 	// Filter with delivery status unconfirmed
-	pageable = datastore.Pageable{PerPage: 3, Cursor: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"}
+	pageable = datastore.Pageable{PerPage: 3}
 	filter = datastore.ReelFilter{DeliveryStatus: datastore.UnconfirmedReelStatus}
 	reels, PaginationData, err = reelRepo.GetReelsPaged(context.Background(), user.UID, filter, pageable)
 	require.NoError(t, err)
 
 	require.Len(t, reels, 3)
-	require.True(t, PaginationData.HasMorePages)
-	require.NotEmpty(t, PaginationData.Cursor)
+	require.True(t, PaginationData.HasNextPage)
+	require.NotEmpty(t, PaginationData.NextCursor)
 
 }
 
@@ -149,7 +149,7 @@ func TestAssignReelsToUserByEmail(t *testing.T) {
 
 	defaultPerpage := 10
 
-	pageable := datastore.Pageable{Cursor: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", PerPage: defaultPerpage}
+	pageable := datastore.Pageable{PerPage: defaultPerpage}
 	filter := datastore.ReelFilter{}
 	reels, _, err := reelRepo.GetReelsPaged(context.Background(), user.UID, filter, pageable)
 
@@ -159,7 +159,7 @@ func TestAssignReelsToUserByEmail(t *testing.T) {
 	err = reelRepo.AssignReelsToUserByEmail(context.Background(), user.Email, user.UID)
 	require.NoError(t, err)
 
-	pageable = datastore.Pageable{Cursor: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", PerPage: defaultPerpage}
+	pageable = datastore.Pageable{PerPage: defaultPerpage}
 	filter = datastore.ReelFilter{}
 	reels, _, err = reelRepo.GetReelsPaged(context.Background(), user.UID, filter, pageable)
 
@@ -190,6 +190,7 @@ func TestUpdateReel(t *testing.T) {
 		EmailConfirmationToken: "jbkl",
 		DeliveryStatus:         datastore.DeliveredReelStatus,
 		DeliveryDate:           time.Now().Add(time.Hour * 24 * 7).UTC(),
+		Version:                reel.Version,
 	}
 
 	require.NoError(t, reelRepo.UpdateReel(context.Background(), updatedReel))
@@ -290,6 +291,49 @@ func TestDeleteRecipient(t *testing.T) {
 	require.Nil(t, dbRecipient)
 }
 
+func TestGetReelsDueForReminder(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	reelRepo := NewReelRepo(db)
+	user := seedUser(t, db)
+
+	createReel := func(status datastore.ReelDeliveryStatus, deliveryDate time.Time, suppress bool) *datastore.Reel {
+		video := seedVideo(t, db)
+		reel := generateReel(video.UID, user.UID)
+		reel.DeliveryStatus = status
+		reel.DeliveryDate = deliveryDate
+		reel.SuppressReminders = suppress
+
+		require.NoError(t, reelRepo.CreateReel(context.Background(), reel))
+		return reel
+	}
+
+	due := createReel(datastore.ScheduledReelStatus, time.Now().Add(time.Hour*24*5), false)
+	createReel(datastore.ScheduledReelStatus, time.Now().Add(time.Hour*24*10), false)
+	createReel(datastore.ScheduledReelStatus, time.Now().Add(time.Hour*24*5), true)
+	createReel(datastore.UnconfirmedReelStatus, time.Now().Add(time.Hour*24*5), false)
+
+	reels, err := reelRepo.GetReelsDueForReminder(context.Background(), 7, 10)
+	require.NoError(t, err)
+
+	require.Len(t, reels, 1)
+	require.Equal(t, due.UID, reels[0].UID)
+
+	// recorded reminders aren't due again
+	require.NoError(t, reelRepo.RecordReminders(context.Background(), due.UID, []int{7, 30}))
+	require.NoError(t, reelRepo.RecordReminders(context.Background(), due.UID, []int{7}))
+
+	reels, err = reelRepo.GetReelsDueForReminder(context.Background(), 7, 10)
+	require.NoError(t, err)
+	require.Len(t, reels, 0)
+
+	reels, err = reelRepo.GetReelsDueForReminder(context.Background(), 30, 10)
+	require.NoError(t, err)
+	require.Len(t, reels, 1)
+	require.NotEqual(t, due.UID, reels[0].UID)
+}
+
 func generateReel(videoID, userID string) *datastore.Reel {
 	return &datastore.Reel{
 		UID:                    ulid.Make().String(),
@@ -360,3 +404,16 @@ func seedVideo(t *testing.T, db database.Database) *datastore.Video {
 
 	return video
 }
+
+func seedReel(t *testing.T, db database.Database) *datastore.Reel {
+	user := seedUser(t, db)
+	video := seedVideo(t, db)
+	reel := generateReel(video.UID, user.UID)
+
+	reelRepo := NewReelRepo(db)
+
+	err := reelRepo.CreateReel(context.Background(), reel)
+	require.NoError(t, err)
+
+	return reel
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createResponse = `
+	INSERT INTO reel_responses (id, reel_id, recipient_id, kind, body, video_id, moderation_status)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	RETURNING *;
+	`
+
+	fetchResponseById = `SELECT * FROM reel_responses WHERE id = $1;`
+
+	fetchResponsesByReel = `
+	SELECT * FROM reel_responses
+	WHERE reel_id = $1 AND moderation_status = $2
+	ORDER BY created_at, id;
+	`
+
+	moderateResponse = `
+	UPDATE reel_responses SET
+		moderation_status = $2
+	WHERE id = $1 AND moderation_status = 'pending';
+	`
+)
+
+type responseRepo struct {
+	db sqlx.ExtContext
+}
+
+func NewResponseRepo(db database.Database) datastore.ResponseRepository {
+	return &responseRepo{db: db.GetDB()}
+}
+
+func (r responseRepo) GetResponseByID(ctx context.Context, id string) (*datastore.ReelResponse, error) {
+	response := &datastore.ReelResponse{}
+	if err := r.db.QueryRowxContext(ctx, fetchResponseById, id).StructScan(response); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrResponseNotFound
+		}
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (r responseRepo) GetResponsesByReel(ctx context.Context, reelID string, status datastore.ModerationStatus) ([]datastore.ReelResponse, error) {
+	responses := []datastore.ReelResponse{}
+	if err := sqlx.SelectContext(ctx, r.db, &responses, fetchResponsesByReel, reelID, status); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+func (r responseRepo) CreateResponse(ctx context.Context, response *datastore.ReelResponse, messages ...datastore.OutboxMessage) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		row := tx.QueryRowxContext(ctx, createResponse,
+			response.UID,
+			response.ReelID,
+			response.RecipientID,
+			response.Kind,
+			response.Body,
+			response.VideoID,
+			response.ModerationStatus,
+		)
+
+		if err := row.StructScan(response); err != nil {
+			return err
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
+}
+
+func (r responseRepo) ModerateResponse(ctx context.Context, id string, status datastore.ModerationStatus, messages ...datastore.OutboxMessage) error {
+	return runInTx(ctx, r.db, func(tx sqlx.ExtContext) error {
+		result, err := tx.ExecContext(ctx, moderateResponse, id, status)
+		if err != nil {
+			return err
+		}
+
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return datastore.ErrResponseNotFound
+		}
+
+		return enqueueMessages(ctx, tx, messages)
+	})
+}
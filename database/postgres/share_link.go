@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createShareLink = `
+	INSERT INTO share_links (id, reel_id, password_hash, expires_at, max_views)
+	VALUES ($1,$2,$3,$4,$5)
+	RETURNING *;
+	`
+
+	fetchShareLinkById = `SELECT * FROM share_links WHERE id = $1;`
+
+	fetchShareLinksByReel = `
+	SELECT * FROM share_links
+	WHERE reel_id = $1
+	ORDER BY created_at DESC, id DESC;
+	`
+
+	recordShareLinkView = `
+	UPDATE share_links SET
+		views = views + 1,
+		failed_password_attempts = 0
+	WHERE id = $1
+	AND revoked_at IS NULL
+	AND (expires_at IS NULL OR expires_at > NOW())
+	AND (max_views IS NULL OR views < max_views)
+	RETURNING *;
+	`
+
+	recordSharePasswordFailure = `
+	UPDATE share_links SET
+		failed_password_attempts = CASE WHEN failed_password_attempts + 1 >= $2 THEN 0 ELSE failed_password_attempts + 1 END,
+		locked_until = CASE WHEN failed_password_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3) ELSE locked_until END
+	WHERE id = $1
+	RETURNING *;
+	`
+
+	revokeShareLink = `
+	UPDATE share_links SET
+		revoked_at = COALESCE(revoked_at, NOW())
+	WHERE id = $1 AND reel_id = $2;
+	`
+)
+
+type shareLinkRepo struct {
+	db sqlx.ExtContext
+}
+
+func NewShareLinkRepo(db database.Database) datastore.ShareLinkRepository {
+	return &shareLinkRepo{db: db.GetDB()}
+}
+
+func (s shareLinkRepo) GetShareLinkByID(ctx context.Context, id string) (*datastore.ShareLink, error) {
+	link := &datastore.ShareLink{}
+	if err := s.db.QueryRowxContext(ctx, fetchShareLinkById, id).StructScan(link); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s shareLinkRepo) GetShareLinksByReel(ctx context.Context, reelID string) ([]datastore.ShareLink, error) {
+	links := []datastore.ShareLink{}
+	if err := sqlx.SelectContext(ctx, s.db, &links, fetchShareLinksByReel, reelID); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (s shareLinkRepo) CreateShareLink(ctx context.Context, link *datastore.ShareLink) error {
+	row := s.db.QueryRowxContext(ctx, createShareLink,
+		link.UID,
+		link.ReelID,
+		link.PasswordHash,
+		link.ExpiresAt,
+		link.MaxViews,
+	)
+
+	return classifyError(row.StructScan(link))
+}
+
+func (s shareLinkRepo) RecordShareLinkView(ctx context.Context, id string) (*datastore.ShareLink, error) {
+	link := &datastore.ShareLink{}
+	if err := s.db.QueryRowxContext(ctx, recordShareLinkView, id).StructScan(link); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s shareLinkRepo) RecordSharePasswordFailure(ctx context.Context, id string, maxAttempts int, lockout time.Duration) (*datastore.ShareLink, error) {
+	link := &datastore.ShareLink{}
+	err := s.db.QueryRowxContext(ctx, recordSharePasswordFailure, id, maxAttempts, lockout.Seconds()).StructScan(link)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s shareLinkRepo) RevokeShareLink(ctx context.Context, reelID string, id string) error {
+	result, err := s.db.ExecContext(ctx, revokeShareLink, id, reelID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return datastore.ErrShareLinkNotFound
+	}
+
+	return nil
+}
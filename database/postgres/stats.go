@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	countReelsByStatus = `
+	SELECT delivery_status, COUNT(*)
+	FROM reels
+	WHERE user_id = $1 AND deleted_at IS NULL
+	GROUP BY delivery_status;
+	`
+
+	fetchReelUsage = `
+	SELECT
+		(
+			SELECT COUNT(*)
+			FROM reel_recipients rr
+			JOIN reels ON reels.id = rr.reel_id
+			WHERE reels.user_id = $1 AND reels.deleted_at IS NULL AND rr.deleted_at IS NULL
+		) AS recipients,
+		(
+			SELECT COALESCE(SUM(videos.size_mb), 0)
+			FROM videos
+			WHERE videos.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM reels
+				WHERE reels.video_id = videos.id AND reels.user_id = $1 AND reels.deleted_at IS NULL
+			)
+		) AS storage_mb;
+	`
+
+	countDeliveriesByMonth = `
+	SELECT to_char(delivery_date AT TIME ZONE 'UTC', 'YYYY-MM') AS month, COUNT(*)
+	FROM reels
+	WHERE user_id = $1
+	AND deleted_at IS NULL
+	AND delivery_status = 'scheduled'
+	AND delivery_date >= $2
+	AND delivery_date < $3
+	GROUP BY month;
+	`
+)
+
+func (r reelRepo) GetReelStats(ctx context.Context, userID string) (*datastore.ReelStats, error) {
+	rows, err := r.replica.QueryxContext(ctx, countReelsByStatus, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byStatus := map[datastore.ReelDeliveryStatus]int{}
+	for rows.Next() {
+		var status datastore.ReelDeliveryStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		byStatus[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var usage struct {
+		Recipients int     `db:"recipients"`
+		StorageMB  float64 `db:"storage_mb"`
+	}
+
+	if err := sqlx.GetContext(ctx, r.replica, &usage, fetchReelUsage, userID); err != nil {
+		return nil, err
+	}
+
+	return datastore.NewReelStats(byStatus, usage.Recipients, usage.StorageMB), nil
+}
+
+func (r reelRepo) GetDeliveryTimeline(ctx context.Context, userID string, from time.Time, months int) ([]datastore.MonthlyDeliveries, error) {
+	rows, err := r.replica.QueryxContext(ctx, countDeliveriesByMonth, userID, from, datastore.TimelineWindow(from, months))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var month string
+		var count int
+		if err := rows.Scan(&month, &count); err != nil {
+			return nil, err
+		}
+		counts[month] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return datastore.NewDeliveryTimeline(from, months, counts), nil
+}
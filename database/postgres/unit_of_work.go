@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+type unitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db database.Database) datastore.UnitOfWork {
+	return &unitOfWork{db: db.GetDB()}
+}
+
+func (u unitOfWork) RunInTx(ctx context.Context, fn func(repos datastore.Repositories) error) error {
+	return runInTx(ctx, u.db, func(tx sqlx.ExtContext) error {
+		return fn(newRepositories(tx))
+	})
+}
+
+// newRepositories binds repositories to db for both reads and writes, so reads inside a
+// transaction see its writes
+func newRepositories(db sqlx.ExtContext) datastore.Repositories {
+	return datastore.Repositories{
+		Users:         &userRepo{db: db},
+		Reels:         &reelRepo{db: db, replica: db},
+		Videos:        &videoRepo{db: db},
+		Outbox:        &outboxRepo{db: db},
+		ViewTokens:    &viewTokenRepo{db: db},
+		Audit:         &auditRepo{replica: db},
+		Inbox:         &inboxRepo{db: db, replica: db},
+		Gallery:       &galleryRepo{replica: db},
+		Shares:        &shareLinkRepo{db: db},
+		Responses:     &responseRepo{db: db},
+		Contributions: &contributionRepo{db: db},
+	}
+}
+
+// runInTx runs fn in a new transaction, or in the current one when db is already a transaction.
+// The transaction is rolled back if fn returns an error or panics. Errors from postgres,
+// including those raised on commit, are classified into datastore errors
+func runInTx(ctx context.Context, db sqlx.ExtContext, fn func(tx sqlx.ExtContext) error) (err error) {
+	sqlDB, ok := db.(*sqlx.DB)
+	if !ok {
+		return classifyError(fn(db))
+	}
+
+	tx, err := sqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return classifyError(err)
+	}
+
+	return classifyError(tx.Commit())
+}
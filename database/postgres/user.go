@@ -5,27 +5,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/ayo-awe/memoreel-be/database"
 	"github.com/ayo-awe/memoreel-be/datastore"
 	"github.com/jmoiron/sqlx"
 )
 
-var (
-	ErrUserNotCreated = errors.New("user could not be created")
-	ErrUserNotUpdated = errors.New("user could not be updated")
-	ErrUserNotDeleted = errors.New("user could not be deleted")
-)
-
 const (
 	createUser = `
 	INSERT INTO users (
 		id, first_name, last_name,
 		email, password, email_verified, reset_password_token,
 		email_verification_token, reset_password_expires_at,
-		email_verification_expires_at
-	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		email_verification_expires_at, calendar_token
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
 	RETURNING *;
 	`
 
@@ -40,8 +33,18 @@ const (
 		email_verification_token = $8,
 		reset_password_expires_at = $9,
 		email_verification_expires_at = $10,
-		updated_at = NOW()
-	WHERE id = $1 AND deleted_at IS NULL
+		calendar_token = $11,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND version = $12
+	RETURNING version, updated_at
+	`
+
+	userExists = `
+	SELECT EXISTS (
+		SELECT 1 FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	);
 	`
 
 	deleteUser = `
@@ -62,16 +65,18 @@ const (
 		email_verification_token,
 		reset_password_expires_at,
 		email_verification_expires_at,
+		calendar_token,
 		created_at,
 		updated_at,
-		deleted_at
+		deleted_at,
+		version
 	FROM users
 	WHERE %s = $1 AND deleted_at IS NULL
 	`
 )
 
 type userRepo struct {
-	db *sqlx.DB
+	db sqlx.ExtContext
 }
 
 func NewUserRepo(d database.Database) datastore.UserRepository {
@@ -134,66 +139,81 @@ func (u userRepo) GetUserByResetPasswordToken(ctx context.Context, userID string
 	return user, nil
 }
 
-func (u userRepo) CreateUser(ctx context.Context, user *datastore.User) error {
-
-	row := u.db.QueryRowxContext(ctx, createUser,
-		user.UID,
-		user.Firstname,
-		user.Lastname,
-		user.Email,
-		user.Password,
-		user.EmailVerified,
-		user.ResetPasswordToken,
-		user.EmailVerificationToken,
-		user.ResetPasswordExpiresAt,
-		user.EmailVerificationExpiresAt)
-
-	if err := row.StructScan(user); err != nil {
-		if strings.Contains(err.Error(), "duplicate") {
-			return datastore.ErrDuplicateUserEmail
+func (u userRepo) GetUserByCalendarToken(ctx context.Context, token string) (*datastore.User, error) {
+	user := &datastore.User{}
+
+	err := u.db.QueryRowxContext(ctx, fmt.Sprintf(fetchUser, "calendar_token"), token).StructScan(user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrUserNotFound
 		}
-		return err
+		return nil, err
 	}
 
-	return nil
+	return user, nil
 }
 
-func (u userRepo) UpdateUser(ctx context.Context, user *datastore.User) error {
-
-	res, err := u.db.ExecContext(ctx, updateUser,
-		user.UID,
-		user.Firstname,
-		user.Lastname,
-		user.Email,
-		user.Password,
-		user.EmailVerified,
-		user.ResetPasswordToken,
-		user.EmailVerificationToken,
-		user.ResetPasswordExpiresAt,
-		user.EmailVerificationExpiresAt)
-
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+func (u userRepo) CreateUser(ctx context.Context, user *datastore.User, messages ...datastore.OutboxMessage) error {
+	return withOutbox(ctx, u.db, messages, func(q sqlx.ExtContext) error {
+		row := q.QueryRowxContext(ctx, createUser,
+			user.UID,
+			user.Firstname,
+			user.Lastname,
+			user.Email,
+			user.Password,
+			user.EmailVerified,
+			user.ResetPasswordToken,
+			user.EmailVerificationToken,
+			user.ResetPasswordExpiresAt,
+			user.EmailVerificationExpiresAt,
+			user.CalendarToken)
+
+		return row.StructScan(user)
+	})
+}
 
-	if rowsAffected < 1 {
-		return ErrUserNotUpdated
-	}
+func (u userRepo) UpdateUser(ctx context.Context, user *datastore.User, messages ...datastore.OutboxMessage) error {
+	return withOutbox(ctx, u.db, messages, func(q sqlx.ExtContext) error {
+		row := q.QueryRowxContext(ctx, updateUser,
+			user.UID,
+			user.Firstname,
+			user.Lastname,
+			user.Email,
+			user.Password,
+			user.EmailVerified,
+			user.ResetPasswordToken,
+			user.EmailVerificationToken,
+			user.ResetPasswordExpiresAt,
+			user.EmailVerificationExpiresAt,
+			user.CalendarToken,
+			user.Version)
+
+		err := row.Scan(&user.Version, &user.UpdatedAt)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			// nothing was updated, either the user doesn't exist or its version has moved on
+			var exists bool
+			if err := sqlx.GetContext(ctx, q, &exists, userExists, user.UID); err != nil {
+				return err
+			}
+
+			if exists {
+				return datastore.ErrVersionConflict
+			}
+			return datastore.ErrUserNotUpdated
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (u userRepo) DeleteUser(ctx context.Context, userID string) error {
-
 	res, err := u.db.ExecContext(ctx, deleteUser, userID)
-
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
@@ -202,7 +222,7 @@ func (u userRepo) DeleteUser(ctx context.Context, userID string) error {
 	}
 
 	if rowsAffected < 1 {
-		return ErrUserNotDeleted
+		return datastore.ErrUserNotDeleted
 	}
 
 	return nil
@@ -160,6 +160,7 @@ func TestUpdateUser(t *testing.T) {
 		EmailVerificationExpiresAt: null.NewTime(time.Now().Add(time.Hour).UTC(), true),
 		ResetPasswordExpiresAt:     null.NewTime(time.Now().Add(time.Hour).UTC(), true),
 		EmailVerified:              true,
+		Version:                    user.Version,
 	}
 
 	err := userRepo.UpdateUser(context.Background(), updatedUser)
@@ -170,8 +171,11 @@ func TestUpdateUser(t *testing.T) {
 	dbUser, err := userRepo.GetUserByID(context.Background(), user.UID)
 	require.NoError(t, err)
 
+	require.Equal(t, user.Version+1, dbUser.Version)
+
 	dbUser.UpdatedAt = time.Time{}
 	dbUser.CreatedAt = time.Time{}
+	updatedUser.UpdatedAt = time.Time{}
 
 	require.InDelta(t, dbUser.EmailVerificationExpiresAt.Time.Unix(), updatedUser.EmailVerificationExpiresAt.Time.Unix(), float64(time.Second))
 	require.InDelta(t, dbUser.ResetPasswordExpiresAt.Time.Unix(), updatedUser.ResetPasswordExpiresAt.Time.Unix(), float64(time.Second))
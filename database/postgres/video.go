@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/ayo-awe/memoreel-be/database"
 	"github.com/ayo-awe/memoreel-be/datastore"
@@ -23,6 +24,9 @@ const (
 		key,
 		file_format,
 		size_mb,
+		hls_master_key,
+		packaging_attempts,
+		packaging_failed_at,
 		created_at,
 		updated_at,
 		deleted_at
@@ -35,10 +39,38 @@ const (
 		key = $2,
 		file_format = $3,
 		size_mb = $4,
+		hls_master_key = $5,
 		updated_at = NOW()
 	WHERE id = $1 AND deleted_at IS NULL;
 	`
 
+	fetchVideosPendingPackaging = `
+	SELECT
+		id,
+		key,
+		file_format,
+		size_mb,
+		hls_master_key,
+		packaging_attempts,
+		packaging_failed_at,
+		created_at,
+		updated_at,
+		deleted_at
+	FROM videos
+	WHERE hls_master_key IS NULL AND deleted_at IS NULL
+		AND packaging_attempts < $2
+		AND (packaging_failed_at IS NULL OR packaging_failed_at <= NOW() - make_interval(secs => $3))
+	ORDER BY packaging_attempts, id
+	LIMIT $1;
+	`
+
+	recordPackagingFailure = `
+	UPDATE videos SET
+		packaging_attempts = packaging_attempts + 1,
+		packaging_failed_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL;
+	`
+
 	deleteVideo = `
 	UPDATE videos SET
 		deleted_at = NOW()
@@ -46,13 +78,10 @@ const (
 	`
 )
 
-var (
-	ErrVideoNotUpdated = errors.New("video could not be updated")
-	ErrVideoNotDeleted = errors.New("video could not be deleted")
-)
-
+// videoRepo reads from the primary only, videos are read back right after they're
+// uploaded and a lagging replica wouldn't have them yet
 type videoRepo struct {
-	db *sqlx.DB
+	db sqlx.ExtContext
 }
 
 func NewVideoRepo(db database.Database) datastore.VideoRepository {
@@ -74,6 +103,35 @@ func (v videoRepo) GetVideoByID(ctx context.Context, id string) (*datastore.Vide
 	return video, nil
 }
 
+func (v videoRepo) GetVideosPendingPackaging(ctx context.Context, limit int, retryAfter time.Duration) ([]datastore.Video, error) {
+	var videos []datastore.Video
+
+	err := sqlx.SelectContext(ctx, v.db, &videos, fetchVideosPendingPackaging, limit, datastore.MaxPackagingAttempts, retryAfter.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+func (v videoRepo) RecordPackagingFailure(ctx context.Context, videoID string) error {
+	res, err := v.db.ExecContext(ctx, recordPackagingFailure, videoID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected < 1 {
+		return datastore.ErrVideoNotUpdated
+	}
+
+	return nil
+}
+
 func (v videoRepo) CreateVideo(ctx context.Context, video *datastore.Video) error {
 	row := v.db.QueryRowxContext(ctx, createVideo,
 		video.UID,
@@ -84,7 +142,7 @@ func (v videoRepo) CreateVideo(ctx context.Context, video *datastore.Video) erro
 
 	err := row.StructScan(video)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	return nil
@@ -96,10 +154,11 @@ func (v videoRepo) UpdateVideo(ctx context.Context, video *datastore.Video) erro
 		video.Key,
 		video.FileFormat,
 		video.SizeMB,
+		video.HLSMasterKey,
 	)
 
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
@@ -108,7 +167,7 @@ func (v videoRepo) UpdateVideo(ctx context.Context, video *datastore.Video) erro
 	}
 
 	if rowsAffected < 1 {
-		return ErrVideoNotUpdated
+		return datastore.ErrVideoNotUpdated
 	}
 
 	return nil
@@ -117,7 +176,7 @@ func (v videoRepo) UpdateVideo(ctx context.Context, video *datastore.Video) erro
 func (v videoRepo) DeleteVideo(ctx context.Context, videoID string) error {
 	res, err := v.db.ExecContext(ctx, deleteVideo, videoID)
 	if err != nil {
-		return nil
+		return classifyError(err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
@@ -126,7 +185,7 @@ func (v videoRepo) DeleteVideo(ctx context.Context, videoID string) error {
 	}
 
 	if rowsAffected < 1 {
-		return ErrVideoNotDeleted
+		return datastore.ErrVideoNotDeleted
 	}
 
 	return nil
@@ -8,6 +8,7 @@ import (
 	"github.com/ayo-awe/memoreel-be/datastore"
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
 )
 
 func TestCreateVideo(t *testing.T) {
@@ -71,6 +72,28 @@ func TestUpdateVideo(t *testing.T) {
 	require.Equal(t, updatedVideo, dbVideo)
 }
 
+func TestGetVideosPendingPackaging(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	videoRepo := NewVideoRepo(db)
+
+	pending := generateVideo()
+	packaged := generateVideo()
+
+	require.NoError(t, videoRepo.CreateVideo(context.Background(), pending))
+	require.NoError(t, videoRepo.CreateVideo(context.Background(), packaged))
+
+	packaged.HLSMasterKey = null.StringFrom("hls/" + packaged.UID + "/master.m3u8")
+	require.NoError(t, videoRepo.UpdateVideo(context.Background(), packaged))
+
+	videos, err := videoRepo.GetVideosPendingPackaging(context.Background(), 10, time.Hour)
+	require.NoError(t, err)
+
+	require.Len(t, videos, 1)
+	require.Equal(t, pending.UID, videos[0].UID)
+}
+
 func TestDeleteVideo(t *testing.T) {
 	db, closeFn := getDB(t)
 	defer closeFn()
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ayo-awe/memoreel-be/database"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createViewToken = `
+	INSERT INTO view_tokens (id, reel_id, recipient_id, expires_at)
+	VALUES ($1,$2,$3,$4)
+	RETURNING *;
+	`
+
+	fetchViewTokenById = `
+	SELECT
+		id,
+		reel_id,
+		recipient_id,
+		expires_at,
+		revoked_at,
+		created_at
+	FROM view_tokens
+	WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW();
+	`
+
+	revokeViewTokens = `
+	UPDATE view_tokens SET
+		revoked_at = NOW()
+	WHERE reel_id = $1 AND recipient_id = $2 AND revoked_at IS NULL;
+	`
+)
+
+type viewTokenRepo struct {
+	db sqlx.ExtContext
+}
+
+func NewViewTokenRepo(db database.Database) datastore.ViewTokenRepository {
+	return &viewTokenRepo{db: db.GetDB()}
+}
+
+// GetViewTokenByID only returns tokens that have neither expired nor been revoked
+func (v viewTokenRepo) GetViewTokenByID(ctx context.Context, id string) (*datastore.ViewToken, error) {
+	token := &datastore.ViewToken{}
+	err := v.db.QueryRowxContext(ctx, fetchViewTokenById, id).StructScan(token)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrViewTokenNotFound
+		}
+
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (v viewTokenRepo) CreateViewToken(ctx context.Context, token *datastore.ViewToken) error {
+	row := v.db.QueryRowxContext(ctx, createViewToken,
+		token.UID,
+		token.ReelID,
+		token.RecipientID,
+		token.ExpiresAt,
+	)
+
+	err := row.StructScan(token)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	return nil
+}
+
+func (v viewTokenRepo) RevokeViewTokens(ctx context.Context, reelID string, recipientID string) error {
+	_, err := v.db.ExecContext(ctx, revokeViewTokens, reelID, recipientID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateViewToken(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	viewTokenRepo := NewViewTokenRepo(db)
+	reel := seedReel(t, db)
+	token := generateViewToken(reel.UID, reel.Recipients[0].UID)
+
+	require.NoError(t, viewTokenRepo.CreateViewToken(context.Background(), token))
+
+	dbToken, err := viewTokenRepo.GetViewTokenByID(context.Background(), token.UID)
+	require.NoError(t, err)
+
+	require.Equal(t, token.ReelID, dbToken.ReelID)
+	require.Equal(t, token.RecipientID, dbToken.RecipientID)
+	require.False(t, dbToken.RevokedAt.Valid)
+}
+
+func TestGetViewTokenByID(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	viewTokenRepo := NewViewTokenRepo(db)
+	reel := seedReel(t, db)
+
+	token := generateViewToken(reel.UID, reel.Recipients[0].UID)
+	_, err := viewTokenRepo.GetViewTokenByID(context.Background(), token.UID)
+	require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+
+	// expired tokens are not returned
+	expiredToken := generateViewToken(reel.UID, reel.Recipients[0].UID)
+	expiredToken.ExpiresAt = time.Now().Add(-time.Hour)
+	require.NoError(t, viewTokenRepo.CreateViewToken(context.Background(), expiredToken))
+
+	_, err = viewTokenRepo.GetViewTokenByID(context.Background(), expiredToken.UID)
+	require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+}
+
+func TestRevokeViewTokens(t *testing.T) {
+	db, closeFn := getDB(t)
+	defer closeFn()
+
+	viewTokenRepo := NewViewTokenRepo(db)
+	reel := seedReel(t, db)
+
+	revoked := generateViewToken(reel.UID, reel.Recipients[0].UID)
+	untouched := generateViewToken(reel.UID, reel.Recipients[1].UID)
+
+	require.NoError(t, viewTokenRepo.CreateViewToken(context.Background(), revoked))
+	require.NoError(t, viewTokenRepo.CreateViewToken(context.Background(), untouched))
+
+	require.NoError(t, viewTokenRepo.RevokeViewTokens(context.Background(), reel.UID, reel.Recipients[0].UID))
+
+	_, err := viewTokenRepo.GetViewTokenByID(context.Background(), revoked.UID)
+	require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+
+	_, err = viewTokenRepo.GetViewTokenByID(context.Background(), untouched.UID)
+	require.NoError(t, err)
+}
+
+func generateViewToken(reelID, recipientID string) *datastore.ViewToken {
+	return &datastore.ViewToken{
+		UID:         ulid.Make().String(),
+		ReelID:      reelID,
+		RecipientID: recipientID,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
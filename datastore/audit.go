@@ -0,0 +1,141 @@
+package datastore
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type AuditAction string
+
+const (
+	ReelCreatedAction      AuditAction = "reel.created"
+	ReelUpdatedAction      AuditAction = "reel.updated"
+	ReelDeletedAction      AuditAction = "reel.deleted"
+	ReelRestoredAction     AuditAction = "reel.restored"
+	ReelPurgedAction       AuditAction = "reel.purged"
+	RecipientsAddedAction  AuditAction = "reel.recipients_added"
+	RecipientRemovedAction AuditAction = "reel.recipient_removed"
+)
+
+// AuditChange holds the value of a field before and after a change
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(c)
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, c)
+}
+
+// AuditEntry is an append-only record of a change made to a reel
+type AuditEntry struct {
+	UID       string       `json:"id" db:"id"`
+	ReelID    string       `json:"reel_id" db:"reel_id"`
+	ActorID   null.String  `json:"actor_id" db:"actor_id"`
+	Action    AuditAction  `json:"action" db:"action"`
+	Changes   AuditChanges `json:"changes" db:"changes"`
+	RequestID null.String  `json:"request_id" db:"request_id"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Auditor identifies who is making changes, and in which request. Changes made without
+// one, like those from background jobs, are recorded without an actor
+type Auditor struct {
+	ActorID   string
+	RequestID string
+}
+
+type auditorCtxKey struct{}
+
+func WithAuditor(ctx context.Context, auditor Auditor) context.Context {
+	return context.WithValue(ctx, auditorCtxKey{}, auditor)
+}
+
+func AuditorFrom(ctx context.Context) Auditor {
+	auditor, _ := ctx.Value(auditorCtxKey{}).(Auditor)
+	return auditor
+}
+
+// NewReelAuditEntry builds an entry for a change to a reel made by the auditor in ctx
+func NewReelAuditEntry(ctx context.Context, reelID string, action AuditAction, changes AuditChanges) AuditEntry {
+	auditor := AuditorFrom(ctx)
+
+	return AuditEntry{
+		UID:       ulid.Make().String(),
+		ReelID:    reelID,
+		ActorID:   null.NewString(auditor.ActorID, auditor.ActorID != ""),
+		Action:    action,
+		Changes:   changes,
+		RequestID: null.NewString(auditor.RequestID, auditor.RequestID != ""),
+	}
+}
+
+// DiffReels lists the audited fields that differ between two versions of a reel.
+// Secrets like the email confirmation token are left out
+func DiffReels(before, after Reel) AuditChanges {
+	changes := AuditChanges{}
+
+	diff := func(field string, from, to any, equal bool) {
+		if !equal {
+			changes[field] = AuditChange{From: from, To: to}
+		}
+	}
+
+	diff("user_id", before.UserID, after.UserID, before.UserID == after.UserID)
+	diff("video_id", before.VideoID, after.VideoID, before.VideoID == after.VideoID)
+	diff("email", before.Email, after.Email, before.Email == after.Email)
+	diff("title", before.Title, after.Title, before.Title == after.Title)
+	diff("description", before.Description, after.Description, before.Description == after.Description)
+	diff("private", before.Private, after.Private, before.Private == after.Private)
+	diff("delivery_status", before.DeliveryStatus, after.DeliveryStatus, before.DeliveryStatus == after.DeliveryStatus)
+	diff("delivery_date", before.DeliveryDate, after.DeliveryDate, before.DeliveryDate.Equal(after.DeliveryDate))
+	diff("suppress_reminders", before.SuppressReminders, after.SuppressReminders, before.SuppressReminders == after.SuppressReminders)
+	diff("contribution_deadline", before.ContributionDeadline, after.ContributionDeadline, before.ContributionDeadline.Equal(after.ContributionDeadline))
+
+	return changes
+}
+
+// NewReelChanges records every audited field of a newly created reel, and its recipients
+func NewReelChanges(reel Reel) AuditChanges {
+	changes := DiffReels(Reel{}, reel)
+	for field, change := range changes {
+		changes[field] = AuditChange{To: change.To}
+	}
+
+	if len(reel.Recipients) > 0 {
+		changes["recipients"] = AuditChange{To: RecipientEmails(reel.Recipients)}
+	}
+
+	return changes
+}
+
+// RecipientEmails lists the emails of recipients, the form they're recorded in audit entries
+func RecipientEmails(recipients Recipients) []string {
+	emails := make([]string, len(recipients))
+	for i := range recipients {
+		emails[i] = recipients[i].Email
+	}
+
+	return emails
+}
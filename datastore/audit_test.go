@@ -0,0 +1,36 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffReels(t *testing.T) {
+	date := time.Now()
+	before := Reel{Title: "before", DeliveryDate: date, EmailConfirmationToken: "secret"}
+
+	after := before
+	require.Empty(t, DiffReels(before, after))
+
+	after.Title = "after"
+	after.DeliveryDate = date.In(time.FixedZone("WAT", 3600))
+	after.EmailConfirmationToken = "changed"
+
+	changes := DiffReels(before, after)
+	require.Len(t, changes, 1)
+	require.Equal(t, AuditChange{From: "before", To: "after"}, changes["title"])
+}
+
+func TestNewReelAuditEntry(t *testing.T) {
+	entry := NewReelAuditEntry(context.Background(), "reel", ReelDeletedAction, nil)
+	require.False(t, entry.ActorID.Valid)
+	require.False(t, entry.RequestID.Valid)
+
+	ctx := WithAuditor(context.Background(), Auditor{ActorID: "user", RequestID: "request"})
+	entry = NewReelAuditEntry(ctx, "reel", ReelDeletedAction, nil)
+	require.Equal(t, "user", entry.ActorID.String)
+	require.Equal(t, "request", entry.RequestID.String)
+}
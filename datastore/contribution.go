@@ -0,0 +1,55 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrContributorNotFound      = errors.New("contributor not found")
+	ErrDuplicateContributor     = errors.New("email is already a contributor to the reel")
+	ErrContributionNotFound     = errors.New("contribution not found")
+	ErrInvalidContributionOrder = errors.New("order must list every contribution to the reel exactly once")
+	ErrContributionLimitReached = errors.New("contributor can't add any more clips to the reel")
+)
+
+// ContributionStatus is the owner's decision on a contribution, only approved ones are delivered
+type ContributionStatus string
+
+const (
+	PendingContribution  ContributionStatus = "pending"
+	ApprovedContribution ContributionStatus = "approved"
+	RejectedContribution ContributionStatus = "rejected"
+)
+
+func (s ContributionStatus) IsValid() bool {
+	switch s {
+	case PendingContribution, ApprovedContribution, RejectedContribution:
+		return true
+	}
+	return false
+}
+
+// Contributor is someone the reel's owner invited by email to add clips to it
+type Contributor struct {
+	UID       string    `json:"id" db:"id"`
+	ReelID    string    `json:"reel_id" db:"reel_id"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	RemovedAt null.Time `json:"removed_at" db:"removed_at"`
+}
+
+// Contribution is a clip a contributor added to a reel. Position orders a reel's contributions,
+// they're delivered after the reel's own video
+type Contribution struct {
+	UID           string             `json:"id" db:"id"`
+	ReelID        string             `json:"reel_id" db:"reel_id"`
+	ContributorID string             `json:"contributor_id" db:"contributor_id"`
+	VideoID       string             `json:"video_id" db:"video_id"`
+	Status        ContributionStatus `json:"status" db:"status"`
+	Position      int                `json:"position" db:"position"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" db:"updated_at"`
+}
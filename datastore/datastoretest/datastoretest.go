@@ -0,0 +1,1958 @@
+// Package datastoretest provides a contract test suite every implementation
+// of the datastore repositories is expected to pass
+package datastoretest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+type Repositories struct {
+	datastore.Repositories
+	UnitOfWork datastore.UnitOfWork
+}
+
+// Factory returns repositories backed by empty storage. It's called once per test
+type Factory func(t *testing.T) Repositories
+
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("UserRepository", func(t *testing.T) { runUserTests(t, newRepos) })
+	t.Run("VideoRepository", func(t *testing.T) { runVideoTests(t, newRepos) })
+	t.Run("ReelRepository", func(t *testing.T) { runReelTests(t, newRepos) })
+	t.Run("ViewTokenRepository", func(t *testing.T) { runViewTokenTests(t, newRepos) })
+	t.Run("InboxRepository", func(t *testing.T) { runInboxTests(t, newRepos) })
+	t.Run("GalleryRepository", func(t *testing.T) { runGalleryTests(t, newRepos) })
+	t.Run("ShareLinkRepository", func(t *testing.T) { runShareLinkTests(t, newRepos) })
+	t.Run("ResponseRepository", func(t *testing.T) { runResponseTests(t, newRepos) })
+	t.Run("ContributionRepository", func(t *testing.T) { runContributionTests(t, newRepos) })
+	t.Run("UnitOfWork", func(t *testing.T) { runUnitOfWorkTests(t, newRepos) })
+}
+
+func runUserTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("GetUserNotFound", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+
+		_, err := repos.Users.GetUserByID(ctx, user.UID)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+
+		_, err = repos.Users.GetUserByEmail(ctx, user.Email)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+
+		_, err = repos.Users.GetUserByEmailVerificationToken(ctx, user.EmailVerificationToken)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+
+		_, err = repos.Users.GetUserByResetPasswordToken(ctx, user.ResetPasswordToken)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	})
+
+	t.Run("CreateAndGetUser", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+
+		require.NoError(t, repos.Users.CreateUser(ctx, user))
+		require.False(t, user.CreatedAt.IsZero())
+
+		for _, get := range []func() (*datastore.User, error){
+			func() (*datastore.User, error) { return repos.Users.GetUserByID(ctx, user.UID) },
+			func() (*datastore.User, error) { return repos.Users.GetUserByEmail(ctx, user.Email) },
+			func() (*datastore.User, error) {
+				return repos.Users.GetUserByEmailVerificationToken(ctx, user.EmailVerificationToken)
+			},
+			func() (*datastore.User, error) {
+				return repos.Users.GetUserByResetPasswordToken(ctx, user.ResetPasswordToken)
+			},
+		} {
+			found, err := get()
+			require.NoError(t, err)
+			require.Equal(t, user.UID, found.UID)
+			require.Equal(t, user.Email, found.Email)
+		}
+	})
+
+	t.Run("CreateUserDuplicateEmail", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		require.NoError(t, repos.Users.CreateUser(ctx, user))
+
+		duplicate := GenerateUser()
+		duplicate.Email = user.Email
+		require.ErrorIs(t, repos.Users.CreateUser(ctx, duplicate), datastore.ErrDuplicateUserEmail)
+
+		// the email is free again once the user is deleted
+		require.NoError(t, repos.Users.DeleteUser(ctx, user.UID))
+		require.NoError(t, repos.Users.CreateUser(ctx, duplicate))
+	})
+
+	t.Run("UpdateUser", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		require.NoError(t, repos.Users.CreateUser(ctx, user))
+
+		user.Firstname = "updated"
+		user.EmailVerified = true
+		require.NoError(t, repos.Users.UpdateUser(ctx, user))
+
+		found, err := repos.Users.GetUserByID(ctx, user.UID)
+		require.NoError(t, err)
+		require.Equal(t, "updated", found.Firstname)
+		require.True(t, found.EmailVerified)
+
+		require.ErrorIs(t, repos.Users.UpdateUser(ctx, GenerateUser()), datastore.ErrUserNotUpdated)
+	})
+
+	t.Run("GetUserByCalendarToken", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		_, err := repos.Users.GetUserByCalendarToken(ctx, "")
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+
+		user.CalendarToken = null.StringFrom(ulid.Make().String())
+		require.NoError(t, repos.Users.UpdateUser(ctx, user))
+
+		found, err := repos.Users.GetUserByCalendarToken(ctx, user.CalendarToken.String)
+		require.NoError(t, err)
+		require.Equal(t, user.UID, found.UID)
+
+		// rotating the token retires the old one
+		old := user.CalendarToken.String
+		user.CalendarToken = null.StringFrom(ulid.Make().String())
+		require.NoError(t, repos.Users.UpdateUser(ctx, user))
+
+		_, err = repos.Users.GetUserByCalendarToken(ctx, old)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	})
+
+	t.Run("UpdateUserDuplicateEmail", func(t *testing.T) {
+		repos := newRepos(t)
+		first := seedUser(t, repos)
+		second := seedUser(t, repos)
+
+		second.Email = first.Email
+		err := repos.Users.UpdateUser(ctx, second)
+		require.ErrorIs(t, err, datastore.ErrDuplicateUserEmail)
+		require.ErrorIs(t, err, datastore.ErrDuplicate)
+	})
+
+	t.Run("UpdateUserVersionConflict", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		require.NoError(t, repos.Users.CreateUser(ctx, user))
+		require.Equal(t, 1, user.Version)
+
+		stale := *user
+
+		user.Firstname = "first"
+		require.NoError(t, repos.Users.UpdateUser(ctx, user))
+		require.Equal(t, 2, user.Version)
+
+		stale.Firstname = "second"
+		require.ErrorIs(t, repos.Users.UpdateUser(ctx, &stale), datastore.ErrVersionConflict)
+
+		found, err := repos.Users.GetUserByID(ctx, user.UID)
+		require.NoError(t, err)
+		require.Equal(t, "first", found.Firstname)
+		require.Equal(t, 2, found.Version)
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		require.NoError(t, repos.Users.CreateUser(ctx, user))
+
+		require.NoError(t, repos.Users.DeleteUser(ctx, user.UID))
+
+		_, err := repos.Users.GetUserByID(ctx, user.UID)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+
+		// deleted users can't be updated or deleted again
+		require.ErrorIs(t, repos.Users.UpdateUser(ctx, user), datastore.ErrUserNotUpdated)
+		require.ErrorIs(t, repos.Users.DeleteUser(ctx, user.UID), datastore.ErrUserNotDeleted)
+	})
+}
+
+func runVideoTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetVideo", func(t *testing.T) {
+		repos := newRepos(t)
+		video := GenerateVideo()
+
+		_, err := repos.Videos.GetVideoByID(ctx, video.UID)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+
+		require.NoError(t, repos.Videos.CreateVideo(ctx, video))
+
+		found, err := repos.Videos.GetVideoByID(ctx, video.UID)
+		require.NoError(t, err)
+		require.Equal(t, video, found)
+	})
+
+	t.Run("UpdateVideo", func(t *testing.T) {
+		repos := newRepos(t)
+		video := GenerateVideo()
+		require.NoError(t, repos.Videos.CreateVideo(ctx, video))
+
+		video.FileFormat = "mkv"
+		video.HLSMasterKey = null.StringFrom("hls/" + video.UID + "/master.m3u8")
+		require.NoError(t, repos.Videos.UpdateVideo(ctx, video))
+
+		found, err := repos.Videos.GetVideoByID(ctx, video.UID)
+		require.NoError(t, err)
+		require.Equal(t, "mkv", found.FileFormat)
+		require.Equal(t, video.HLSMasterKey, found.HLSMasterKey)
+
+		require.ErrorIs(t, repos.Videos.UpdateVideo(ctx, GenerateVideo()), datastore.ErrVideoNotUpdated)
+	})
+
+	t.Run("DeleteVideo", func(t *testing.T) {
+		repos := newRepos(t)
+		video := GenerateVideo()
+		require.NoError(t, repos.Videos.CreateVideo(ctx, video))
+
+		require.NoError(t, repos.Videos.DeleteVideo(ctx, video.UID))
+
+		_, err := repos.Videos.GetVideoByID(ctx, video.UID)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+
+		require.ErrorIs(t, repos.Videos.DeleteVideo(ctx, video.UID), datastore.ErrVideoNotDeleted)
+	})
+
+	t.Run("GetVideosPendingPackaging", func(t *testing.T) {
+		repos := newRepos(t)
+
+		pending := GenerateVideo()
+		packaged := GenerateVideo()
+		deleted := GenerateVideo()
+		for _, video := range []*datastore.Video{pending, packaged, deleted} {
+			require.NoError(t, repos.Videos.CreateVideo(ctx, video))
+		}
+
+		packaged.HLSMasterKey = null.StringFrom("hls/" + packaged.UID + "/master.m3u8")
+		require.NoError(t, repos.Videos.UpdateVideo(ctx, packaged))
+		require.NoError(t, repos.Videos.DeleteVideo(ctx, deleted.UID))
+
+		videos, err := repos.Videos.GetVideosPendingPackaging(ctx, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, videos, 1)
+		require.Equal(t, pending.UID, videos[0].UID)
+	})
+
+	t.Run("RecordPackagingFailure", func(t *testing.T) {
+		repos := newRepos(t)
+
+		// broken sorts before fresh, so only the recorded failure puts it last
+		broken := GenerateVideo()
+		fresh := GenerateVideo()
+		broken.UID, fresh.UID = "0"+broken.UID[1:], "1"+fresh.UID[1:]
+		for _, video := range []*datastore.Video{broken, fresh} {
+			require.NoError(t, repos.Videos.CreateVideo(ctx, video))
+		}
+
+		require.NoError(t, repos.Videos.RecordPackagingFailure(ctx, broken.UID))
+		require.ErrorIs(t, repos.Videos.RecordPackagingFailure(ctx, ulid.Make().String()), datastore.ErrVideoNotUpdated)
+
+		// it waits out the back off
+		videos, err := repos.Videos.GetVideosPendingPackaging(ctx, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, videos, 1)
+		require.Equal(t, fresh.UID, videos[0].UID)
+
+		// then goes after videos that never failed
+		videos, err = repos.Videos.GetVideosPendingPackaging(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, videos, 1)
+		require.Equal(t, fresh.UID, videos[0].UID)
+
+		found, err := repos.Videos.GetVideoByID(ctx, broken.UID)
+		require.NoError(t, err)
+		require.Equal(t, 1, found.PackagingAttempts)
+		require.True(t, found.PackagingFailedAt.Valid)
+
+		// and is given up on eventually
+		for i := 1; i < datastore.MaxPackagingAttempts; i++ {
+			require.NoError(t, repos.Videos.RecordPackagingFailure(ctx, broken.UID))
+		}
+
+		videos, err = repos.Videos.GetVideosPendingPackaging(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, videos, 1)
+		require.Equal(t, fresh.UID, videos[0].UID)
+	})
+}
+
+func runReelTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetReel", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Equal(t, reel, found)
+
+		found, err = repos.Reels.GetReelByEmailConfirmationToken(ctx, reel.EmailConfirmationToken)
+		require.NoError(t, err)
+		require.Equal(t, reel, found)
+	})
+
+	t.Run("GetReelNotFound", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Reels.GetReelByID(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		_, err = repos.Reels.GetReelByEmailConfirmationToken(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+	})
+
+	t.Run("UpdateReel", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		reel.Title = "updated"
+		reel.DeliveryStatus = datastore.ScheduledReelStatus
+		require.NoError(t, repos.Reels.UpdateReel(ctx, reel))
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Equal(t, "updated", found.Title)
+		require.Equal(t, datastore.ScheduledReelStatus, found.DeliveryStatus)
+		require.Len(t, found.Recipients, len(reel.Recipients))
+	})
+
+	t.Run("UpdateReelVersionConflict", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+		require.Equal(t, 1, reel.Version)
+
+		stale := *reel
+
+		reel.Title = "first"
+		require.NoError(t, repos.Reels.UpdateReel(ctx, reel))
+		require.Equal(t, 2, reel.Version)
+
+		stale.Title = "second"
+		require.ErrorIs(t, repos.Reels.UpdateReel(ctx, &stale), datastore.ErrVersionConflict)
+
+		// recipient changes move the version on too
+		require.NoError(t, repos.Reels.AddRecipients(ctx, reel, GenerateRecipients(1)))
+		require.Equal(t, 3, reel.Version)
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Equal(t, "first", found.Title)
+		require.Equal(t, 3, found.Version)
+	})
+
+	t.Run("DeleteReel", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		require.NoError(t, repos.Reels.DeleteReel(ctx, reel.UID))
+
+		_, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		// soft deleted reels can't be changed
+		require.ErrorIs(t, repos.Reels.UpdateReel(ctx, reel), datastore.ErrReelNotUpdated)
+		require.ErrorIs(t, repos.Reels.AddRecipients(ctx, reel, GenerateRecipients(1)), datastore.ErrReelRecipientsNotAdded)
+		require.ErrorIs(t, repos.Reels.DeleteReel(ctx, reel.UID), datastore.ErrReelNotDeleted)
+	})
+
+	t.Run("AddAndDeleteRecipients", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		added := GenerateRecipients(2)
+		require.NoError(t, repos.Reels.AddRecipients(ctx, reel, added))
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, found.Recipients, 4)
+
+		require.ErrorIs(t, repos.Reels.DeleteRecipient(ctx, found, ulid.Make().String()), datastore.ErrRecipientNotFound)
+		require.NoError(t, repos.Reels.DeleteRecipient(ctx, found, added[0].UID))
+
+		// deleted recipients are filtered out on read
+		found, err = repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, found.Recipients, 3)
+		require.Nil(t, found.FindRecipient(added[0].UID))
+		require.NotNil(t, found.FindRecipient(added[1].UID))
+	})
+
+	t.Run("DuplicateRecipients", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		duplicate := GenerateRecipients(1)
+		duplicate[0].Email = reel.Recipients[0].Email
+		require.ErrorIs(t, repos.Reels.AddRecipients(ctx, reel, duplicate), datastore.ErrDuplicateRecipient)
+
+		// emails are compared without case
+		upper := GenerateRecipients(1)
+		upper[0].Email = strings.ToUpper(reel.Recipients[0].Email)
+		require.ErrorIs(t, repos.Reels.AddRecipients(ctx, reel, upper), datastore.ErrDuplicateRecipient)
+
+		// nothing from a failed batch is added
+		batch := append(GenerateRecipients(1), duplicate...)
+		reel, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.ErrorIs(t, repos.Reels.AddRecipients(ctx, reel, batch), datastore.ErrDuplicateRecipient)
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, found.Recipients, 2)
+
+		// a removed recipient can be added again
+		require.NoError(t, repos.Reels.DeleteRecipient(ctx, found, found.Recipients[0].UID))
+		require.NoError(t, repos.Reels.AddRecipients(ctx, found, duplicate))
+
+		found, err = repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, found.Recipients, 2)
+		require.NotNil(t, found.FindRecipient(duplicate[0].UID))
+
+		withDuplicates := GenerateReel(seedVideo(t, repos).UID, found.UserID.String)
+		withDuplicates.Recipients[1].Email = withDuplicates.Recipients[0].Email
+		require.ErrorIs(t, repos.Reels.CreateReel(ctx, withDuplicates), datastore.ErrDuplicateRecipient)
+
+		_, err = repos.Reels.GetReelByID(ctx, withDuplicates.UID)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+	})
+
+	t.Run("GetReelsPaged", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		other := seedUser(t, repos)
+
+		var ids []string
+		for i := 0; i < 5; i++ {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			if i%2 == 0 {
+				reel.DeliveryStatus = datastore.ScheduledReelStatus
+			}
+
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			ids = append(ids, reel.UID)
+		}
+
+		seedReel(t, repos, other.UID)
+
+		deleted := seedReel(t, repos, user.UID)
+		require.NoError(t, repos.Reels.DeleteReel(ctx, deleted.UID))
+
+		// newest first, only the user's reels that aren't deleted
+		reels, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, reels, 5)
+		require.False(t, pagination.HasNextPage)
+		require.False(t, pagination.HasPrevPage)
+		for i := range reels {
+			require.Equal(t, ids[len(ids)-1-i], reels[i].UID)
+		}
+
+		reels, pagination, err = repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 2})
+		require.NoError(t, err)
+		require.Len(t, reels, 2)
+		require.True(t, pagination.HasNextPage)
+		require.False(t, pagination.HasPrevPage)
+
+		// older than the cursor
+		reels, _, err = repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 10, Cursor: pagination.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, reels, 3)
+		require.Equal(t, ids[2], reels[0].UID)
+
+		_, _, err = repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 10, Cursor: "not a cursor"})
+		require.ErrorIs(t, err, datastore.ErrInvalidCursor)
+
+		filter := datastore.ReelFilter{DeliveryStatus: datastore.ScheduledReelStatus}
+		reels, _, err = repos.Reels.GetReelsPaged(ctx, user.UID, filter, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, reels, 3)
+	})
+
+	t.Run("GetReelsPagedBothDirections", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		var ids []string
+		for i := 0; i < 5; i++ {
+			ids = append(ids, seedReel(t, repos, user.UID).UID)
+		}
+		slices.Reverse(ids)
+
+		pageIDs := func(reels []datastore.Reel) []string {
+			var ids []string
+			for _, reel := range reels {
+				ids = append(ids, reel.UID)
+			}
+			return ids
+		}
+
+		page := func(cursor string) ([]string, datastore.PaginationData) {
+			reels, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 2, Cursor: cursor})
+			require.NoError(t, err)
+			return pageIDs(reels), pagination
+		}
+
+		first, pagination := page("")
+		require.Equal(t, ids[0:2], first)
+		require.True(t, pagination.HasNextPage)
+		require.False(t, pagination.HasPrevPage)
+		require.Empty(t, pagination.PrevCursor)
+
+		second, pagination := page(pagination.NextCursor)
+		require.Equal(t, ids[2:4], second)
+		require.True(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+
+		last, pagination := page(pagination.NextCursor)
+		require.Equal(t, ids[4:], last)
+		require.False(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+		require.Empty(t, pagination.NextCursor)
+
+		// and back again
+		back, pagination := page(pagination.PrevCursor)
+		require.Equal(t, second, back)
+		require.True(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+
+		back, pagination = page(pagination.PrevCursor)
+		require.Equal(t, first, back)
+		require.True(t, pagination.HasNextPage)
+		require.False(t, pagination.HasPrevPage)
+	})
+
+	t.Run("GetReelsPagedSorted", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		// two reels share a title and a delivery date so ties fall back to the id
+		titles := []string{"birthday", "anniversary", "graduation", "anniversary"}
+		days := []int{3, 1, 2, 1}
+
+		var reels []*datastore.Reel
+		for i := range titles {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			reel.Title = titles[i]
+			reel.DeliveryDate = time.Now().Truncate(time.Second).AddDate(0, 0, days[i])
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			reels = append(reels, reel)
+		}
+
+		// walk lists every page forwards then back to the start, returning the ids in order
+		walk := func(sort datastore.Sort) []string {
+			var ids []string
+			var cursor string
+			for {
+				page, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Cursor: cursor, Sort: sort})
+				require.NoError(t, err)
+				require.Len(t, page, 1)
+
+				ids = append(ids, page[0].UID)
+				if !pagination.HasNextPage {
+					break
+				}
+				cursor = pagination.NextCursor
+			}
+
+			_, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Cursor: cursor, Sort: sort})
+			require.NoError(t, err)
+
+			for i := len(ids) - 2; i >= 0; i-- {
+				require.True(t, pagination.HasPrevPage)
+
+				page, prev, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Cursor: pagination.PrevCursor, Sort: sort})
+				require.NoError(t, err)
+				require.Len(t, page, 1)
+				require.Equal(t, ids[i], page[0].UID)
+				pagination = prev
+			}
+			require.False(t, pagination.HasPrevPage)
+
+			return ids
+		}
+
+		tied := []string{reels[1].UID, reels[3].UID}
+
+		require.Equal(t, append(tied, reels[0].UID, reels[2].UID), walk(datastore.Sort{Field: datastore.ReelSortTitle}))
+		require.Equal(t, []string{reels[0].UID, reels[2].UID, tied[1], tied[0]}, walk(datastore.Sort{Field: datastore.ReelSortDeliveryDate, Order: datastore.SortDesc}))
+		require.Equal(t, []string{reels[3].UID, reels[2].UID, reels[1].UID, reels[0].UID}, walk(datastore.Sort{}))
+
+		// a cursor only works with the sort it was made for
+		_, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Sort: datastore.Sort{Field: datastore.ReelSortTitle}})
+		require.NoError(t, err)
+
+		_, _, err = repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Cursor: pagination.NextCursor})
+		require.ErrorIs(t, err, datastore.ErrInvalidCursor)
+
+		_, _, err = repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 1, Sort: datastore.Sort{Field: "email"}})
+		require.ErrorIs(t, err, datastore.ErrInvalidSort)
+	})
+
+	t.Run("GetReelsPagedSortedByMixedCaseTitle", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		// case is ignored and punctuation isn't, whatever the database's collation
+		titles := []string{"beta", "Alpha", "_gamma", "alpha two", "ALPHA"}
+
+		var reels []*datastore.Reel
+		for _, title := range titles {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			reel.Title = title
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			reels = append(reels, reel)
+		}
+
+		var ids []string
+		var cursor string
+		for {
+			page, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 2, Cursor: cursor, Sort: datastore.Sort{Field: datastore.ReelSortTitle}})
+			require.NoError(t, err)
+
+			for _, reel := range page {
+				ids = append(ids, reel.UID)
+			}
+
+			if !pagination.HasNextPage {
+				break
+			}
+			cursor = pagination.NextCursor
+		}
+
+		// "Alpha" and "ALPHA" tie, so they fall back to the id
+		require.Equal(t, []string{reels[2].UID, reels[1].UID, reels[4].UID, reels[3].UID, reels[0].UID}, ids)
+	})
+
+	t.Run("GetReelsPagedFiltered", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		today := time.Now().Truncate(time.Second)
+
+		soon := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		soon.DeliveryDate = today.AddDate(0, 0, 1)
+
+		public := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		public.DeliveryDate = today.AddDate(0, 0, 5)
+		public.Private = false
+		public.Recipients = nil
+
+		later := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		later.DeliveryDate = today.AddDate(0, 0, 10)
+		later.Recipients[0].Email = "Alex@Example.com"
+
+		for _, reel := range []*datastore.Reel{soon, public, later} {
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+		}
+
+		list := func(filter datastore.ReelFilter) []string {
+			reels, _, err := repos.Reels.GetReelsPaged(ctx, user.UID, filter, datastore.Pageable{PerPage: 10, Sort: datastore.Sort{Field: datastore.ReelSortDeliveryDate}})
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, reel := range reels {
+				ids = append(ids, reel.UID)
+			}
+			return ids
+		}
+
+		// the end of a range is left out
+		require.Equal(t, []string{public.UID}, list(datastore.ReelFilter{
+			DeliveryFrom: null.TimeFrom(today.AddDate(0, 0, 2)),
+			DeliveryTo:   null.TimeFrom(later.DeliveryDate),
+		}))
+		require.Equal(t, []string{later.UID}, list(datastore.ReelFilter{DeliveryFrom: null.TimeFrom(later.DeliveryDate)}))
+
+		require.Len(t, list(datastore.ReelFilter{CreatedTo: null.TimeFrom(today.Add(time.Hour))}), 3)
+		require.Empty(t, list(datastore.ReelFilter{CreatedFrom: null.TimeFrom(today.Add(time.Hour))}))
+
+		require.Equal(t, []string{public.UID}, list(datastore.ReelFilter{Private: null.BoolFrom(false)}))
+		require.Equal(t, []string{public.UID}, list(datastore.ReelFilter{HasRecipients: null.BoolFrom(false)}))
+		require.Equal(t, []string{later.UID}, list(datastore.ReelFilter{RecipientEmail: "alex@example.com"}))
+
+		// filters combine
+		require.Equal(t, []string{soon.UID, later.UID}, list(datastore.ReelFilter{
+			Private:       null.BoolFrom(true),
+			HasRecipients: null.BoolFrom(true),
+		}))
+		require.Empty(t, list(datastore.ReelFilter{
+			Private:        null.BoolFrom(false),
+			RecipientEmail: "alex@example.com",
+		}))
+
+		// removed recipients no longer match
+		require.NoError(t, repos.Reels.DeleteRecipient(ctx, later, later.Recipients[0].UID))
+		require.Empty(t, list(datastore.ReelFilter{RecipientEmail: "alex@example.com"}))
+	})
+
+	t.Run("SearchReels", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		reels := map[string]*datastore.Reel{}
+		for name, text := range map[string][2]string{
+			"title":       {"Grandma birthday", "cake and candles"},
+			"description": {"Road trip", "a birthday surprise at the beach"},
+			"unrelated":   {"Graduation", "cap and gown"},
+		} {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			reel.Title, reel.Description = text[0], text[1]
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			reels[name] = reel
+		}
+
+		// someone else's reel never shows up
+		other := GenerateReel(seedVideo(t, repos).UID, seedUser(t, repos).UID)
+		other.Title = "birthday"
+		require.NoError(t, repos.Reels.CreateReel(ctx, other))
+
+		search := func(query string, pageable datastore.Pageable) ([]string, datastore.PaginationData) {
+			found, pagination, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{Query: query}, pageable)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, reel := range found {
+				ids = append(ids, reel.UID)
+			}
+			return ids, pagination
+		}
+
+		// title matches rank first
+		ids, _ := search("birthday", datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{reels["title"].UID, reels["description"].UID}, ids)
+
+		ids, _ = search("birthday beach", datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{reels["description"].UID}, ids)
+
+		ids, _ = search("volcano", datastore.Pageable{PerPage: 10})
+		require.Empty(t, ids)
+
+		// stray operators and quotes don't break the search
+		ids, _ = search(`birthday & | ) :* "`, datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{reels["title"].UID, reels["description"].UID}, ids)
+
+		// results page by relevance
+		ids, pagination := search("birthday", datastore.Pageable{PerPage: 1})
+		require.Equal(t, []string{reels["title"].UID}, ids)
+		require.True(t, pagination.HasNextPage)
+
+		ids, pagination = search("birthday", datastore.Pageable{PerPage: 1, Cursor: pagination.NextCursor})
+		require.Equal(t, []string{reels["description"].UID}, ids)
+		require.False(t, pagination.HasNextPage)
+
+		ids, _ = search("birthday", datastore.Pageable{PerPage: 1, Cursor: pagination.PrevCursor})
+		require.Equal(t, []string{reels["title"].UID}, ids)
+
+		// a cursor only pages through the search it came from
+		_, _, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{Query: "birthday beach"}, datastore.Pageable{PerPage: 1, Cursor: pagination.PrevCursor})
+		require.ErrorIs(t, err, datastore.ErrInvalidCursor)
+
+		// and can be sorted another way
+		ids, _ = search("birthday", datastore.Pageable{PerPage: 10, Sort: datastore.Sort{Field: datastore.ReelSortTitle}})
+		require.Equal(t, []string{reels["title"].UID, reels["description"].UID}, ids)
+	})
+
+	t.Run("GetReelStats", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		stats, err := repos.Reels.GetReelStats(ctx, user.UID)
+		require.NoError(t, err)
+		require.Equal(t, datastore.NewReelStats(nil, 0, 0), stats)
+
+		for _, status := range []datastore.ReelDeliveryStatus{datastore.ScheduledReelStatus, datastore.ScheduledReelStatus, datastore.DeliveredReelStatus} {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			reel.DeliveryStatus = status
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+		}
+
+		// neither trashed reels nor other users' reels count
+		trashed := seedReel(t, repos, user.UID)
+		require.NoError(t, repos.Reels.DeleteReel(ctx, trashed.UID))
+		seedReel(t, repos, seedUser(t, repos).UID)
+
+		stats, err = repos.Reels.GetReelStats(ctx, user.UID)
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.Total)
+		require.Equal(t, 2, stats.ByStatus[datastore.ScheduledReelStatus])
+		require.Equal(t, 1, stats.ByStatus[datastore.DeliveredReelStatus])
+		require.Equal(t, 0, stats.ByStatus[datastore.FailedReelStatus])
+		require.Equal(t, 6, stats.Recipients)
+		require.InDelta(t, 3*GenerateVideo().SizeMB, stats.StorageMB, 0.001)
+	})
+
+	t.Run("GetDeliveryTimeline", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		year := time.Now().Year() + 1
+		from := time.Date(year, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+		schedule := func(status datastore.ReelDeliveryStatus, deliveryDate time.Time) *datastore.Reel {
+			reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+			reel.DeliveryStatus = status
+			reel.DeliveryDate = deliveryDate
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			return reel
+		}
+
+		schedule(datastore.ScheduledReelStatus, from.AddDate(0, 0, 1))
+		schedule(datastore.ScheduledReelStatus, time.Date(year, time.May, 31, 23, 0, 0, 0, time.UTC))
+		schedule(datastore.ScheduledReelStatus, time.Date(year, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+		// outside the window, not scheduled or trashed
+		schedule(datastore.ScheduledReelStatus, from.Add(-time.Hour))
+		schedule(datastore.ScheduledReelStatus, time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC))
+		schedule(datastore.UnconfirmedReelStatus, from.AddDate(0, 0, 2))
+		trashed := schedule(datastore.ScheduledReelStatus, from.AddDate(0, 1, 0))
+		require.NoError(t, repos.Reels.DeleteReel(ctx, trashed.UID))
+
+		timeline, err := repos.Reels.GetDeliveryTimeline(ctx, user.UID, from, 3)
+		require.NoError(t, err)
+		require.Equal(t, []datastore.MonthlyDeliveries{
+			{Month: fmt.Sprintf("%d-03", year), Reels: 1},
+			{Month: fmt.Sprintf("%d-04", year), Reels: 0},
+			{Month: fmt.Sprintf("%d-05", year), Reels: 2},
+		}, timeline)
+	})
+
+	t.Run("AssignReelsToUserByEmail", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		for i := 0; i < 3; i++ {
+			reel := GenerateReel(seedVideo(t, repos).UID, "")
+			reel.UserID = null.String{}
+			if i < 2 {
+				reel.Email = user.Email
+			}
+
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+		}
+
+		require.NoError(t, repos.Reels.AssignReelsToUserByEmail(ctx, user.Email, user.UID))
+
+		reels, _, err := repos.Reels.GetReelsPaged(ctx, user.UID, datastore.ReelFilter{}, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, reels, 2)
+	})
+
+	t.Run("GetReelsDueForReminder", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		due := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		due.DeliveryStatus = datastore.ScheduledReelStatus
+		due.DeliveryDate = time.Now().Add(time.Hour * 24 * 5)
+		require.NoError(t, repos.Reels.CreateReel(ctx, due))
+
+		suppressed := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		suppressed.DeliveryStatus = datastore.ScheduledReelStatus
+		suppressed.DeliveryDate = time.Now().Add(time.Hour * 24 * 5)
+		suppressed.SuppressReminders = true
+		require.NoError(t, repos.Reels.CreateReel(ctx, suppressed))
+
+		reels, err := repos.Reels.GetReelsDueForReminder(ctx, 7, 10)
+		require.NoError(t, err)
+		require.Len(t, reels, 1)
+		require.Equal(t, due.UID, reels[0].UID)
+
+		require.NoError(t, repos.Reels.RecordReminders(ctx, due.UID, []int{7, 30}))
+
+		reels, err = repos.Reels.GetReelsDueForReminder(ctx, 7, 10)
+		require.NoError(t, err)
+		require.Len(t, reels, 0)
+	})
+
+	t.Run("ReelReferences", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		err := repos.Reels.CreateReel(ctx, GenerateReel(ulid.Make().String(), user.UID))
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+
+		err = repos.Reels.CreateReel(ctx, GenerateReel(seedVideo(t, repos).UID, ulid.Make().String()))
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+
+		// a video can only be used by one active reel
+		first := seedReel(t, repos, user.UID)
+		err = repos.Reels.CreateReel(ctx, GenerateReel(first.VideoID, user.UID))
+		require.ErrorIs(t, err, datastore.ErrReelVideoInUse)
+		require.ErrorIs(t, err, datastore.ErrDuplicate)
+
+		second := seedReel(t, repos, user.UID)
+		second.VideoID = first.VideoID
+		require.ErrorIs(t, repos.Reels.UpdateReel(ctx, second), datastore.ErrReelVideoInUse)
+	})
+
+	t.Run("BulkOperations", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		existing := seedReel(t, repos, user.UID)
+
+		reels := []*datastore.Reel{
+			GenerateReel(seedVideo(t, repos).UID, user.UID),
+			// the video is already used, this one fails on its own
+			GenerateReel(existing.VideoID, user.UID),
+			GenerateReel(seedVideo(t, repos).UID, user.UID),
+		}
+
+		results, err := repos.Reels.CreateReels(ctx, reels)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.NoError(t, results[0])
+		require.ErrorIs(t, results[1], datastore.ErrReelVideoInUse)
+		require.NoError(t, results[2])
+
+		_, err = repos.Reels.GetReelByID(ctx, reels[1].UID)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		// each reel gets its own copy of the recipients
+		first, second := GenerateRecipients(1), GenerateRecipients(1)
+		second[0].Email = first[0].Email
+		duplicate := GenerateRecipients(1)
+		duplicate[0].Email = existing.Recipients[0].Email
+
+		results, err = repos.Reels.AddRecipientsToReels(ctx,
+			[]*datastore.Reel{reels[0], reels[2], existing},
+			[]datastore.Recipients{first, second, duplicate},
+		)
+		require.NoError(t, err)
+		require.NoError(t, results[0])
+		require.NoError(t, results[1])
+		require.ErrorIs(t, results[2], datastore.ErrDuplicateRecipient)
+
+		found, err := repos.Reels.GetReelByID(ctx, reels[2].UID)
+		require.NoError(t, err)
+		require.NotNil(t, found.FindRecipient(second[0].UID))
+
+		_, err = repos.Reels.AddRecipientsToReels(ctx, []*datastore.Reel{existing}, nil)
+		require.ErrorIs(t, err, datastore.ErrBulkLengthMismatch)
+
+		results, err = repos.Reels.DeleteReels(ctx, []string{reels[0].UID, ulid.Make().String(), reels[2].UID})
+		require.NoError(t, err)
+		require.NoError(t, results[0])
+		require.ErrorIs(t, results[1], datastore.ErrReelNotDeleted)
+		require.NoError(t, results[2])
+
+		trash, _, err := repos.Reels.GetDeletedReelsPaged(ctx, user.UID, time.Time{}, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, trash, 2)
+	})
+
+	t.Run("TrashAndRestore", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		reel := seedReel(t, repos, user.UID)
+
+		_, err := repos.Reels.GetDeletedReelByID(ctx, reel.UID)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		require.NoError(t, repos.Reels.DeleteReel(ctx, reel.UID))
+
+		retained := time.Now().Add(-time.Hour)
+		trash, _, err := repos.Reels.GetDeletedReelsPaged(ctx, user.UID, retained, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		require.Equal(t, reel.UID, trash[0].UID)
+		require.True(t, trash[0].DeletedAt.Valid)
+
+		// once the retention is up the reel is neither listed nor restorable
+		expired := time.Now().Add(time.Second)
+		trash, _, err = repos.Reels.GetDeletedReelsPaged(ctx, user.UID, expired, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, trash)
+		require.ErrorIs(t, repos.Reels.RestoreReel(ctx, &datastore.Reel{UID: reel.UID}, expired), datastore.ErrReelNotRestored)
+
+		// the video is free to be used by another reel while this one is in the trash
+		replacement := GenerateReel(reel.VideoID, user.UID)
+		require.NoError(t, repos.Reels.CreateReel(ctx, replacement))
+
+		deleted, err := repos.Reels.GetDeletedReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.ErrorIs(t, repos.Reels.RestoreReel(ctx, deleted, retained), datastore.ErrReelVideoInUse)
+
+		require.NoError(t, repos.Reels.DeleteReel(ctx, replacement.UID))
+		require.NoError(t, repos.Reels.RestoreReel(ctx, deleted, retained))
+		require.False(t, deleted.DeletedAt.Valid)
+		require.Equal(t, reel.Version+1, deleted.Version)
+
+		found, err := repos.Reels.GetReelByID(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, found.Recipients, len(reel.Recipients))
+
+		require.ErrorIs(t, repos.Reels.RestoreReel(ctx, found, retained), datastore.ErrReelNotRestored)
+	})
+
+	t.Run("GetDeletedReelsPaged", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repos.Reels.DeleteReel(ctx, seedReel(t, repos, user.UID).UID))
+		}
+		seedReel(t, repos, user.UID)
+
+		first, pagination, err := repos.Reels.GetDeletedReelsPaged(ctx, user.UID, time.Time{}, datastore.Pageable{PerPage: 2})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		require.True(t, pagination.HasNextPage)
+		require.False(t, pagination.HasPrevPage)
+		require.False(t, first[0].DeletedAt.Time.Before(first[1].DeletedAt.Time))
+
+		second, pagination, err := repos.Reels.GetDeletedReelsPaged(ctx, user.UID, time.Time{}, datastore.Pageable{PerPage: 2, Cursor: pagination.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		require.False(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+		require.NotContains(t, []string{first[0].UID, first[1].UID}, second[0].UID)
+
+		back, _, err := repos.Reels.GetDeletedReelsPaged(ctx, user.UID, time.Time{}, datastore.Pageable{PerPage: 2, Cursor: pagination.PrevCursor})
+		require.NoError(t, err)
+		require.Equal(t, []string{first[0].UID, first[1].UID}, []string{back[0].UID, back[1].UID})
+
+		_, _, err = repos.Reels.GetDeletedReelsPaged(ctx, user.UID, time.Time{}, datastore.Pageable{PerPage: 2, Sort: datastore.Sort{Field: "title", Order: datastore.SortAsc}})
+		require.ErrorIs(t, err, datastore.ErrInvalidSort)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		actorCtx := datastore.WithAuditor(ctx, datastore.Auditor{ActorID: user.UID, RequestID: "request-1"})
+
+		reel := GenerateReel(seedVideo(t, repos).UID, user.UID)
+		require.NoError(t, repos.Reels.CreateReel(actorCtx, reel))
+
+		reel.Title = "updated"
+		reel.DeliveryDate = reel.DeliveryDate.Add(time.Hour * 24)
+		require.NoError(t, repos.Reels.UpdateReel(actorCtx, reel))
+
+		// an update that changes nothing isn't recorded
+		require.NoError(t, repos.Reels.UpdateReel(actorCtx, reel))
+
+		added := GenerateRecipients(1)
+		require.NoError(t, repos.Reels.AddRecipients(actorCtx, reel, added))
+		require.NoError(t, repos.Reels.DeleteRecipient(actorCtx, reel, added[0].UID))
+
+		// changes without an auditor, like those from background jobs, have no actor
+		require.NoError(t, repos.Reels.DeleteReel(ctx, reel.UID))
+
+		entries, err := repos.Audit.GetReelAuditEntries(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, entries, 5)
+
+		actions := make([]datastore.AuditAction, len(entries))
+		for i := range entries {
+			actions[i] = entries[i].Action
+		}
+
+		require.Equal(t, []datastore.AuditAction{
+			datastore.ReelCreatedAction,
+			datastore.ReelUpdatedAction,
+			datastore.RecipientsAddedAction,
+			datastore.RecipientRemovedAction,
+			datastore.ReelDeletedAction,
+		}, actions)
+
+		require.Equal(t, user.UID, entries[0].ActorID.String)
+		require.Equal(t, "request-1", entries[0].RequestID.String)
+		require.Contains(t, entries[0].Changes, "recipients")
+
+		require.Len(t, entries[1].Changes, 2)
+		require.Equal(t, "updated", entries[1].Changes["title"].To)
+		require.Contains(t, entries[1].Changes, "delivery_date")
+
+		require.False(t, entries[4].ActorID.Valid)
+		require.False(t, entries[4].RequestID.Valid)
+
+		entries, err = repos.Audit.GetReelAuditEntries(ctx, ulid.Make().String())
+		require.NoError(t, err)
+		require.Len(t, entries, 0)
+	})
+
+	t.Run("PurgeDeletedReels", func(t *testing.T) {
+		repos := newRepos(t)
+		user := seedUser(t, repos)
+		reel := seedReel(t, repos, user.UID)
+		require.NoError(t, repos.Reels.DeleteReel(ctx, reel.UID))
+
+		replacement := GenerateReel(reel.VideoID, user.UID)
+		require.NoError(t, repos.Reels.CreateReel(ctx, replacement))
+
+		// nothing was deleted long enough ago
+		purged, videos, err := repos.Reels.PurgeDeletedReels(ctx, time.Now().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Equal(t, 0, purged)
+		require.Empty(t, videos)
+
+		purged, videos, err = repos.Reels.PurgeDeletedReels(ctx, time.Now().Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+		require.Empty(t, videos)
+
+		_, err = repos.Reels.GetDeletedReelByID(ctx, reel.UID)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		// the audit log outlives the reel
+		entries, err := repos.Audit.GetReelAuditEntries(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, datastore.ReelDeletedAction, entries[1].Action)
+		require.Equal(t, datastore.ReelPurgedAction, entries[2].Action)
+
+		// the video is still used by the replacement
+		_, err = repos.Videos.GetVideoByID(ctx, reel.VideoID)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.Reels.DeleteReel(ctx, replacement.UID))
+		purged, videos, err = repos.Reels.PurgeDeletedReels(ctx, time.Now().Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+		require.Len(t, videos, 1)
+		require.Equal(t, reel.VideoID, videos[0].UID)
+		require.NotEmpty(t, videos[0].Key)
+
+		_, err = repos.Videos.GetVideoByID(ctx, reel.VideoID)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+	})
+
+	t.Run("PurgeDeletedReelsWithResponses", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		reply := GenerateResponse(reel, datastore.VideoResponse)
+		reply.VideoID = null.StringFrom(seedVideo(t, repos).UID)
+		require.NoError(t, repos.Responses.CreateResponse(ctx, reply))
+
+		note := GenerateResponse(reel, datastore.NoteResponse)
+		require.NoError(t, repos.Responses.CreateResponse(ctx, note))
+
+		require.NoError(t, repos.Reels.DeleteReel(ctx, reel.UID))
+		purged, videos, err := repos.Reels.PurgeDeletedReels(ctx, time.Now().Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+		require.Len(t, videos, 2)
+		require.ElementsMatch(t, []string{reel.VideoID, reply.VideoID.String}, []string{videos[0].UID, videos[1].UID})
+
+		// reply videos go along with their responses
+		for _, id := range []string{reply.UID, note.UID} {
+			_, err = repos.Responses.GetResponseByID(ctx, id)
+			require.ErrorIs(t, err, datastore.ErrResponseNotFound)
+		}
+
+		_, err = repos.Videos.GetVideoByID(ctx, reply.VideoID.String)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+	})
+}
+
+func runViewTokenTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetViewToken", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		token := GenerateViewToken(reel.UID, reel.Recipients[0].UID)
+		_, err := repos.ViewTokens.GetViewTokenByID(ctx, token.UID)
+		require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+
+		require.NoError(t, repos.ViewTokens.CreateViewToken(ctx, token))
+
+		found, err := repos.ViewTokens.GetViewTokenByID(ctx, token.UID)
+		require.NoError(t, err)
+		require.Equal(t, reel.Recipients[0].UID, found.RecipientID)
+
+		// expired tokens aren't returned
+		expired := GenerateViewToken(reel.UID, reel.Recipients[0].UID)
+		expired.ExpiresAt = time.Now().Add(-time.Hour)
+		require.NoError(t, repos.ViewTokens.CreateViewToken(ctx, expired))
+
+		_, err = repos.ViewTokens.GetViewTokenByID(ctx, expired.UID)
+		require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+
+		err = repos.ViewTokens.CreateViewToken(ctx, GenerateViewToken(ulid.Make().String(), reel.Recipients[0].UID))
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+	})
+
+	t.Run("RevokeViewTokens", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		revoked := GenerateViewToken(reel.UID, reel.Recipients[0].UID)
+		untouched := GenerateViewToken(reel.UID, reel.Recipients[1].UID)
+		require.NoError(t, repos.ViewTokens.CreateViewToken(ctx, revoked))
+		require.NoError(t, repos.ViewTokens.CreateViewToken(ctx, untouched))
+
+		require.NoError(t, repos.ViewTokens.RevokeViewTokens(ctx, reel.UID, reel.Recipients[0].UID))
+
+		_, err := repos.ViewTokens.GetViewTokenByID(ctx, revoked.UID)
+		require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+
+		_, err = repos.ViewTokens.GetViewTokenByID(ctx, untouched.UID)
+		require.NoError(t, err)
+	})
+}
+
+func runInboxTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("GetInboxPaged", func(t *testing.T) {
+		repos := newRepos(t)
+		sender := seedUser(t, repos)
+		recipient := seedUser(t, repos)
+		today := time.Now().Truncate(time.Second)
+
+		send := func(status datastore.ReelDeliveryStatus, daysAgo int, to string) *datastore.Reel {
+			reel := GenerateReel(seedVideo(t, repos).UID, sender.UID)
+			reel.DeliveryStatus = status
+			reel.DeliveryDate = today.AddDate(0, 0, -daysAgo)
+			if to != "" {
+				reel.Recipients[0].Email = to
+			}
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			return reel
+		}
+
+		older := send(datastore.DeliveredReelStatus, 3, strings.ToUpper(recipient.Email))
+		newer := send(datastore.DeliveredReelStatus, 1, recipient.Email)
+
+		// not delivered yet, sent to someone else, removed as a recipient, and trashed
+		scheduled := send(datastore.ScheduledReelStatus, 0, recipient.Email)
+		someoneElse := send(datastore.DeliveredReelStatus, 2, "")
+		removed := send(datastore.DeliveredReelStatus, 2, recipient.Email)
+		require.NoError(t, repos.Reels.DeleteRecipient(ctx, removed, removed.Recipients[0].UID))
+		trashed := send(datastore.DeliveredReelStatus, 2, recipient.Email)
+		require.NoError(t, repos.Reels.DeleteReel(ctx, trashed.UID))
+
+		inbox := func(filter datastore.InboxFilter, pageable datastore.Pageable) ([]string, datastore.PaginationData) {
+			items, pagination, err := repos.Inbox.GetInboxPaged(ctx, recipient.UID, recipient.Email, filter, pageable)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, item.ReelID)
+			}
+			return ids, pagination
+		}
+
+		items, _, err := repos.Inbox.GetInboxPaged(ctx, recipient.UID, recipient.Email, datastore.InboxFilter{}, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, newer.UID, items[0].ReelID)
+		require.Equal(t, newer.Recipients[0].UID, items[0].RecipientID)
+		require.Equal(t, newer.Title, items[0].Title)
+		require.False(t, items[0].Hidden)
+
+		// latest delivery first, in pages both ways
+		ids, pagination := inbox(datastore.InboxFilter{}, datastore.Pageable{PerPage: 1})
+		require.Equal(t, []string{newer.UID}, ids)
+		require.True(t, pagination.HasNextPage)
+
+		ids, pagination = inbox(datastore.InboxFilter{}, datastore.Pageable{PerPage: 1, Cursor: pagination.NextCursor})
+		require.Equal(t, []string{older.UID}, ids)
+		require.False(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+
+		ids, _ = inbox(datastore.InboxFilter{}, datastore.Pageable{PerPage: 1, Cursor: pagination.PrevCursor})
+		require.Equal(t, []string{newer.UID}, ids)
+
+		_, _, err = repos.Inbox.GetInboxPaged(ctx, recipient.UID, recipient.Email, datastore.InboxFilter{}, datastore.Pageable{PerPage: 1, Sort: datastore.Sort{Field: datastore.ReelSortTitle}})
+		require.ErrorIs(t, err, datastore.ErrInvalidSort)
+
+		// hidden reels move out of the inbox until they're brought back
+		require.NoError(t, repos.Inbox.SetInboxItemHidden(ctx, recipient.UID, recipient.Email, newer.UID, true))
+		require.NoError(t, repos.Inbox.SetInboxItemHidden(ctx, recipient.UID, recipient.Email, newer.UID, true))
+
+		ids, _ = inbox(datastore.InboxFilter{}, datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{older.UID}, ids)
+
+		hidden, _, err := repos.Inbox.GetInboxPaged(ctx, recipient.UID, recipient.Email, datastore.InboxFilter{Hidden: true}, datastore.Pageable{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, hidden, 1)
+		require.Equal(t, newer.UID, hidden[0].ReelID)
+		require.True(t, hidden[0].Hidden)
+
+		require.NoError(t, repos.Inbox.SetInboxItemHidden(ctx, recipient.UID, recipient.Email, newer.UID, false))
+		ids, _ = inbox(datastore.InboxFilter{}, datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{newer.UID, older.UID}, ids)
+
+		for _, reel := range []*datastore.Reel{scheduled, someoneElse, removed, trashed} {
+			err := repos.Inbox.SetInboxItemHidden(ctx, recipient.UID, recipient.Email, reel.UID, true)
+			require.ErrorIs(t, err, datastore.ErrInboxItemNotFound)
+		}
+	})
+}
+
+func runGalleryTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("GetGalleryPaged", func(t *testing.T) {
+		repos := newRepos(t)
+		sender := seedUser(t, repos)
+		today := time.Now().Truncate(time.Second)
+
+		share := func(status datastore.ReelDeliveryStatus, daysAgo int, private bool, optIn bool) *datastore.Reel {
+			reel := GenerateReel(seedVideo(t, repos).UID, sender.UID)
+			reel.DeliveryStatus = status
+			reel.DeliveryDate = today.AddDate(0, 0, -daysAgo)
+			reel.Private = private
+			if optIn {
+				reel.PublicOptInAt = null.TimeFrom(today)
+			}
+			require.NoError(t, repos.Reels.CreateReel(ctx, reel))
+			return reel
+		}
+
+		older := share(datastore.DeliveredReelStatus, 3, false, true)
+		newer := share(datastore.DeliveredReelStatus, 1, false, true)
+
+		// not delivered yet, private, never opted in, and trashed
+		scheduled := share(datastore.ScheduledReelStatus, 0, false, true)
+		private := share(datastore.DeliveredReelStatus, 2, true, true)
+		notOptedIn := share(datastore.DeliveredReelStatus, 2, false, false)
+		trashed := share(datastore.DeliveredReelStatus, 2, false, true)
+		require.NoError(t, repos.Reels.DeleteReel(ctx, trashed.UID))
+
+		gallery := func(pageable datastore.Pageable) ([]string, datastore.PaginationData) {
+			items, pagination, err := repos.Gallery.GetGalleryPaged(ctx, pageable)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, item.ReelID)
+			}
+			return ids, pagination
+		}
+
+		item, err := repos.Gallery.GetGalleryItem(ctx, newer.UID)
+		require.NoError(t, err)
+		require.Equal(t, newer.Title, item.Title)
+		require.Equal(t, newer.VideoID, item.VideoID)
+		require.Equal(t, sender.Firstname, item.SenderName)
+		require.True(t, newer.DeliveryDate.Equal(item.DeliveredAt))
+
+		// latest delivery first, in pages both ways
+		ids, pagination := gallery(datastore.Pageable{PerPage: 1})
+		require.Equal(t, []string{newer.UID}, ids)
+		require.True(t, pagination.HasNextPage)
+
+		ids, pagination = gallery(datastore.Pageable{PerPage: 1, Cursor: pagination.NextCursor})
+		require.Equal(t, []string{older.UID}, ids)
+		require.False(t, pagination.HasNextPage)
+		require.True(t, pagination.HasPrevPage)
+
+		ids, _ = gallery(datastore.Pageable{PerPage: 1, Cursor: pagination.PrevCursor})
+		require.Equal(t, []string{newer.UID}, ids)
+
+		_, _, err = repos.Gallery.GetGalleryPaged(ctx, datastore.Pageable{PerPage: 1, Sort: datastore.Sort{Field: datastore.ReelSortTitle}})
+		require.ErrorIs(t, err, datastore.ErrInvalidSort)
+
+		for _, reel := range []*datastore.Reel{scheduled, private, notOptedIn, trashed} {
+			_, err := repos.Gallery.GetGalleryItem(ctx, reel.UID)
+			require.ErrorIs(t, err, datastore.ErrReelNotFound)
+		}
+
+		// opting in only happens at creation, and making a reel private takes it out
+		notOptedIn.PublicOptInAt = null.TimeFrom(today)
+		require.NoError(t, repos.Reels.UpdateReel(ctx, notOptedIn))
+
+		older.Private = true
+		require.NoError(t, repos.Reels.UpdateReel(ctx, older))
+
+		ids, _ = gallery(datastore.Pageable{PerPage: 10})
+		require.Equal(t, []string{newer.UID}, ids)
+	})
+}
+
+func runShareLinkTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetShareLink", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		link := GenerateShareLink(reel.UID)
+		link.PasswordHash = null.StringFrom("hash")
+		link.ExpiresAt = null.TimeFrom(time.Now().Add(time.Hour).Truncate(time.Second))
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, link))
+		require.False(t, link.CreatedAt.IsZero())
+
+		dbLink, err := repos.Shares.GetShareLinkByID(ctx, link.UID)
+		require.NoError(t, err)
+		require.Equal(t, link.PasswordHash, dbLink.PasswordHash)
+		require.True(t, link.ExpiresAt.Time.Equal(dbLink.ExpiresAt.Time))
+		require.False(t, dbLink.MaxViews.Valid)
+		require.Zero(t, dbLink.Views)
+
+		_, err = repos.Shares.GetShareLinkByID(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+
+		err = repos.Shares.CreateShareLink(ctx, GenerateShareLink(ulid.Make().String()))
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+	})
+
+	t.Run("RecordShareLinkView", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		link := GenerateShareLink(reel.UID)
+		link.MaxViews = null.IntFrom(2)
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, link))
+
+		for i := 1; i <= 2; i++ {
+			viewed, err := repos.Shares.RecordShareLinkView(ctx, link.UID)
+			require.NoError(t, err)
+			require.Equal(t, i, viewed.Views)
+		}
+
+		// used up
+		_, err := repos.Shares.RecordShareLinkView(ctx, link.UID)
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+
+		expired := GenerateShareLink(reel.UID)
+		expired.ExpiresAt = null.TimeFrom(time.Now().Add(-time.Minute))
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, expired))
+
+		_, err = repos.Shares.RecordShareLinkView(ctx, expired.UID)
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+	})
+
+	t.Run("RecordSharePasswordFailure", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		link := GenerateShareLink(reel.UID)
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, link))
+
+		failed, err := repos.Shares.RecordSharePasswordFailure(ctx, link.UID, 3, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, 1, failed.FailedPasswordAttempts)
+
+		// a view starts the count over
+		viewed, err := repos.Shares.RecordShareLinkView(ctx, link.UID)
+		require.NoError(t, err)
+		require.Equal(t, 0, viewed.FailedPasswordAttempts)
+
+		for i := 1; i < 3; i++ {
+			failed, err = repos.Shares.RecordSharePasswordFailure(ctx, link.UID, 3, time.Minute)
+			require.NoError(t, err)
+			require.False(t, failed.Locked(time.Now()))
+		}
+
+		failed, err = repos.Shares.RecordSharePasswordFailure(ctx, link.UID, 3, time.Minute)
+		require.NoError(t, err)
+		require.True(t, failed.Locked(time.Now()))
+		require.False(t, failed.Locked(time.Now().Add(2*time.Minute)))
+		require.Equal(t, 0, failed.FailedPasswordAttempts)
+
+		_, err = repos.Shares.RecordSharePasswordFailure(ctx, ulid.Make().String(), 3, time.Minute)
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+	})
+
+	t.Run("RevokeShareLink", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+		other := seedReel(t, repos, seedUser(t, repos).UID)
+
+		older := GenerateShareLink(reel.UID)
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, older))
+		newer := GenerateShareLink(reel.UID)
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, newer))
+		require.NoError(t, repos.Shares.CreateShareLink(ctx, GenerateShareLink(other.UID)))
+
+		// a link can only be revoked through its own reel
+		err := repos.Shares.RevokeShareLink(ctx, other.UID, newer.UID)
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+
+		require.NoError(t, repos.Shares.RevokeShareLink(ctx, reel.UID, newer.UID))
+		require.NoError(t, repos.Shares.RevokeShareLink(ctx, reel.UID, newer.UID))
+
+		_, err = repos.Shares.RecordShareLinkView(ctx, newer.UID)
+		require.ErrorIs(t, err, datastore.ErrShareLinkNotFound)
+
+		links, err := repos.Shares.GetShareLinksByReel(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, links, 2)
+		require.Equal(t, newer.UID, links[0].UID)
+		require.True(t, links[0].RevokedAt.Valid)
+		require.False(t, links[1].RevokedAt.Valid)
+	})
+}
+
+func runResponseTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetResponse", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		reaction := GenerateResponse(reel, datastore.ReactionResponse)
+		require.NoError(t, repos.Responses.CreateResponse(ctx, reaction))
+		require.False(t, reaction.CreatedAt.IsZero())
+
+		dbResponse, err := repos.Responses.GetResponseByID(ctx, reaction.UID)
+		require.NoError(t, err)
+		require.Equal(t, reaction.Body, dbResponse.Body)
+		require.Equal(t, reel.Recipients[0].UID, dbResponse.RecipientID)
+		require.False(t, dbResponse.VideoID.Valid)
+
+		_, err = repos.Responses.GetResponseByID(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, datastore.ErrResponseNotFound)
+
+		// reply videos need a video and nothing else can have one
+		err = repos.Responses.CreateResponse(ctx, GenerateResponse(reel, datastore.VideoResponse))
+		require.ErrorIs(t, err, datastore.ErrConstraintViolation)
+
+		note := GenerateResponse(reel, datastore.NoteResponse)
+		note.VideoID = null.StringFrom(seedVideo(t, repos).UID)
+		require.ErrorIs(t, repos.Responses.CreateResponse(ctx, note), datastore.ErrConstraintViolation)
+
+		reply := GenerateResponse(reel, datastore.VideoResponse)
+		reply.VideoID = null.StringFrom(ulid.Make().String())
+		err = repos.Responses.CreateResponse(ctx, reply)
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+	})
+
+	t.Run("ModerateResponse", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		approved := GenerateResponse(reel, datastore.ReactionResponse)
+		require.NoError(t, repos.Responses.CreateResponse(ctx, approved))
+
+		pending := GenerateResponse(reel, datastore.NoteResponse)
+		pending.ModerationStatus = datastore.PendingModeration
+		require.NoError(t, repos.Responses.CreateResponse(ctx, pending))
+
+		responses, err := repos.Responses.GetResponsesByReel(ctx, reel.UID, datastore.PendingModeration)
+		require.NoError(t, err)
+		require.Len(t, responses, 1)
+		require.Equal(t, pending.UID, responses[0].UID)
+
+		require.NoError(t, repos.Responses.ModerateResponse(ctx, pending.UID, datastore.ApprovedModeration))
+
+		// only pending responses can be moderated
+		err = repos.Responses.ModerateResponse(ctx, pending.UID, datastore.RejectedModeration)
+		require.ErrorIs(t, err, datastore.ErrResponseNotFound)
+
+		responses, err = repos.Responses.GetResponsesByReel(ctx, reel.UID, datastore.ApprovedModeration)
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+		require.Equal(t, approved.UID, responses[0].UID)
+		require.Equal(t, pending.UID, responses[1].UID)
+	})
+}
+
+func runContributionTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateAndRemoveContributor", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		contributor := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, contributor))
+		require.False(t, contributor.CreatedAt.IsZero())
+
+		dbContributor, err := repos.Contributions.GetContributorByID(ctx, contributor.UID)
+		require.NoError(t, err)
+		require.Equal(t, contributor.Email, dbContributor.Email)
+
+		// emails are unique per reel regardless of case
+		duplicate := GenerateContributor(reel.UID)
+		duplicate.Email = strings.ToUpper(contributor.Email)
+		err = repos.Contributions.CreateContributor(ctx, duplicate)
+		require.ErrorIs(t, err, datastore.ErrDuplicate)
+		require.ErrorIs(t, err, datastore.ErrDuplicateContributor)
+
+		err = repos.Contributions.CreateContributor(ctx, GenerateContributor(ulid.Make().String()))
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+		require.ErrorIs(t, err, datastore.ErrReelNotFound)
+
+		_, err = repos.Contributions.RemoveContributor(ctx, ulid.Make().String(), contributor.UID)
+		require.ErrorIs(t, err, datastore.ErrContributorNotFound)
+
+		_, err = repos.Contributions.RemoveContributor(ctx, reel.UID, contributor.UID)
+		require.NoError(t, err)
+
+		_, err = repos.Contributions.GetContributorByID(ctx, contributor.UID)
+		require.ErrorIs(t, err, datastore.ErrContributorNotFound)
+
+		contributors, err := repos.Contributions.GetContributorsByReel(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Empty(t, contributors)
+
+		// a removed contributor can be invited again
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, duplicate))
+	})
+
+	t.Run("CreateAndManageContributions", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		contributor := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, contributor))
+
+		first := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, first, 10))
+		second := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, second, 10))
+		require.Less(t, first.Position, second.Position)
+
+		missing := GenerateContribution(contributor, ulid.Make().String())
+		err := repos.Contributions.CreateContribution(ctx, missing, 10)
+		require.ErrorIs(t, err, datastore.ErrReferenceNotFound)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+
+		require.NoError(t, repos.Contributions.SetContributionStatus(ctx, reel.UID, second.UID, datastore.ApprovedContribution))
+		err = repos.Contributions.SetContributionStatus(ctx, ulid.Make().String(), second.UID, datastore.RejectedContribution)
+		require.ErrorIs(t, err, datastore.ErrContributionNotFound)
+
+		// the order has to list every contribution once
+		err = repos.Contributions.ReorderContributions(ctx, reel.UID, []string{second.UID})
+		require.ErrorIs(t, err, datastore.ErrInvalidContributionOrder)
+		err = repos.Contributions.ReorderContributions(ctx, reel.UID, []string{second.UID, second.UID})
+		require.ErrorIs(t, err, datastore.ErrInvalidContributionOrder)
+
+		require.NoError(t, repos.Contributions.ReorderContributions(ctx, reel.UID, []string{second.UID, first.UID}))
+
+		contributions, err := repos.Contributions.GetContributionsByReel(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, contributions, 2)
+		require.Equal(t, second.UID, contributions[0].UID)
+		require.Equal(t, datastore.ApprovedContribution, contributions[0].Status)
+		require.Equal(t, first.UID, contributions[1].UID)
+
+		video, err := repos.Contributions.DeleteContribution(ctx, reel.UID, first.UID)
+		require.NoError(t, err)
+		require.Equal(t, first.VideoID, video.UID)
+		require.NotEmpty(t, video.Key)
+
+		_, err = repos.Contributions.DeleteContribution(ctx, reel.UID, first.UID)
+		require.ErrorIs(t, err, datastore.ErrContributionNotFound)
+
+		// the clip goes with it
+		_, err = repos.Videos.GetVideoByID(ctx, first.VideoID)
+		require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+
+		// new contributions still go last
+		third := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, third, 10))
+
+		contributions, err = repos.Contributions.GetContributionsByReel(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, contributions, 2)
+		require.Equal(t, third.UID, contributions[1].UID)
+	})
+
+	t.Run("CreateContributionLimit", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		contributor := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, contributor))
+		other := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, other))
+
+		first := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, first, 2))
+		require.NoError(t, repos.Contributions.SetContributionStatus(ctx, reel.UID, first.UID, datastore.RejectedContribution))
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, GenerateContribution(contributor, seedVideo(t, repos).UID), 2))
+
+		// rejected clips count towards the limit too
+		err := repos.Contributions.CreateContribution(ctx, GenerateContribution(contributor, seedVideo(t, repos).UID), 2)
+		require.ErrorIs(t, err, datastore.ErrContributionLimitReached)
+
+		// the limit is per contributor
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, GenerateContribution(other, seedVideo(t, repos).UID), 2))
+
+		// removing a clip makes room for another
+		_, err = repos.Contributions.DeleteContribution(ctx, reel.UID, first.UID)
+		require.NoError(t, err)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, GenerateContribution(contributor, seedVideo(t, repos).UID), 2))
+	})
+
+	t.Run("RemoveContributorDeletesUnapprovedClips", func(t *testing.T) {
+		repos := newRepos(t)
+		reel := seedReel(t, repos, seedUser(t, repos).UID)
+
+		contributor := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, contributor))
+		other := GenerateContributor(reel.UID)
+		require.NoError(t, repos.Contributions.CreateContributor(ctx, other))
+
+		approved := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, approved, 10))
+		require.NoError(t, repos.Contributions.SetContributionStatus(ctx, reel.UID, approved.UID, datastore.ApprovedContribution))
+
+		pending := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, pending, 10))
+
+		rejected := GenerateContribution(contributor, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, rejected, 10))
+		require.NoError(t, repos.Contributions.SetContributionStatus(ctx, reel.UID, rejected.UID, datastore.RejectedContribution))
+
+		kept := GenerateContribution(other, seedVideo(t, repos).UID)
+		require.NoError(t, repos.Contributions.CreateContribution(ctx, kept, 10))
+
+		videos, err := repos.Contributions.RemoveContributor(ctx, reel.UID, contributor.UID)
+		require.NoError(t, err)
+
+		videoIDs := []string{}
+		for _, video := range videos {
+			require.NotEmpty(t, video.Key)
+			videoIDs = append(videoIDs, video.UID)
+		}
+		require.ElementsMatch(t, []string{pending.VideoID, rejected.VideoID}, videoIDs)
+
+		for _, videoID := range videoIDs {
+			_, err = repos.Videos.GetVideoByID(ctx, videoID)
+			require.ErrorIs(t, err, datastore.ErrVideoNotFound)
+		}
+
+		contributions, err := repos.Contributions.GetContributionsByReel(ctx, reel.UID)
+		require.NoError(t, err)
+		require.Len(t, contributions, 2)
+		require.Equal(t, approved.UID, contributions[0].UID)
+		require.Equal(t, kept.UID, contributions[1].UID)
+	})
+}
+
+func runUnitOfWorkTests(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("Commit", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		video := GenerateVideo()
+
+		err := repos.UnitOfWork.RunInTx(ctx, func(tx datastore.Repositories) error {
+			if err := tx.Users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+
+			if err := tx.Videos.CreateVideo(ctx, video); err != nil {
+				return err
+			}
+
+			// writes are visible inside the transaction
+			_, err := tx.Users.GetUserByID(ctx, user.UID)
+			require.NoError(t, err)
+
+			return tx.Reels.CreateReel(ctx, GenerateReel(video.UID, user.UID))
+		})
+		require.NoError(t, err)
+
+		_, err = repos.Users.GetUserByID(ctx, user.UID)
+		require.NoError(t, err)
+
+		_, err = repos.Videos.GetVideoByID(ctx, video.UID)
+		require.NoError(t, err)
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+		failure := errors.New("failure")
+
+		err := repos.UnitOfWork.RunInTx(ctx, func(tx datastore.Repositories) error {
+			if err := tx.Users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+
+			return failure
+		})
+		require.ErrorIs(t, err, failure)
+
+		_, err = repos.Users.GetUserByID(ctx, user.UID)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	})
+
+	t.Run("RollbackOnPanic", func(t *testing.T) {
+		repos := newRepos(t)
+		user := GenerateUser()
+
+		require.Panics(t, func() {
+			_ = repos.UnitOfWork.RunInTx(ctx, func(tx datastore.Repositories) error {
+				if err := tx.Users.CreateUser(ctx, user); err != nil {
+					return err
+				}
+
+				panic("failure")
+			})
+		})
+
+		_, err := repos.Users.GetUserByID(ctx, user.UID)
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	})
+}
+
+func seedUser(t *testing.T, repos Repositories) *datastore.User {
+	return SeedUser(t, repos.Repositories)
+}
+
+func seedVideo(t *testing.T, repos Repositories) *datastore.Video {
+	return SeedVideo(t, repos.Repositories)
+}
+
+func seedReel(t *testing.T, repos Repositories, userID string) *datastore.Reel {
+	return SeedReel(t, repos.Repositories, userID, datastore.UnconfirmedReelStatus)
+}
+
+// SeedUser saves a generated user
+func SeedUser(t *testing.T, repos datastore.Repositories) *datastore.User {
+	user := GenerateUser()
+	require.NoError(t, repos.Users.CreateUser(context.Background(), user))
+
+	return user
+}
+
+// SeedVideo saves a generated video
+func SeedVideo(t *testing.T, repos datastore.Repositories) *datastore.Video {
+	video := GenerateVideo()
+	require.NoError(t, repos.Videos.CreateVideo(context.Background(), video))
+
+	return video
+}
+
+// SeedReel saves a generated reel in the given delivery status along with its video. An
+// empty userID seeds a reel created without an account
+func SeedReel(t *testing.T, repos datastore.Repositories, userID string, status datastore.ReelDeliveryStatus) *datastore.Reel {
+	reel := GenerateReel(SeedVideo(t, repos).UID, userID)
+	reel.UserID = null.NewString(userID, userID != "")
+	reel.DeliveryStatus = status
+	require.NoError(t, repos.Reels.CreateReel(context.Background(), reel))
+
+	return reel
+}
+
+func GenerateUser() *datastore.User {
+	return &datastore.User{
+		UID:                        ulid.Make().String(),
+		Firstname:                  "test",
+		Lastname:                   "user",
+		Password:                   "demopassword",
+		Email:                      fmt.Sprintf("%s@gmail.com", ulid.Make().String()),
+		EmailVerificationToken:     ulid.Make().String(),
+		ResetPasswordToken:         ulid.Make().String(),
+		EmailVerificationExpiresAt: null.NewTime(time.Now(), true),
+		ResetPasswordExpiresAt:     null.NewTime(time.Time{}, false),
+	}
+}
+
+func GenerateVideo() *datastore.Video {
+	return &datastore.Video{
+		UID:        ulid.Make().String(),
+		Key:        ulid.Make().String(),
+		FileFormat: "mp4",
+		SizeMB:     20,
+	}
+}
+
+func GenerateReel(videoID, userID string) *datastore.Reel {
+	return &datastore.Reel{
+		UID:                    ulid.Make().String(),
+		UserID:                 null.NewString(userID, true),
+		VideoID:                videoID,
+		Email:                  fmt.Sprintf("%s@memoreel.com", ulid.Make().String()),
+		Title:                  "Test Reel",
+		Private:                true,
+		Recipients:             GenerateRecipients(2),
+		EmailConfirmationToken: ulid.Make().String(),
+		DeliveryStatus:         datastore.UnconfirmedReelStatus,
+		DeliveryDate:           time.Now().Add(time.Hour * 24 * 4),
+	}
+}
+
+func GenerateRecipients(n int) datastore.Recipients {
+	recipients := make(datastore.Recipients, n)
+	for i := range recipients {
+		recipients[i] = datastore.Recipient{
+			UID:       ulid.Make().String(),
+			Email:     fmt.Sprintf("recipient_%s@gmail.com", ulid.Make().String()),
+			CreatedAt: time.Now(),
+		}
+	}
+
+	return recipients
+}
+
+func GenerateViewToken(reelID, recipientID string) *datastore.ViewToken {
+	return &datastore.ViewToken{
+		UID:         ulid.Make().String(),
+		ReelID:      reelID,
+		RecipientID: recipientID,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func GenerateShareLink(reelID string) *datastore.ShareLink {
+	return &datastore.ShareLink{
+		UID:    ulid.Make().String(),
+		ReelID: reelID,
+	}
+}
+
+// GenerateResponse returns an approved response from the reel's first recipient. Reply
+// videos still need a video
+func GenerateResponse(reel *datastore.Reel, kind datastore.ResponseKind) *datastore.ReelResponse {
+	body := ""
+	switch kind {
+	case datastore.ReactionResponse:
+		body = "🎉"
+	case datastore.NoteResponse:
+		body = "Thank you!"
+	}
+
+	return &datastore.ReelResponse{
+		UID:              ulid.Make().String(),
+		ReelID:           reel.UID,
+		RecipientID:      reel.Recipients[0].UID,
+		Kind:             kind,
+		Body:             body,
+		ModerationStatus: datastore.ApprovedModeration,
+	}
+}
+
+func GenerateContributor(reelID string) *datastore.Contributor {
+	return &datastore.Contributor{
+		UID:    ulid.Make().String(),
+		ReelID: reelID,
+		Email:  fmt.Sprintf("contributor_%s@gmail.com", strings.ToLower(ulid.Make().String())),
+	}
+}
+
+// GenerateContribution returns a pending contribution of the video from the contributor
+func GenerateContribution(contributor *datastore.Contributor, videoID string) *datastore.Contribution {
+	return &datastore.Contribution{
+		UID:           ulid.Make().String(),
+		ReelID:        contributor.ReelID,
+		ContributorID: contributor.UID,
+		VideoID:       videoID,
+		Status:        datastore.PendingContribution,
+	}
+}
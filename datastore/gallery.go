@@ -0,0 +1,27 @@
+package datastore
+
+import "time"
+
+// GallerySort is the only order the gallery is listed in, latest delivery first
+var GallerySort = Sort{Field: "delivery_date", Order: SortDesc}
+
+// GalleryItem is a delivered reel its sender opted into sharing publicly when they created it.
+// The sender's first name is all that's shown about them
+type GalleryItem struct {
+	ReelID      string    `json:"reel_id" db:"reel_id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	DeliveredAt time.Time `json:"delivered_at" db:"delivery_date"`
+	SenderName  string    `json:"sender_name" db:"sender_name"`
+	VideoID     string    `json:"-" db:"video_id"`
+}
+
+// GalleryPosition returns where an item sits in the gallery
+func GalleryPosition(item GalleryItem) Cursor {
+	return Cursor{Sort: GallerySort, Value: formatCursorTime(item.DeliveredAt), ID: item.ReelID}
+}
+
+// InGallery reports whether a reel is shown in the public gallery
+func (r Reel) InGallery() bool {
+	return !r.DeletedAt.Valid && !r.Private && r.PublicOptInAt.Valid && r.DeliveryStatus == DeliveredReelStatus
+}
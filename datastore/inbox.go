@@ -0,0 +1,32 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInboxItemNotFound = errors.New("reel not found in inbox")
+
+// InboxSort is the only order an inbox is listed in, latest delivery first
+var InboxSort = Sort{Field: "delivery_date", Order: SortDesc}
+
+// InboxItem is a reel delivered to the user, as they see it as a recipient. Like the
+// recipient view it leaves out the sender and the other recipients
+type InboxItem struct {
+	ReelID      string    `json:"reel_id" db:"reel_id"`
+	RecipientID string    `json:"recipient_id" db:"recipient_id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	DeliveredAt time.Time `json:"delivered_at" db:"delivery_date"`
+	Hidden      bool      `json:"hidden" db:"hidden"`
+}
+
+type InboxFilter struct {
+	// Hidden lists the items the user has hidden instead of the visible ones
+	Hidden bool
+}
+
+// InboxPosition returns where an item sits in an inbox
+func InboxPosition(item InboxItem) Cursor {
+	return Cursor{Sort: InboxSort, Value: formatCursorTime(item.DeliveredAt), ID: item.ReelID}
+}
@@ -4,14 +4,30 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 
+	"github.com/jmoiron/sqlx/types"
 	"gopkg.in/guregu/null.v4"
 )
 
+// ErrVersionConflict is returned when a record is updated based on a version that's no longer current
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// general errors for changes the database refused. Repositories return them alongside a
+// more specific error, like ErrDuplicateUserEmail, when there's one
+var (
+	ErrDuplicate            = errors.New("record already exists")
+	ErrReferenceNotFound    = errors.New("referenced record does not exist")
+	ErrConstraintViolation  = errors.New("record violates a constraint")
+	ErrSerializationFailure = errors.New("transaction conflicted with another one, it can be retried")
+)
+
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrDuplicateUserEmail = errors.New("a user with this email already exists")
+	ErrUserNotUpdated     = errors.New("user could not be updated")
+	ErrUserNotDeleted     = errors.New("user could not be deleted")
 )
 
 type User struct {
@@ -25,25 +41,37 @@ type User struct {
 	EmailVerificationToken     string    `json:"-" db:"email_verification_token,omitempty"`
 	ResetPasswordExpiresAt     null.Time `json:"-" db:"reset_password_expires_at,omitempty"`
 	EmailVerificationExpiresAt null.Time `json:"-" db:"email_verification_expires_at,omitempty"`
-	CreatedAt                  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt                  time.Time `json:"updated_at" db:"updated_at"`
-	DeletedAt                  null.Time `json:"deleted_at,omitempty" db:"deleted_at,omitempty"`
+	// CalendarToken is the secret in the user's calendar feed url, null until a feed is set up
+	CalendarToken null.String `json:"-" db:"calendar_token"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at" db:"updated_at"`
+	DeletedAt     null.Time   `json:"deleted_at,omitempty" db:"deleted_at,omitempty"`
+	Version       int         `json:"version" db:"version"`
 }
 
 var (
-	ErrVideoNotFound = errors.New("video not found")
+	ErrVideoNotFound   = errors.New("video not found")
+	ErrVideoNotUpdated = errors.New("video could not be updated")
+	ErrVideoNotDeleted = errors.New("video could not be deleted")
 )
 
 type Video struct {
-	UID        string    `json:"uid" db:"id"`
-	Key        string    `json:"-" db:"key"`
-	FileFormat string    `json:"file_format" db:"file_format"`
-	SizeMB     float32   `json:"size_md" db:"size_mb"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
-	DeletedAt  null.Time `json:"deleted_at" db:"deleted_at"`
+	UID          string      `json:"uid" db:"id"`
+	Key          string      `json:"-" db:"key"`
+	FileFormat   string      `json:"file_format" db:"file_format"`
+	SizeMB       float32     `json:"size_md" db:"size_mb"`
+	HLSMasterKey null.String `json:"-" db:"hls_master_key"`
+	// PackagingAttempts counts failed attempts at packaging the video for streaming
+	PackagingAttempts int       `json:"-" db:"packaging_attempts"`
+	PackagingFailedAt null.Time `json:"-" db:"packaging_failed_at"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt         null.Time `json:"deleted_at" db:"deleted_at"`
 }
 
+// MaxPackagingAttempts is how many times packaging a video is tried before it's given up on
+const MaxPackagingAttempts = 5
+
 type Recipient struct {
 	UID       string    `json:"uid" db:"id"`
 	Email     string    `json:"email" db:"email"`
@@ -121,12 +149,115 @@ func (r ReelDeliveryStatus) IsValid() bool {
 }
 
 var (
-	ErrReelNotFound      = errors.New("reel not found")
-	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrReelNotFound            = errors.New("reel not found")
+	ErrRecipientNotFound       = errors.New("recipient not found")
+	ErrDuplicateRecipient      = errors.New("a recipient with this email has already been added to the reel")
+	ErrReelNotUpdated          = errors.New("reel could not be updated")
+	ErrReelRecipientNotDeleted = errors.New("reel recipient could not be deleted")
+	ErrReelRecipientsNotAdded  = errors.New("reel recipients could not added")
+	ErrReelNotDeleted          = errors.New("reel could not be deleted")
+	ErrReelNotRestored         = errors.New("reel could not be restored")
+	ErrReelVideoInUse          = errors.New("the reel's video is used by another reel")
+	ErrBulkLengthMismatch      = errors.New("every reel in a bulk operation needs its own recipients")
 )
 
+// ReelFilter narrows a reel listing, every field that's set must match. Ranges include
+// their From time and stop before their To time, either end can be left open
 type ReelFilter struct {
 	DeliveryStatus ReelDeliveryStatus
+	DeliveryFrom   null.Time
+	DeliveryTo     null.Time
+	CreatedFrom    null.Time
+	CreatedTo      null.Time
+	Private        null.Bool
+	HasRecipients  null.Bool
+	// RecipientEmail matches reels sent to this email, ignoring case
+	RecipientEmail string
+	// Query searches the title and description, words and quoted phrases as in a web search
+	Query string
+}
+
+// fields reels can be sorted by
+const (
+	ReelSortCreatedAt    = "created_at"
+	ReelSortUpdatedAt    = "updated_at"
+	ReelSortDeliveryDate = "delivery_date"
+	ReelSortTitle        = "title"
+	// ReelSortRelevance ranks search results, it can only be used with a query
+	ReelSortRelevance = "relevance"
+)
+
+var (
+	// DefaultReelSort lists the newest reels first
+	DefaultReelSort = Sort{Field: ReelSortCreatedAt, Order: SortDesc}
+	// DefaultReelSearchSort lists the best matches first
+	DefaultReelSearchSort = Sort{Field: ReelSortRelevance, Order: SortDesc}
+)
+
+// ReelSort fills in the defaults for a requested sort. An empty sort is DefaultReelSort,
+// or DefaultReelSearchSort when the filter has a query, and an empty order is ascending
+func ReelSort(sort Sort, filter ReelFilter) (Sort, error) {
+	if sort.Field == "" {
+		if filter.Query != "" {
+			sort = DefaultReelSearchSort
+			sort.Query = queryHash(filter.Query)
+			return sort, nil
+		}
+		return DefaultReelSort, nil
+	}
+
+	if sort.Order == "" {
+		sort.Order = SortAsc
+	}
+
+	switch sort.Field {
+	case ReelSortCreatedAt, ReelSortUpdatedAt, ReelSortDeliveryDate, ReelSortTitle:
+	case ReelSortRelevance:
+		if filter.Query == "" {
+			return Sort{}, ErrInvalidSort
+		}
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+
+	if sort.Order != SortAsc && sort.Order != SortDesc {
+		return Sort{}, ErrInvalidSort
+	}
+
+	sort.Query = ""
+	if sort.Field == ReelSortRelevance {
+		sort.Query = queryHash(filter.Query)
+	}
+
+	return sort, nil
+}
+
+// ReelPosition returns where a reel sits in a listing sorted by sort
+func ReelPosition(reel Reel, sort Sort) Cursor {
+	position := Cursor{Sort: sort, ID: reel.UID}
+
+	switch sort.Field {
+	case ReelSortCreatedAt:
+		position.Value = formatCursorTime(reel.CreatedAt)
+	case ReelSortUpdatedAt:
+		position.Value = formatCursorTime(reel.UpdatedAt)
+	case ReelSortDeliveryDate:
+		position.Value = formatCursorTime(reel.DeliveryDate)
+	case ReelSortTitle:
+		position.Value = reel.Title
+	case ReelSortRelevance:
+		position.Value = strconv.FormatFloat(float64(reel.SearchRank), 'g', -1, 32)
+	}
+
+	return position
+}
+
+// TrashSort is the only order the trash is listed in, most recently deleted first
+var TrashSort = Sort{Field: "deleted_at", Order: SortDesc}
+
+// TrashPosition returns where a deleted reel sits in the trash
+func TrashPosition(reel Reel) Cursor {
+	return Cursor{Sort: TrashSort, Value: formatCursorTime(reel.DeletedAt.Time), ID: reel.UID}
 }
 
 type Reel struct {
@@ -141,9 +272,19 @@ type Reel struct {
 	EmailConfirmationToken string             `json:"-" db:"email_confirmation_token"`
 	DeliveryStatus         ReelDeliveryStatus `json:"delivery_status" db:"delivery_status"`
 	DeliveryDate           time.Time          `json:"delivery_date,omitempty" db:"delivery_date,omitempty"`
-	CreatedAt              time.Time          `json:"created_at" db:"created_at"`
-	UpdatedAt              time.Time          `json:"updated_at" db:"updated_at"`
-	DeletedAt              null.Time          `json:"deleted_at,omitempty" db:"deleted_at"`
+	SuppressReminders      bool               `json:"suppress_reminders" db:"suppress_reminders"`
+	// PublicOptInAt is when the sender agreed to share the reel in the public gallery. It can
+	// only be given at creation, and the reel is shown once delivered unless made private
+	PublicOptInAt null.Time `json:"public_opt_in_at" db:"public_opt_in_at"`
+	// ContributionDeadline is when invited contributors stop being able to add clips to the reel,
+	// it's null when the reel doesn't take contributions
+	ContributionDeadline null.Time `json:"contribution_deadline" db:"contribution_deadline"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt            null.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Version              int       `json:"version" db:"version"`
+	// SearchRank is how well the reel matched a search, only set in search results
+	SearchRank float32 `json:"-" db:"search_rank"`
 }
 
 func (r Reel) FindRecipient(recipientID string) *Recipient {
@@ -158,40 +299,38 @@ func (r Reel) FindRecipient(recipientID string) *Recipient {
 	return nil
 }
 
-type PageDirection string
-
-type Pageable struct {
-	PerPage int    `json:"per_page"`
-	Cursor  string `json:"cursor"`
-}
-
-func (p Pageable) Limit() int {
-	return p.PerPage + 1
-}
+var (
+	ErrViewTokenNotFound = errors.New("view token not found")
+)
 
-type PreviousRowCount struct {
-	Count int
+// ViewToken grants a single recipient of a reel access to view it without an account
+type ViewToken struct {
+	UID         string    `json:"id" db:"id"`
+	ReelID      string    `json:"reel_id" db:"reel_id"`
+	RecipientID string    `json:"recipient_id" db:"recipient_id"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt   null.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
-type PaginationData struct {
-	PerPage      int    `json:"per_page"`
-	Cursor       string `json:"cursor"`
-	HasMorePages bool   `json:"has_more_pages"`
+// OutboxMessage is a side effect, like an email, recorded in the same transaction as
+// the change that caused it and published later by a dispatcher. IdempotencyKey
+// identifies the logical message so it's never enqueued twice and lets consumers
+// discard redeliveries
+type OutboxMessage struct {
+	UID            string         `json:"id" db:"id"`
+	IdempotencyKey string         `json:"idempotency_key" db:"idempotency_key"`
+	Topic          string         `json:"topic" db:"topic"`
+	Payload        types.JSONText `json:"payload" db:"payload"`
+	Attempts       int            `json:"attempts" db:"attempts"`
+	AvailableAt    time.Time      `json:"available_at" db:"available_at"`
+	LockedUntil    null.Time      `json:"locked_until" db:"locked_until"`
+	DispatchedAt   null.Time      `json:"dispatched_at" db:"dispatched_at"`
+	LastError      null.String    `json:"last_error" db:"last_error"`
+	// DeadLetteredAt is set on messages that can never be dispatched, they're kept for inspection
+	DeadLetteredAt null.Time `json:"dead_lettered_at" db:"dead_lettered_at"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 }
 
-func (p *PaginationData) Build(pageable Pageable, items []string) *PaginationData {
-	p.PerPage = pageable.PerPage
-
-	var last string
-
-	if len(items) > 0 {
-		last = items[len(items)-1]
-	}
-
-	p.Cursor = last
-
-	// an extra exists. It's used to check if there are more pages to be loaded
-	p.HasMorePages = len(items) > p.PerPage
-
-	return p
-}
+// MaxOutboxAttempts is how many times dispatching a message is tried before it's dead-lettered
+const MaxOutboxAttempts = 10
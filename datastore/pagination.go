@@ -0,0 +1,206 @@
+package datastore
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidSort   = errors.New("invalid sort")
+)
+
+type PageDirection string
+
+const (
+	// NextPage moves further along the sort order
+	NextPage PageDirection = "next"
+	// PrevPage moves back towards the start of the sort order
+	PrevPage PageDirection = "prev"
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Sort orders a listing by Field. Ties are broken by id in the same order so every
+// item has a fixed position to page from
+type Sort struct {
+	Field string    `json:"field"`
+	Order SortOrder `json:"order"`
+	// Query is a hash of the search a relevance sort ranks by, so a cursor can't be used
+	// to page through the results of another search
+	Query string `json:"query,omitempty"`
+}
+
+// Reverse returns the sort in the opposite order, used to walk back to a previous page
+func (s Sort) Reverse() Sort {
+	if s.Order == SortDesc {
+		s.Order = SortAsc
+	} else {
+		s.Order = SortDesc
+	}
+	return s
+}
+
+// Cursor marks where a page starts. It's handed to clients as an opaque string so
+// what's in it can change without breaking them
+type Cursor struct {
+	Sort Sort `json:"sort"`
+	// Value and ID are the sort key and id of the item the page starts after, in Direction
+	Value     string        `json:"value,omitempty"`
+	ID        string        `json:"id"`
+	Direction PageDirection `json:"dir"`
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Time reads the cursor's value for sorts on a timestamp
+func (c Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// Float reads the cursor's value for sorts on a ranking
+func (c Cursor) Float() (float32, error) {
+	f, err := strconv.ParseFloat(c.Value, 32)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return float32(f), nil
+}
+
+// queryHash identifies a search query in a cursor without spelling it out
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func formatCursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// DecodeCursor reads a cursor from its opaque form. An empty string is the first page
+func DecodeCursor(encoded string) (Cursor, error) {
+	if encoded == "" {
+		return Cursor{Direction: NextPage}, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if cursor.Direction != NextPage && cursor.Direction != PrevPage {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+type Pageable struct {
+	PerPage int `json:"per_page"`
+	// Cursor is an opaque cursor from PaginationData, empty for the first page
+	Cursor string `json:"cursor"`
+	// Sort is left empty for the listing's default order
+	Sort Sort `json:"sort"`
+}
+
+// Limit is one more than a page so the extra item shows whether there's another page
+func (p Pageable) Limit() int {
+	return p.PerPage + 1
+}
+
+// Position decodes the cursor, making sure it was made for the same sort
+func (p Pageable) Position(sort Sort) (Cursor, error) {
+	cursor, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	if cursor.ID != "" && cursor.Sort != sort {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	cursor.Sort = sort
+	return cursor, nil
+}
+
+// FixedPosition decodes the cursor of a listing that only comes in one order, any other
+// requested sort is rejected
+func (p Pageable) FixedPosition(sort Sort) (Cursor, error) {
+	if p.Sort != (Sort{}) && p.Sort != sort {
+		return Cursor{}, ErrInvalidSort
+	}
+
+	return p.Position(sort)
+}
+
+type PaginationData struct {
+	PerPage     int    `json:"per_page"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
+	HasNextPage bool   `json:"has_next_page"`
+	HasPrevPage bool   `json:"has_prev_page"`
+}
+
+// Page trims the extra item fetched past a page and works out the pagination data.
+//
+// positions are the fetched items' positions, in the order they were fetched in cursor's
+// direction, so a previous page comes in reversed. hasBehind reports whether there are
+// items on the other side of the cursor. It returns how many of the fetched items make
+// up the page; the caller reverses them into display order for a previous page
+func Page(pageable Pageable, cursor Cursor, positions []Cursor, hasBehind bool) (int, PaginationData) {
+	hasAhead := len(positions) > pageable.PerPage
+	if hasAhead {
+		positions = positions[:pageable.PerPage]
+	}
+
+	pagination := PaginationData{PerPage: pageable.PerPage}
+
+	if cursor.Direction == PrevPage {
+		pagination.HasPrevPage, pagination.HasNextPage = hasAhead, hasBehind
+	} else {
+		pagination.HasNextPage, pagination.HasPrevPage = hasAhead, hasBehind
+	}
+
+	if len(positions) == 0 {
+		return 0, pagination
+	}
+
+	// first and last in display order
+	first, last := positions[0], positions[len(positions)-1]
+	if cursor.Direction == PrevPage {
+		first, last = last, first
+	}
+
+	if pagination.HasNextPage {
+		last.Sort, last.Direction = cursor.Sort, NextPage
+		pagination.NextCursor = last.Encode()
+	}
+
+	if pagination.HasPrevPage {
+		first.Sort, first.Direction = cursor.Sort, PrevPage
+		pagination.PrevCursor = first.Encode()
+	}
+
+	return len(positions), pagination
+}
@@ -0,0 +1,58 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	cursor := Cursor{ID: "01HQ5Z3B9M8Y7X6W5V4T3S2R1Q", Direction: PrevPage}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+
+	first, err := DecodeCursor("")
+	require.NoError(t, err)
+	require.Equal(t, Cursor{Direction: NextPage}, first)
+
+	for _, encoded := range []string{
+		"not base64!",
+		Cursor{ID: "x", Direction: "sideways"}.Encode(),
+		Cursor{Direction: NextPage}.Encode(),
+	} {
+		_, err := DecodeCursor(encoded)
+		require.ErrorIs(t, err, ErrInvalidCursor, encoded)
+	}
+}
+
+func TestReelSort(t *testing.T) {
+	sort, err := ReelSort(Sort{}, ReelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultReelSort, sort)
+
+	sort, err = ReelSort(Sort{Field: ReelSortTitle}, ReelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, Sort{Field: ReelSortTitle, Order: SortAsc}, sort)
+
+	sort, err = ReelSort(Sort{}, ReelFilter{Query: "birthday"})
+	require.NoError(t, err)
+	require.Equal(t, DefaultReelSearchSort.Field, sort.Field)
+	require.Equal(t, DefaultReelSearchSort.Order, sort.Order)
+
+	// relevance depends on the query, so the sort does too
+	other, err := ReelSort(Sort{Field: ReelSortRelevance, Order: SortDesc}, ReelFilter{Query: "wedding"})
+	require.NoError(t, err)
+	require.NotEqual(t, sort, other)
+
+	// there's nothing to rank by without a query
+	_, err = ReelSort(Sort{Field: ReelSortRelevance}, ReelFilter{})
+	require.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = ReelSort(Sort{Field: "email"}, ReelFilter{})
+	require.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = ReelSort(Sort{Field: ReelSortTitle, Order: "up"}, ReelFilter{})
+	require.ErrorIs(t, err, ErrInvalidSort)
+}
@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"time"
 )
 
 type UserRepository interface {
@@ -9,8 +10,9 @@ type UserRepository interface {
 	GetUserByEmail(context.Context, string) (*User, error)
 	GetUserByResetPasswordToken(context.Context, string) (*User, error)
 	GetUserByEmailVerificationToken(context.Context, string) (*User, error)
-	CreateUser(context.Context, *User) error
-	UpdateUser(context.Context, *User) error
+	GetUserByCalendarToken(context.Context, string) (*User, error)
+	CreateUser(ctx context.Context, user *User, messages ...OutboxMessage) error
+	UpdateUser(ctx context.Context, user *User, messages ...OutboxMessage) error
 	DeleteUser(ctx context.Context, userID string) error
 }
 
@@ -18,17 +20,151 @@ type ReelRepository interface {
 	GetReelByID(context.Context, string) (*Reel, error)
 	GetReelsPaged(ctx context.Context, userID string, filter ReelFilter, pageable Pageable) ([]Reel, PaginationData, error)
 	GetReelByEmailConfirmationToken(context.Context, string) (*Reel, error)
-	CreateReel(context.Context, *Reel) error
-	UpdateReel(context.Context, *Reel) error
+	GetReelsDueForReminder(ctx context.Context, daysBefore int, limit int) ([]Reel, error)
+	RecordReminders(ctx context.Context, reelID string, daysBefore []int) error
+	CreateReel(ctx context.Context, reel *Reel, messages ...OutboxMessage) error
+	UpdateReel(ctx context.Context, reel *Reel, messages ...OutboxMessage) error
 	AssignReelsToUserByEmail(ctx context.Context, email string, userID string) error
 	AddRecipients(ctx context.Context, reel *Reel, recipients Recipients) error
 	DeleteRecipient(ctx context.Context, reel *Reel, recipientID string) error
 	DeleteReel(ctx context.Context, reelID string) error
+	// trash. Reels deleted before deletedAfter are past their retention and only wait to be
+	// purged, so they're neither listed nor restored
+	GetDeletedReelsPaged(ctx context.Context, userID string, deletedAfter time.Time, pageable Pageable) ([]Reel, PaginationData, error)
+	GetDeletedReelByID(ctx context.Context, reelID string) (*Reel, error)
+	RestoreReel(ctx context.Context, reel *Reel, deletedAfter time.Time) error
+	// PurgeDeletedReels permanently removes up to limit reels deleted before the given time,
+	// along with their recipients and videos no other reel uses. It returns how many were
+	// removed and the videos that went with them, whose stored objects are left to the caller
+	PurgeDeletedReels(ctx context.Context, deletedBefore time.Time, limit int) (int, []Video, error)
+	// stats
+	GetReelStats(ctx context.Context, userID string) (*ReelStats, error)
+	// GetDeliveryTimeline counts the user's scheduled reels due each month from the given time
+	// until the end of the months-th calendar month, starting with from's month
+	GetDeliveryTimeline(ctx context.Context, userID string, from time.Time, months int) ([]MonthlyDeliveries, error)
+	// bulk operations run in a single transaction but apply each item independently, one
+	// failing doesn't undo the others. The returned slice holds each item's error, or nil,
+	// in the order given. The error is only set when the transaction itself failed
+	CreateReels(ctx context.Context, reels []*Reel) ([]error, error)
+	DeleteReels(ctx context.Context, reelIDs []string) ([]error, error)
+	// AddRecipientsToReels adds recipients[i] to reels[i]
+	AddRecipientsToReels(ctx context.Context, reels []*Reel, recipients []Recipients) ([]error, error)
 }
 
 type VideoRepository interface {
 	GetVideoByID(context.Context, string) (*Video, error)
+	// GetVideosPendingPackaging returns up to limit videos without HLS renditions, leaving out
+	// those that failed within retryAfter or MaxPackagingAttempts times. Videos that never
+	// failed come first so broken ones can't hold up the rest
+	GetVideosPendingPackaging(ctx context.Context, limit int, retryAfter time.Duration) ([]Video, error)
+	// RecordPackagingFailure counts a failed attempt at packaging the video
+	RecordPackagingFailure(ctx context.Context, videoID string) error
 	CreateVideo(context.Context, *Video) error
 	UpdateVideo(context.Context, *Video) error
 	DeleteVideo(ctx context.Context, videoID string) error
 }
+
+type ViewTokenRepository interface {
+	GetViewTokenByID(context.Context, string) (*ViewToken, error)
+	CreateViewToken(context.Context, *ViewToken) error
+	RevokeViewTokens(ctx context.Context, reelID string, recipientID string) error
+}
+
+type OutboxRepository interface {
+	EnqueueMessages(ctx context.Context, messages ...OutboxMessage) error
+	// ClaimPendingMessages leases up to limit undispatched messages so no other dispatcher picks them up until the lease expires
+	ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	MarkMessageDispatched(ctx context.Context, messageID string) error
+	MarkMessageFailed(ctx context.Context, messageID string, reason string, retryAt time.Time) error
+	// MarkMessageDeadLettered stops the message from being claimed again
+	MarkMessageDeadLettered(ctx context.Context, messageID string, reason string) error
+}
+
+// AuditRepository reads the audit log. Entries are written by the repositories themselves,
+// in the same transaction as the change they record
+type AuditRepository interface {
+	GetReelAuditEntries(ctx context.Context, reelID string) ([]AuditEntry, error)
+}
+
+// InboxRepository lists reels from the recipient's side. A reel is in a user's inbox once
+// it's delivered and their email is one of its active recipients
+type InboxRepository interface {
+	GetInboxPaged(ctx context.Context, userID string, email string, filter InboxFilter, pageable Pageable) ([]InboxItem, PaginationData, error)
+	// SetInboxItemHidden hides a reel from the user's inbox or brings it back
+	SetInboxItemHidden(ctx context.Context, userID string, email string, reelID string, hidden bool) error
+}
+
+// GalleryRepository lists the reels shared in the public gallery, see Reel.InGallery
+type GalleryRepository interface {
+	GetGalleryPaged(ctx context.Context, pageable Pageable) ([]GalleryItem, PaginationData, error)
+	GetGalleryItem(ctx context.Context, reelID string) (*GalleryItem, error)
+}
+
+// ShareLinkRepository keeps the links senders create to share a reel outside its recipients
+type ShareLinkRepository interface {
+	GetShareLinkByID(ctx context.Context, id string) (*ShareLink, error)
+	GetShareLinksByReel(ctx context.Context, reelID string) ([]ShareLink, error)
+	CreateShareLink(ctx context.Context, link *ShareLink) error
+	// RecordShareLinkView counts a view of the link, failing with ErrShareLinkNotFound when it's
+	// no longer active. The check and count happen atomically so max views can't be overrun
+	// It also clears the link's failed password attempts
+	RecordShareLinkView(ctx context.Context, id string) (*ShareLink, error)
+	// RecordSharePasswordFailure counts a wrong password for the link. The maxAttempts-th one
+	// locks the link for lockout and starts the count over
+	RecordSharePasswordFailure(ctx context.Context, id string, maxAttempts int, lockout time.Duration) (*ShareLink, error)
+	RevokeShareLink(ctx context.Context, reelID string, id string) error
+}
+
+// ResponseRepository keeps what recipients sent back after a reel was delivered
+type ResponseRepository interface {
+	GetResponseByID(ctx context.Context, id string) (*ReelResponse, error)
+	// GetResponsesByReel lists the reel's responses in the given moderation status, oldest first
+	GetResponsesByReel(ctx context.Context, reelID string, status ModerationStatus) ([]ReelResponse, error)
+	CreateResponse(ctx context.Context, response *ReelResponse, messages ...OutboxMessage) error
+	// ModerateResponse settles a pending response, failing with ErrResponseNotFound when it's
+	// already been moderated
+	ModerateResponse(ctx context.Context, id string, status ModerationStatus, messages ...OutboxMessage) error
+}
+
+// ContributionRepository keeps the contributors invited to group reels and the clips they add
+type ContributionRepository interface {
+	// GetContributorByID only returns contributors that haven't been removed
+	GetContributorByID(ctx context.Context, id string) (*Contributor, error)
+	GetContributorsByReel(ctx context.Context, reelID string) ([]Contributor, error)
+	CreateContributor(ctx context.Context, contributor *Contributor, messages ...OutboxMessage) error
+	// RemoveContributor removes the contributor and deletes the clips they added that weren't
+	// approved, returning the deleted clips' videos. Approved clips stay in the reel
+	RemoveContributor(ctx context.Context, reelID string, id string) ([]Video, error)
+	// GetContributionsByReel lists the reel's contributions by position
+	GetContributionsByReel(ctx context.Context, reelID string) ([]Contribution, error)
+	// CreateContribution adds the contribution after the reel's existing ones, failing with
+	// ErrContributionLimitReached when its contributor already added maxPerContributor
+	CreateContribution(ctx context.Context, contribution *Contribution, maxPerContributor int) error
+	SetContributionStatus(ctx context.Context, reelID string, id string, status ContributionStatus) error
+	// ReorderContributions positions the reel's contributions in the order of ids, which must
+	// list each of them once
+	ReorderContributions(ctx context.Context, reelID string, ids []string) error
+	// DeleteContribution removes the contribution along with its video, which is returned
+	DeleteContribution(ctx context.Context, reelID string, id string) (*Video, error)
+}
+
+// Repositories groups repositories that share the same connection or transaction
+type Repositories struct {
+	Users         UserRepository
+	Reels         ReelRepository
+	Videos        VideoRepository
+	Outbox        OutboxRepository
+	ViewTokens    ViewTokenRepository
+	Audit         AuditRepository
+	Inbox         InboxRepository
+	Gallery       GalleryRepository
+	Shares        ShareLinkRepository
+	Responses     ResponseRepository
+	Contributions ContributionRepository
+}
+
+type UnitOfWork interface {
+	// RunInTx calls fn with repositories bound to a single transaction. The transaction
+	// is committed when fn returns nil and rolled back when it returns an error or panics
+	RunInTx(ctx context.Context, fn func(repos Repositories) error) error
+}
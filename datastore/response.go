@@ -0,0 +1,49 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+var ErrResponseNotFound = errors.New("response not found")
+
+// ResponseKind is how a recipient responded to a reel
+type ResponseKind string
+
+const (
+	ReactionResponse ResponseKind = "reaction"
+	NoteResponse     ResponseKind = "note"
+	VideoResponse    ResponseKind = "video"
+)
+
+// ModerationStatus is where a response is in moderation, only approved responses reach the sender
+type ModerationStatus string
+
+const (
+	PendingModeration  ModerationStatus = "pending"
+	ApprovedModeration ModerationStatus = "approved"
+	RejectedModeration ModerationStatus = "rejected"
+)
+
+func (s ModerationStatus) IsValid() bool {
+	switch s {
+	case PendingModeration, ApprovedModeration, RejectedModeration:
+		return true
+	}
+	return false
+}
+
+// ReelResponse is a recipient's answer to a delivered reel. Body holds the emoji of a
+// reaction or the text of a note, and VideoID the upload of a reply video
+type ReelResponse struct {
+	UID              string           `json:"id" db:"id"`
+	ReelID           string           `json:"reel_id" db:"reel_id"`
+	RecipientID      string           `json:"recipient_id" db:"recipient_id"`
+	Kind             ResponseKind     `json:"kind" db:"kind"`
+	Body             string           `json:"body" db:"body"`
+	VideoID          null.String      `json:"video_id" db:"video_id"`
+	ModerationStatus ModerationStatus `json:"moderation_status" db:"moderation_status"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,40 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// ShareLink lets a sender show a delivered reel to someone who isn't one of its recipients.
+// A link stops working once it's revoked, expires or has been viewed MaxViews times
+type ShareLink struct {
+	UID          string      `json:"id" db:"id"`
+	ReelID       string      `json:"reel_id" db:"reel_id"`
+	PasswordHash null.String `json:"-" db:"password_hash"`
+	ExpiresAt    null.Time   `json:"expires_at" db:"expires_at"`
+	MaxViews     null.Int    `json:"max_views" db:"max_views"`
+	Views        int         `json:"views" db:"views"`
+	RevokedAt    null.Time   `json:"revoked_at" db:"revoked_at"`
+	// FailedPasswordAttempts counts wrong passwords since the last view or lockout
+	FailedPasswordAttempts int       `json:"-" db:"failed_password_attempts"`
+	LockedUntil            null.Time `json:"locked_until" db:"locked_until"`
+	CreatedAt              time.Time `json:"created_at" db:"created_at"`
+}
+
+// Locked reports whether too many wrong passwords keep the link from being opened at the given time
+func (s ShareLink) Locked(at time.Time) bool {
+	return s.LockedUntil.Valid && at.Before(s.LockedUntil.Time)
+}
+
+// Active reports whether the link can still be viewed at the given time
+func (s ShareLink) Active(at time.Time) bool {
+	if s.RevokedAt.Valid || (s.ExpiresAt.Valid && !at.Before(s.ExpiresAt.Time)) {
+		return false
+	}
+
+	return !s.MaxViews.Valid || int64(s.Views) < s.MaxViews.Int64
+}
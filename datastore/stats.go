@@ -0,0 +1,61 @@
+package datastore
+
+import "time"
+
+// ReelStats summarises a user's reels. Reels in the trash aren't counted
+type ReelStats struct {
+	Total    int                        `json:"total"`
+	ByStatus map[ReelDeliveryStatus]int `json:"by_status"`
+	// Recipients counts active recipients across all the reels, an email on two reels counts twice
+	Recipients int `json:"recipients"`
+	// StorageMB is the size of the reels' videos
+	StorageMB float64 `json:"storage_mb"`
+}
+
+// NewReelStats builds stats from reel counts per status, every status is listed even when it has no reels
+func NewReelStats(byStatus map[ReelDeliveryStatus]int, recipients int, storageMB float64) *ReelStats {
+	stats := &ReelStats{
+		ByStatus:   map[ReelDeliveryStatus]int{},
+		Recipients: recipients,
+		StorageMB:  storageMB,
+	}
+
+	for _, status := range []ReelDeliveryStatus{UnconfirmedReelStatus, ScheduledReelStatus, FailedReelStatus, DeliveredReelStatus} {
+		stats.ByStatus[status] = byStatus[status]
+		stats.Total += byStatus[status]
+	}
+
+	return stats
+}
+
+// TimelineMonthFormat is how months in a delivery timeline are written
+const TimelineMonthFormat = "2006-01"
+
+// MonthlyDeliveries is how many scheduled reels are due for delivery in a month
+type MonthlyDeliveries struct {
+	// Month is the year and month in UTC
+	Month string `json:"month"`
+	Reels int    `json:"reels"`
+}
+
+// TimelineWindow returns the end of a timeline starting at from and covering months
+// calendar months, the first being from's month
+func TimelineWindow(from time.Time, months int) time.Time {
+	from = from.UTC()
+	return time.Date(from.Year(), from.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NewDeliveryTimeline lays out delivery counts keyed by month over the timeline starting
+// at from, months without deliveries are included with a count of zero
+func NewDeliveryTimeline(from time.Time, months int, counts map[string]int) []MonthlyDeliveries {
+	from = from.UTC()
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	timeline := make([]MonthlyDeliveries, months)
+	for i := range timeline {
+		month := start.AddDate(0, i, 0).Format(TimelineMonthFormat)
+		timeline[i] = MonthlyDeliveries{Month: month, Reels: counts[month]}
+	}
+
+	return timeline
+}
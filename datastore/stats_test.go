@@ -0,0 +1,36 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReelStats(t *testing.T) {
+	stats := NewReelStats(map[ReelDeliveryStatus]int{ScheduledReelStatus: 2, DeliveredReelStatus: 1}, 4, 12.5)
+
+	require.Equal(t, &ReelStats{
+		Total: 3,
+		ByStatus: map[ReelDeliveryStatus]int{
+			UnconfirmedReelStatus: 0,
+			ScheduledReelStatus:   2,
+			FailedReelStatus:      0,
+			DeliveredReelStatus:   1,
+		},
+		Recipients: 4,
+		StorageMB:  12.5,
+	}, stats)
+}
+
+func TestNewDeliveryTimeline(t *testing.T) {
+	from := time.Date(2024, 11, 20, 15, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), TimelineWindow(from, 3))
+
+	timeline := NewDeliveryTimeline(from, 3, map[string]int{"2024-11": 1, "2025-01": 4})
+	require.Equal(t, []MonthlyDeliveries{
+		{Month: "2024-11", Reels: 1},
+		{Month: "2024-12", Reels: 0},
+		{Month: "2025-01", Reels: 4},
+	}, timeline)
+}
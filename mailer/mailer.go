@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/config"
+)
+
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	// IdempotencyKey is sent along with the message so redelivered copies can be discarded
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+type smtpMailer struct {
+	cfg config.MailerConfiguration
+}
+
+func NewSMTPMailer(cfg config.MailerConfiguration) Mailer {
+	return &smtpMailer{cfg: cfg}
+}
+
+func (s *smtpMailer) Send(ctx context.Context, message Message) error {
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	// the envelope sender must be a bare address, the From header may carry a display name
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(address, auth, from.Address, []string{message.To}, buildMessage(s.cfg.From, message))
+}
+
+func buildMessage(from string, message Message) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	if message.IdempotencyKey != "" {
+		fmt.Fprintf(&b, "X-Idempotency-Key: %s\r\n", message.IdempotencyKey)
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(message.Body)
+
+	return []byte(b.String())
+}
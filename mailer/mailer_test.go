@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	message := Message{To: "recipient@gmail.com", Subject: "Hello", Body: "<p>Hi</p>"}
+
+	b := string(buildMessage("Memoreel <no-reply@memoreel.com>", message))
+	require.Contains(t, b, "From: Memoreel <no-reply@memoreel.com>\r\n")
+	require.Contains(t, b, "To: recipient@gmail.com\r\n")
+	require.Contains(t, b, "\r\n\r\n<p>Hi</p>")
+	require.NotContains(t, b, "X-Idempotency-Key")
+
+	message.IdempotencyKey = "reel_delivery:123:456"
+	b = string(buildMessage("Memoreel <no-reply@memoreel.com>", message))
+	require.Contains(t, b, "X-Idempotency-Key: reel_delivery:123:456\r\n")
+}
@@ -0,0 +1,128 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"time"
+)
+
+var reelDeliveryTemplate = template.Must(template.New("reel_delivery").Parse(`
+<p>Hi there,</p>
+<p>Someone sent you a memoreel: <strong>{{.Title}}</strong>.</p>
+<p><a href="{{.ViewURL}}">Watch it here</a></p>
+<p>This link is personal to you, please don't share it.</p>
+`))
+
+var reelReminderTemplate = template.Must(template.New("reel_reminder").Parse(`
+<p>Hi there,</p>
+<p>Your memoreel <strong>{{.Title}}</strong> will be delivered on {{.DeliveryDate.Format "January 2, 2006"}}.</p>
+<p>If a recipient's email needs fixing, <a href="{{.ReelURL}}">update your reel</a> before then.</p>
+`))
+
+var reelResponseTemplate = template.Must(template.New("reel_response").Parse(`
+<p>Hi there,</p>
+{{- if eq .Kind "reaction"}}
+<p>{{.Recipient}} reacted {{.Body}} to your memoreel <strong>{{.Title}}</strong>.</p>
+{{- else if eq .Kind "note"}}
+<p>{{.Recipient}} left a note on your memoreel <strong>{{.Title}}</strong>:</p>
+<blockquote>{{.Body}}</blockquote>
+{{- else}}
+<p>{{.Recipient}} sent a reply video to your memoreel <strong>{{.Title}}</strong>.</p>
+{{- end}}
+<p><a href="{{.ReelURL}}">See all responses</a></p>
+`))
+
+var contributionInviteTemplate = template.Must(template.New("contribution_invite").Parse(`
+<p>Hi there,</p>
+<p>{{.Sender}} invited you to add a clip to the memoreel <strong>{{.Title}}</strong>.</p>
+<p><a href="{{.ContributeURL}}">Add your clip</a> before {{.Deadline.Format "January 2, 2006 at 15:04 MST"}}.</p>
+<p>This link is personal to you, please don't share it.</p>
+`))
+
+type ReelDeliveryData struct {
+	Title   string
+	ViewURL string
+}
+
+// ReelDeliveryMessage builds the email sent to a recipient when a reel is delivered
+func ReelDeliveryMessage(to string, data ReelDeliveryData) (Message, error) {
+	body, err := render(reelDeliveryTemplate, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{To: to, Subject: "You've received a memoreel", Body: body}, nil
+}
+
+type ReelReminderData struct {
+	Title        string
+	DeliveryDate time.Time
+	// SentAt is when the reminder goes out, the subject counts the days from it to delivery
+	SentAt  time.Time
+	ReelURL string
+}
+
+// ReelReminderMessage builds the email sent to a reel's creator ahead of its delivery
+func ReelReminderMessage(to string, data ReelReminderData) (Message, error) {
+	body, err := render(reelReminderTemplate, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	// part of a day left still counts as a day, so a reel due in 30 hours is 2 days away
+	days := int(math.Ceil(data.DeliveryDate.Sub(data.SentAt).Hours() / 24))
+
+	subject := fmt.Sprintf("Your memoreel will be delivered in %d days", days)
+	if days <= 1 {
+		subject = "Your memoreel will be delivered within a day"
+	}
+
+	return Message{To: to, Subject: subject, Body: body}, nil
+}
+
+type ReelResponseData struct {
+	Title     string
+	Recipient string
+	// Kind is reaction, note or video
+	Kind    string
+	Body    string
+	ReelURL string
+}
+
+// ReelResponseMessage builds the email sent to a reel's creator when a recipient responds to it
+func ReelResponseMessage(to string, data ReelResponseData) (Message, error) {
+	body, err := render(reelResponseTemplate, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{To: to, Subject: "Someone responded to your memoreel", Body: body}, nil
+}
+
+type ContributionInviteData struct {
+	Title         string
+	Sender        string
+	Deadline      time.Time
+	ContributeURL string
+}
+
+// ContributionInviteMessage builds the email inviting someone to contribute to a group reel
+func ContributionInviteMessage(to string, data ContributionInviteData) (Message, error) {
+	body, err := render(contributionInviteTemplate, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{To: to, Subject: "You've been invited to contribute to a memoreel", Body: body}, nil
+}
+
+func render(t *template.Template, data any) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
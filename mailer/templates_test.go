@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReelDeliveryMessage(t *testing.T) {
+	data := ReelDeliveryData{Title: "<b>Graduation</b>", ViewURL: "https://memoreel.com/view/abc.def"}
+
+	message, err := ReelDeliveryMessage("recipient@gmail.com", data)
+	require.NoError(t, err)
+
+	require.Equal(t, "recipient@gmail.com", message.To)
+	require.Contains(t, message.Body, data.ViewURL)
+
+	// titles are user provided and must be escaped
+	require.NotContains(t, message.Body, data.Title)
+	require.Contains(t, message.Body, "&lt;b&gt;Graduation&lt;/b&gt;")
+}
+
+func TestReelReminderMessage(t *testing.T) {
+	data := ReelReminderData{
+		Title:        "Graduation",
+		DeliveryDate: time.Date(2030, time.June, 1, 0, 0, 0, 0, time.UTC),
+		SentAt:       time.Date(2030, time.May, 26, 9, 0, 0, 0, time.UTC),
+		ReelURL:      "https://memoreel.com/reels/123",
+	}
+
+	// the subject counts the days actually left, not the reminder's window
+	message, err := ReelReminderMessage("creator@gmail.com", data)
+	require.NoError(t, err)
+
+	require.Equal(t, "Your memoreel will be delivered in 6 days", message.Subject)
+	require.Contains(t, message.Body, "June 1, 2030")
+	require.Contains(t, message.Body, data.ReelURL)
+
+	data.SentAt = data.DeliveryDate.Add(-3 * time.Hour)
+	message, err = ReelReminderMessage("creator@gmail.com", data)
+	require.NoError(t, err)
+	require.Equal(t, "Your memoreel will be delivered within a day", message.Subject)
+}
+
+func TestReelResponseMessage(t *testing.T) {
+	data := ReelResponseData{
+		Title:     "Graduation",
+		Recipient: "friend@gmail.com",
+		Kind:      "note",
+		Body:      "<script>alert(1)</script>",
+		ReelURL:   "https://memoreel.com/reels/123",
+	}
+
+	message, err := ReelResponseMessage("creator@gmail.com", data)
+	require.NoError(t, err)
+
+	require.Equal(t, "creator@gmail.com", message.To)
+	require.Contains(t, message.Body, "left a note")
+	require.Contains(t, message.Body, data.ReelURL)
+
+	// notes are written by recipients and must be escaped
+	require.NotContains(t, message.Body, data.Body)
+
+	data.Kind = "video"
+	message, err = ReelResponseMessage("creator@gmail.com", data)
+	require.NoError(t, err)
+	require.Contains(t, message.Body, "sent a reply video")
+}
+
+func TestContributionInviteMessage(t *testing.T) {
+	data := ContributionInviteData{
+		Title:         "Retirement",
+		Sender:        "boss@gmail.com",
+		Deadline:      time.Date(2030, time.June, 1, 18, 30, 0, 0, time.UTC),
+		ContributeURL: "https://memoreel.com/contribute/abc.def",
+	}
+
+	message, err := ContributionInviteMessage("colleague@gmail.com", data)
+	require.NoError(t, err)
+
+	require.Equal(t, "colleague@gmail.com", message.To)
+	require.Contains(t, message.Body, data.ContributeURL)
+	require.Contains(t, message.Body, "June 1, 2030 at 18:30 UTC")
+}
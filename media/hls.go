@@ -0,0 +1,128 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const MasterPlaylistName = "master.m3u8"
+
+type Rendition struct {
+	Name string
+	// Height in pixels, width is scaled to keep the source aspect ratio
+	Height int
+	// VideoBitrate and AudioBitrate are in kbps
+	VideoBitrate int
+	AudioBitrate int
+}
+
+// Bandwidth is the peak bits per second advertised in the master playlist
+func (r Rendition) Bandwidth() int {
+	return (r.VideoBitrate + r.AudioBitrate) * 1000
+}
+
+var DefaultRenditions = []Rendition{
+	{Name: "360p", Height: 360, VideoBitrate: 800, AudioBitrate: 96},
+	{Name: "480p", Height: 480, VideoBitrate: 1400, AudioBitrate: 128},
+	{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+}
+
+// HLSPackager packages a video into HLS renditions using a local ffmpeg binary
+type HLSPackager struct {
+	FFmpegPath string
+	Renditions []Rendition
+	// SegmentDuration is the target length of each segment in seconds
+	SegmentDuration int
+}
+
+func NewHLSPackager(ffmpegPath string) *HLSPackager {
+	return &HLSPackager{FFmpegPath: ffmpegPath, Renditions: DefaultRenditions, SegmentDuration: 6}
+}
+
+// Package writes a master playlist to outputDir along with a
+// sub directory holding the playlist and segments for each rendition
+func (h *HLSPackager) Package(ctx context.Context, inputPath string, outputDir string) error {
+	for _, rendition := range h.Renditions {
+		if err := os.MkdirAll(filepath.Join(outputDir, rendition.Name), 0o755); err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(ctx, h.FFmpegPath, h.ffmpegArgs(inputPath, outputDir, rendition)...)
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("ffmpeg failed for rendition %s: %w: %s", rendition.Name, err, output)
+		}
+	}
+
+	return os.WriteFile(filepath.Join(outputDir, MasterPlaylistName), BuildMasterPlaylist(h.Renditions), 0o644)
+}
+
+func (h *HLSPackager) ffmpegArgs(inputPath, outputDir string, rendition Rendition) []string {
+	renditionDir := filepath.Join(outputDir, rendition.Name)
+
+	return []string{
+		"-y",
+		"-i", inputPath,
+		"-vf", fmt.Sprintf("scale=-2:%d", rendition.Height),
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-b:v", fmt.Sprintf("%dk", rendition.VideoBitrate),
+		"-maxrate", fmt.Sprintf("%dk", rendition.VideoBitrate),
+		"-bufsize", fmt.Sprintf("%dk", rendition.VideoBitrate*2),
+		"-c:a", "aac",
+		"-b:a", fmt.Sprintf("%dk", rendition.AudioBitrate),
+		"-f", "hls",
+		"-hls_time", fmt.Sprint(h.SegmentDuration),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(renditionDir, "segment_%03d.ts"),
+		filepath.Join(renditionDir, "playlist.m3u8"),
+	}
+}
+
+// BuildMasterPlaylist returns a master playlist referencing each rendition's playlist by relative uri
+func BuildMasterPlaylist(renditions []Rendition) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+
+	for _, rendition := range renditions {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,NAME=\"%s\"\n", rendition.Bandwidth(), rendition.Name)
+		fmt.Fprintf(&b, "%s/playlist.m3u8\n", rendition.Name)
+	}
+
+	return b.Bytes()
+}
+
+// RewritePlaylistURIs replaces every uri line in a playlist with the result of resolve
+func RewritePlaylistURIs(playlist []byte, resolve func(uri string) (string, error)) ([]byte, error) {
+	var b bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			resolved, err := resolve(line)
+			if err != nil {
+				return nil, err
+			}
+			line = resolved
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+package media
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMasterPlaylist(t *testing.T) {
+	renditions := []Rendition{
+		{Name: "360p", Height: 360, VideoBitrate: 800, AudioBitrate: 96},
+		{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	}
+
+	expected := "#EXTM3U\n" +
+		"#EXT-X-VERSION:3\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=896000,NAME=\"360p\"\n" +
+		"360p/playlist.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2928000,NAME=\"720p\"\n" +
+		"720p/playlist.m3u8\n"
+
+	require.Equal(t, expected, string(BuildMasterPlaylist(renditions)))
+}
+
+func TestRewritePlaylistURIs(t *testing.T) {
+	playlist := BuildMasterPlaylist(DefaultRenditions)
+
+	rewritten, err := RewritePlaylistURIs(playlist, func(uri string) (string, error) {
+		return "https://cdn.memoreel.com/hls/123/" + uri, nil
+	})
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(rewritten)), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		require.True(t, strings.HasPrefix(line, "https://cdn.memoreel.com/hls/123/"), line)
+	}
+}
+
+func TestFFmpegArgs(t *testing.T) {
+	packager := NewHLSPackager("ffmpeg")
+	rendition := Rendition{Name: "480p", Height: 480, VideoBitrate: 1400, AudioBitrate: 128}
+
+	args := packager.ffmpegArgs("input.mp4", "out", rendition)
+
+	require.Equal(t, "input.mp4", args[2])
+	require.Contains(t, args, "scale=-2:480")
+	require.Contains(t, args, "1400k")
+	require.Equal(t, filepath.Join("out", "480p", "playlist.m3u8"), args[len(args)-1])
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("email verification token is invalid or has expired")
+)
+
+type AccountService struct {
+	UnitOfWork datastore.UnitOfWork
+}
+
+// VerifyEmail marks the user's email as verified and, in the same transaction,
+// hands them ownership of every reel that was created with that email
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) (*datastore.User, error) {
+	// verified users keep an empty token, so an empty one would match them
+	if token == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	var user *datastore.User
+
+	err := s.UnitOfWork.RunInTx(ctx, func(repos datastore.Repositories) error {
+		var err error
+
+		user, err = repos.Users.GetUserByEmailVerificationToken(ctx, token)
+		if err != nil {
+			if errors.Is(err, datastore.ErrUserNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		if user.EmailVerificationExpiresAt.Valid && user.EmailVerificationExpiresAt.Time.Before(time.Now()) {
+			return ErrInvalidVerificationToken
+		}
+
+		user.EmailVerified = true
+		user.EmailVerificationToken = ""
+		user.EmailVerificationExpiresAt = null.Time{}
+
+		if err := repos.Users.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+
+		return repos.Reels.AssignReelsToUserByEmail(ctx, user.Email, user.UID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
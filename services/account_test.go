@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepo(store)
+	reelRepo := memory.NewReelRepo(store)
+
+	service := &AccountService{UnitOfWork: memory.NewUnitOfWork(store)}
+
+	user := datastoretest.GenerateUser()
+	user.EmailVerificationExpiresAt = null.TimeFrom(time.Now().Add(time.Hour))
+	require.NoError(t, userRepo.CreateUser(context.Background(), user))
+
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(context.Background(), video))
+
+	reel := datastoretest.GenerateReel(video.UID, "")
+	reel.UserID = null.String{}
+	reel.Email = user.Email
+	require.NoError(t, reelRepo.CreateReel(context.Background(), reel))
+
+	_, err := service.VerifyEmail(context.Background(), "invalid")
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+
+	verified, err := service.VerifyEmail(context.Background(), user.EmailVerificationToken)
+	require.NoError(t, err)
+	require.True(t, verified.EmailVerified)
+
+	dbReel, err := reelRepo.GetReelByID(context.Background(), reel.UID)
+	require.NoError(t, err)
+	require.Equal(t, user.UID, dbReel.UserID.String)
+
+	// the token can only be used once
+	_, err = service.VerifyEmail(context.Background(), user.EmailVerificationToken)
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+}
+
+func TestVerifyEmailExpiredToken(t *testing.T) {
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepo(store)
+
+	service := &AccountService{UnitOfWork: memory.NewUnitOfWork(store)}
+
+	user := datastoretest.GenerateUser()
+	user.EmailVerificationExpiresAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	require.NoError(t, userRepo.CreateUser(context.Background(), user))
+
+	_, err := service.VerifyEmail(context.Background(), user.EmailVerificationToken)
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.UID)
+	require.NoError(t, err)
+	require.False(t, dbUser.EmailVerified)
+}
+
+func TestVerifyEmailEmptyToken(t *testing.T) {
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepo(store)
+
+	service := &AccountService{UnitOfWork: memory.NewUnitOfWork(store)}
+
+	// a verified user no longer has a token
+	user := datastoretest.GenerateUser()
+	user.EmailVerified = true
+	user.EmailVerificationToken = ""
+	user.EmailVerificationExpiresAt = null.Time{}
+	require.NoError(t, userRepo.CreateUser(context.Background(), user))
+
+	_, err := service.VerifyEmail(context.Background(), "")
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/calendar"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	calendarEventDuration = 30 * time.Minute
+	// maxCalendarEvents caps how many upcoming deliveries a feed lists
+	maxCalendarEvents = 500
+)
+
+var ErrInvalidCalendarToken = errors.New("calendar feed link is invalid")
+
+// CalendarService serves a user's scheduled deliveries as an iCalendar feed. The feed url
+// carries a secret token instead of credentials since calendar apps can't log in
+type CalendarService struct {
+	UserRepo datastore.UserRepository
+	ReelRepo datastore.ReelRepository
+	APIURL   string
+}
+
+// RotateToken gives the user a new calendar token and returns the feed url. Calendars
+// subscribed with the old url stop receiving updates
+func (s *CalendarService) RotateToken(ctx context.Context, user *datastore.User) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	user.CalendarToken = null.StringFrom(token)
+
+	if err := s.UserRepo.UpdateUser(ctx, user); err != nil {
+		return "", err
+	}
+
+	return s.FeedURL(token)
+}
+
+func (s *CalendarService) FeedURL(token string) (string, error) {
+	return url.JoinPath(s.APIURL, "v1", "calendar", token+".ics")
+}
+
+// Feed lists the scheduled reels of the user the token belongs to as calendar events
+func (s *CalendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrInvalidCalendarToken
+	}
+
+	user, err := s.UserRepo.GetUserByCalendarToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, datastore.ErrUserNotFound) {
+			return nil, ErrInvalidCalendarToken
+		}
+		return nil, err
+	}
+
+	filter := datastore.ReelFilter{DeliveryStatus: datastore.ScheduledReelStatus}
+	pageable := datastore.Pageable{
+		PerPage: maxCalendarEvents,
+		Sort:    datastore.Sort{Field: datastore.ReelSortDeliveryDate, Order: datastore.SortAsc},
+	}
+
+	reels, _, err := s.ReelRepo.GetReelsPaged(ctx, user.UID, filter, pageable)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := calendar.Feed{Name: "Memoreel deliveries"}
+	for _, reel := range reels {
+		feed.Events = append(feed.Events, calendar.Event{
+			UID:         reel.UID + "@memoreel",
+			Summary:     "Reel delivery: " + reel.Title,
+			Description: recipientCount(len(reel.Recipients)),
+			Start:       reel.DeliveryDate,
+			End:         reel.DeliveryDate.Add(calendarEventDuration),
+			Stamp:       reel.UpdatedAt,
+			Sequence:    reel.Version,
+		})
+	}
+
+	return feed.Encode(), nil
+}
+
+func recipientCount(n int) string {
+	if n == 1 {
+		return "Delivering to 1 recipient"
+	}
+	return fmt.Sprintf("Delivering to %d recipients", n)
+}
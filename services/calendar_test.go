@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeed(t *testing.T) {
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepo(store)
+	reelRepo := memory.NewReelRepo(store)
+
+	service := &CalendarService{UserRepo: userRepo, ReelRepo: reelRepo, APIURL: "https://memoreel.com/api"}
+
+	user := datastoretest.GenerateUser()
+	require.NoError(t, userRepo.CreateUser(context.Background(), user))
+
+	var reels []*datastore.Reel
+	for _, status := range []datastore.ReelDeliveryStatus{datastore.ScheduledReelStatus, datastore.UnconfirmedReelStatus} {
+		video := datastoretest.GenerateVideo()
+		require.NoError(t, memory.NewVideoRepo(store).CreateVideo(context.Background(), video))
+
+		reel := datastoretest.GenerateReel(video.UID, user.UID)
+		reel.DeliveryStatus = status
+		require.NoError(t, reelRepo.CreateReel(context.Background(), reel))
+		reels = append(reels, reel)
+	}
+
+	// there's no feed until a token is issued
+	_, err := service.Feed(context.Background(), "")
+	require.ErrorIs(t, err, ErrInvalidCalendarToken)
+
+	feedURL, err := service.RotateToken(context.Background(), user)
+	require.NoError(t, err)
+	require.Equal(t, "https://memoreel.com/api/v1/calendar/"+user.CalendarToken.String+".ics", feedURL)
+
+	feed, err := service.Feed(context.Background(), user.CalendarToken.String)
+	require.NoError(t, err)
+	require.Contains(t, string(feed), "UID:"+reels[0].UID+"@memoreel")
+	require.Contains(t, string(feed), "DESCRIPTION:Delivering to 2 recipients")
+	require.NotContains(t, string(feed), reels[1].UID)
+	require.Equal(t, 1, strings.Count(string(feed), "BEGIN:VEVENT"))
+
+	// rotating retires the old url
+	old := user.CalendarToken.String
+	_, err = service.RotateToken(context.Background(), user)
+	require.NoError(t, err)
+	require.NotEqual(t, old, user.CalendarToken.String)
+
+	_, err = service.Feed(context.Background(), old)
+	require.ErrorIs(t, err, ErrInvalidCalendarToken)
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	contributionAudience = "reel_contribution"
+	// maxClipsPerContributor caps the clips each contributor can add to a reel, including
+	// rejected ones. Clips the owner removes free up room
+	maxClipsPerContributor = 10
+)
+
+var (
+	ErrInvalidContributeLink       = errors.New("contribution link is invalid or has been withdrawn")
+	ErrContributionsClosed         = errors.New("the reel isn't accepting contributions")
+	ErrContributionsLocked         = errors.New("contributions can't be changed once the reel is delivered")
+	ErrInvalidContributionDeadline = errors.New("contribution deadline must be in the future and before the reel's delivery")
+	ErrInvalidContributionStatus   = errors.New("a contribution can only be approved or rejected")
+)
+
+// Contributing is a contributor identified by their invite link, along with the reel they were invited to
+type Contributing struct {
+	Reel        *datastore.Reel
+	Contributor datastore.Contributor
+}
+
+// ContributorReelView is what a contributor sees of the reel they were invited to. Like
+// RecipientReelView it leaves out the recipients, and only lists the contributor's own clips
+type ContributorReelView struct {
+	Title         string                   `json:"title"`
+	Description   string                   `json:"description"`
+	Deadline      null.Time                `json:"contribution_deadline"`
+	Open          bool                     `json:"open"`
+	Contributions []datastore.Contribution `json:"contributions"`
+}
+
+// ContributionView is a contribution as the reel's owner sees it
+type ContributionView struct {
+	datastore.Contribution
+	VideoURL string `json:"video_url"`
+}
+
+// ContributionService turns a reel into a group reel: its owner invites contributors by
+// email, who upload clips until the contribution deadline. The owner approves, orders and
+// removes clips up to delivery, approved ones are delivered after the reel's own video
+type ContributionService struct {
+	UnitOfWork       datastore.UnitOfWork
+	ContributionRepo datastore.ContributionRepository
+	ReelRepo         datastore.ReelRepository
+	VideoRepo        datastore.VideoRepository
+	Signer           *token.Signer
+	Storage          storage.Storage
+	// MaxVideoBytes caps the size of contributed clips
+	MaxVideoBytes int64
+	ClientURL     string
+}
+
+// SetDeadline opens the reel to contributions until the deadline, a null deadline closes it
+func (s *ContributionService) SetDeadline(ctx context.Context, reel *datastore.Reel, deadline null.Time) error {
+	if reel.DeliveryStatus == datastore.DeliveredReelStatus {
+		return ErrContributionsLocked
+	}
+
+	if deadline.Valid {
+		if !deadline.Time.After(time.Now()) || !deadline.Time.Before(reel.DeliveryDate) {
+			return ErrInvalidContributionDeadline
+		}
+		deadline.Time = deadline.Time.UTC()
+	}
+
+	reel.ContributionDeadline = deadline
+
+	return s.ReelRepo.UpdateReel(ctx, reel)
+}
+
+// Invite adds a contributor to the reel and emails them a link to upload their clips with
+func (s *ContributionService) Invite(ctx context.Context, reel *datastore.Reel, email string) (*datastore.Contributor, error) {
+	if !contributionsOpen(reel, time.Now()) {
+		return nil, ErrContributionsClosed
+	}
+
+	contributor := &datastore.Contributor{
+		UID:    ulid.Make().String(),
+		ReelID: reel.UID,
+		Email:  strings.ToLower(email),
+	}
+
+	signed, err := s.Signer.Sign(token.Claims{Subject: contributor.UID, Audience: contributionAudience})
+	if err != nil {
+		return nil, err
+	}
+
+	contributeURL, err := url.JoinPath(s.ClientURL, "contribute", signed)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := mailer.ContributionInviteMessage(contributor.Email, mailer.ContributionInviteData{
+		Title:         reel.Title,
+		Sender:        reel.Email,
+		Deadline:      reel.ContributionDeadline.Time,
+		ContributeURL: contributeURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outboxMessage, err := NewEmailMessage(fmt.Sprintf("reel_contribution_invite:%s", contributor.UID), message)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ContributionRepo.CreateContributor(ctx, contributor, outboxMessage); err != nil {
+		return nil, err
+	}
+
+	return contributor, nil
+}
+
+// ContributorFromLink identifies a contributor by the signed link from their invite email.
+// Links stop working once the contributor is removed
+func (s *ContributionService) ContributorFromLink(ctx context.Context, signedToken string) (*Contributing, error) {
+	claims, err := s.Signer.Verify(signedToken, contributionAudience)
+	if err != nil {
+		return nil, ErrInvalidContributeLink
+	}
+
+	contributor, err := s.ContributionRepo.GetContributorByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, datastore.ErrContributorNotFound) {
+			return nil, ErrInvalidContributeLink
+		}
+		return nil, err
+	}
+
+	reel, err := s.ReelRepo.GetReelByID(ctx, contributor.ReelID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			return nil, ErrInvalidContributeLink
+		}
+		return nil, err
+	}
+
+	return &Contributing{Reel: reel, Contributor: *contributor}, nil
+}
+
+// View returns the contributor's view of the reel they were invited to
+func (s *ContributionService) View(ctx context.Context, contributing *Contributing) (*ContributorReelView, error) {
+	contributions, err := s.ContributionRepo.GetContributionsByReel(ctx, contributing.Reel.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &ContributorReelView{
+		Title:         contributing.Reel.Title,
+		Description:   contributing.Reel.Description,
+		Deadline:      contributing.Reel.ContributionDeadline,
+		Open:          contributionsOpen(contributing.Reel, time.Now()),
+		Contributions: []datastore.Contribution{},
+	}
+
+	for _, contribution := range contributions {
+		if contribution.ContributorID == contributing.Contributor.UID {
+			view.Contributions = append(view.Contributions, contribution)
+		}
+	}
+
+	return view, nil
+}
+
+// Contribute uploads a clip of the given file format, e.g. mp4, to the reel. It waits for
+// the owner's approval before it's delivered
+func (s *ContributionService) Contribute(ctx context.Context, contributing *Contributing, r io.Reader, fileFormat string) (*datastore.Contribution, error) {
+	if !contributionsOpen(contributing.Reel, time.Now()) {
+		return nil, ErrContributionsClosed
+	}
+
+	contribution := &datastore.Contribution{
+		UID:           ulid.Make().String(),
+		ReelID:        contributing.Reel.UID,
+		ContributorID: contributing.Contributor.UID,
+		Status:        datastore.PendingContribution,
+	}
+
+	video, err := storeUpload(ctx, s.Storage, "contributions", contribution.UID, r, fileFormat, s.MaxVideoBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	contribution.VideoID = video.UID
+
+	err = s.UnitOfWork.RunInTx(ctx, func(repos datastore.Repositories) error {
+		if err := repos.Videos.CreateVideo(ctx, video); err != nil {
+			return err
+		}
+
+		return repos.Contributions.CreateContribution(ctx, contribution, maxClipsPerContributor)
+	})
+	if err != nil {
+		// the contribution wasn't saved, so nothing refers to its upload
+		return nil, errors.Join(err, s.Storage.Delete(ctx, video.Key))
+	}
+
+	return contribution, nil
+}
+
+// Contributions lists every contribution to the reel in delivery order for its owner
+func (s *ContributionService) Contributions(ctx context.Context, reel *datastore.Reel) ([]ContributionView, error) {
+	contributions, err := s.ContributionRepo.GetContributionsByReel(ctx, reel.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]ContributionView, len(contributions))
+	for i, contribution := range contributions {
+		views[i] = ContributionView{Contribution: contribution}
+
+		video, err := s.VideoRepo.GetVideoByID(ctx, contribution.VideoID)
+		if err != nil {
+			return nil, err
+		}
+
+		if views[i].VideoURL, err = s.Storage.URL(ctx, video.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
+
+func (s *ContributionService) SetStatus(ctx context.Context, reel *datastore.Reel, contributionID string, status datastore.ContributionStatus) error {
+	if status != datastore.ApprovedContribution && status != datastore.RejectedContribution {
+		return ErrInvalidContributionStatus
+	}
+
+	if reel.DeliveryStatus == datastore.DeliveredReelStatus {
+		return ErrContributionsLocked
+	}
+
+	return s.ContributionRepo.SetContributionStatus(ctx, reel.UID, contributionID, status)
+}
+
+// Reorder sets the order approved contributions are delivered in, it must list every contribution
+func (s *ContributionService) Reorder(ctx context.Context, reel *datastore.Reel, contributionIDs []string) error {
+	if reel.DeliveryStatus == datastore.DeliveredReelStatus {
+		return ErrContributionsLocked
+	}
+
+	return s.ContributionRepo.ReorderContributions(ctx, reel.UID, contributionIDs)
+}
+
+// Remove deletes a contribution and its clip
+func (s *ContributionService) Remove(ctx context.Context, reel *datastore.Reel, contributionID string) error {
+	if reel.DeliveryStatus == datastore.DeliveredReelStatus {
+		return ErrContributionsLocked
+	}
+
+	video, err := s.ContributionRepo.DeleteContribution(ctx, reel.UID, contributionID)
+	if err != nil {
+		return err
+	}
+
+	return deleteVideoObjects(ctx, s.Storage, *video)
+}
+
+// RemoveContributor withdraws a contributor's invite. The clips the owner approved stay in
+// the reel, the rest are deleted since the contributor can no longer manage them
+func (s *ContributionService) RemoveContributor(ctx context.Context, reel *datastore.Reel, contributorID string) error {
+	videos, err := s.ContributionRepo.RemoveContributor(ctx, reel.UID, contributorID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, video := range videos {
+		if err := deleteVideoObjects(ctx, s.Storage, video); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// approvedClips returns the storage urls of the reel's approved contributions in delivery order
+func approvedClips(ctx context.Context, repo datastore.ContributionRepository, videos datastore.VideoRepository, store storage.Storage, reelID string) ([]string, error) {
+	contributions, err := repo.GetContributionsByReel(ctx, reelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var clips []string
+	for _, contribution := range contributions {
+		if contribution.Status != datastore.ApprovedContribution {
+			continue
+		}
+
+		video, err := videos.GetVideoByID(ctx, contribution.VideoID)
+		if err != nil {
+			return nil, err
+		}
+
+		clip, err := store.URL(ctx, video.Key)
+		if err != nil {
+			return nil, err
+		}
+		clips = append(clips, clip)
+	}
+
+	return clips, nil
+}
+
+// contributionsOpen reports whether the reel takes new contributors and clips at the given time
+func contributionsOpen(reel *datastore.Reel, at time.Time) bool {
+	return reel.DeliveryStatus != datastore.DeliveredReelStatus &&
+		reel.ContributionDeadline.Valid && at.Before(reel.ContributionDeadline.Time)
+}
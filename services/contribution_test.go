@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func newContributionService(t *testing.T) (*ContributionService, *memory.Store, *datastore.Reel) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	reel := datastoretest.SeedReel(t, repos, datastoretest.SeedUser(t, repos).UID, datastore.ScheduledReelStatus)
+
+	return &ContributionService{
+		UnitOfWork:       memory.NewUnitOfWork(store),
+		ContributionRepo: repos.Contributions,
+		ReelRepo:         repos.Reels,
+		VideoRepo:        repos.Videos,
+		Signer:           token.NewSigner("secret"),
+		Storage:          storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir(), BaseURL: "http://localhost/media"}),
+		MaxVideoBytes:    1024,
+		ClientURL:        "http://localhost:3000",
+	}, store, reel
+}
+
+func TestContributionDeadline(t *testing.T) {
+	service, _, reel := newContributionService(t)
+	ctx := context.Background()
+
+	// contributions are closed until a deadline is set
+	_, err := service.Invite(ctx, reel, "friend@gmail.com")
+	require.ErrorIs(t, err, ErrContributionsClosed)
+
+	for _, deadline := range []time.Time{time.Now().Add(-time.Hour), reel.DeliveryDate.Add(time.Hour)} {
+		err = service.SetDeadline(ctx, reel, null.TimeFrom(deadline))
+		require.ErrorIs(t, err, ErrInvalidContributionDeadline)
+	}
+
+	require.NoError(t, service.SetDeadline(ctx, reel, null.TimeFrom(time.Now().Add(time.Hour))))
+
+	saved, err := service.ReelRepo.GetReelByID(ctx, reel.UID)
+	require.NoError(t, err)
+	require.True(t, saved.ContributionDeadline.Valid)
+
+	require.NoError(t, service.SetDeadline(ctx, reel, null.Time{}))
+	_, err = service.Invite(ctx, reel, "friend@gmail.com")
+	require.ErrorIs(t, err, ErrContributionsClosed)
+}
+
+func TestContribute(t *testing.T) {
+	service, store, reel := newContributionService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.SetDeadline(ctx, reel, null.TimeFrom(time.Now().Add(time.Hour))))
+
+	contributor, err := service.Invite(ctx, reel, "Friend@Gmail.com")
+	require.NoError(t, err)
+	require.Equal(t, "friend@gmail.com", contributor.Email)
+
+	// the invite carries the contributor's link
+	messages := notifications(t, store)
+	require.Len(t, messages, 1)
+	require.Contains(t, string(messages[0].Payload), "friend@gmail.com")
+	require.Contains(t, string(messages[0].Payload), "http://localhost:3000/contribute/")
+
+	_, err = service.Invite(ctx, reel, "friend@gmail.com")
+	require.ErrorIs(t, err, datastore.ErrDuplicateContributor)
+
+	signed, err := service.Signer.Sign(token.Claims{Subject: contributor.UID, Audience: contributionAudience})
+	require.NoError(t, err)
+
+	_, err = service.ContributorFromLink(ctx, "not-a-token")
+	require.ErrorIs(t, err, ErrInvalidContributeLink)
+
+	contributing, err := service.ContributorFromLink(ctx, signed)
+	require.NoError(t, err)
+	require.Equal(t, reel.UID, contributing.Reel.UID)
+
+	_, err = service.Contribute(ctx, contributing, bytes.NewReader(make([]byte, 2048)), "mp4")
+	require.ErrorIs(t, err, ErrVideoTooLarge)
+
+	_, err = service.Contribute(ctx, contributing, bytes.NewReader([]byte("clip")), "avi")
+	require.ErrorIs(t, err, ErrUnsupportedVideo)
+
+	first, err := service.Contribute(ctx, contributing, bytes.NewReader([]byte("first")), "mp4")
+	require.NoError(t, err)
+	require.Equal(t, datastore.PendingContribution, first.Status)
+
+	second, err := service.Contribute(ctx, contributing, bytes.NewReader([]byte("second")), "webm")
+	require.NoError(t, err)
+
+	view, err := service.View(ctx, contributing)
+	require.NoError(t, err)
+	require.True(t, view.Open)
+	require.Len(t, view.Contributions, 2)
+
+	// the owner approves, orders and removes clips
+	require.ErrorIs(t, service.SetStatus(ctx, reel, first.UID, datastore.PendingContribution), ErrInvalidContributionStatus)
+	require.NoError(t, service.SetStatus(ctx, reel, first.UID, datastore.ApprovedContribution))
+	require.NoError(t, service.SetStatus(ctx, reel, second.UID, datastore.ApprovedContribution))
+	require.NoError(t, service.Reorder(ctx, reel, []string{second.UID, first.UID}))
+
+	contributions, err := service.Contributions(ctx, reel)
+	require.NoError(t, err)
+	require.Len(t, contributions, 2)
+	require.Equal(t, second.UID, contributions[0].UID)
+	require.Contains(t, contributions[0].VideoURL, "contributions/"+second.UID+".webm")
+
+	require.NoError(t, service.Remove(ctx, reel, first.UID))
+
+	clips, err := approvedClips(ctx, service.ContributionRepo, service.VideoRepo, service.Storage, reel.UID)
+	require.NoError(t, err)
+	require.Equal(t, []string{contributions[0].VideoURL}, clips)
+
+	// no more clips once the deadline passes
+	contributing.Reel.ContributionDeadline = null.TimeFrom(time.Now().Add(-time.Minute))
+	_, err = service.Contribute(ctx, contributing, bytes.NewReader([]byte("late")), "mp4")
+	require.ErrorIs(t, err, ErrContributionsClosed)
+
+	// and nothing changes once the reel is delivered
+	reel.DeliveryStatus = datastore.DeliveredReelStatus
+	require.ErrorIs(t, service.Remove(ctx, reel, second.UID), ErrContributionsLocked)
+	require.ErrorIs(t, service.Reorder(ctx, reel, []string{second.UID}), ErrContributionsLocked)
+
+	// removed contributors lose their link
+	require.NoError(t, service.RemoveContributor(ctx, reel, contributor.UID))
+	_, err = service.ContributorFromLink(ctx, signed)
+	require.ErrorIs(t, err, ErrInvalidContributeLink)
+}
+
+func TestContributionCleanup(t *testing.T) {
+	service, _, reel := newContributionService(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	service.Storage = storage.NewLocalStorage(config.StorageConfiguration{Directory: dir})
+
+	require.NoError(t, service.SetDeadline(ctx, reel, null.TimeFrom(time.Now().Add(time.Hour))))
+
+	contributor, err := service.Invite(ctx, reel, "friend@gmail.com")
+	require.NoError(t, err)
+	contributing := &Contributing{Reel: reel, Contributor: *contributor}
+
+	clips := make([]*datastore.Contribution, maxClipsPerContributor)
+	for i := range clips {
+		clips[i], err = service.Contribute(ctx, contributing, bytes.NewReader([]byte("clip")), "mp4")
+		require.NoError(t, err)
+	}
+
+	// a clip over the limit isn't kept in storage either
+	_, err = service.Contribute(ctx, contributing, bytes.NewReader([]byte("clip")), "mp4")
+	require.ErrorIs(t, err, datastore.ErrContributionLimitReached)
+
+	stored := func(contribution *datastore.Contribution) bool {
+		_, err := os.Stat(filepath.Join(dir, "contributions", contribution.UID+".mp4"))
+		return err == nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "contributions"))
+	require.NoError(t, err)
+	require.Len(t, entries, maxClipsPerContributor)
+
+	// removing a clip deletes its upload
+	require.NoError(t, service.Remove(ctx, reel, clips[0].UID))
+	require.False(t, stored(clips[0]))
+
+	// removing the contributor deletes their clips, except the approved ones
+	require.NoError(t, service.SetStatus(ctx, reel, clips[1].UID, datastore.ApprovedContribution))
+	require.NoError(t, service.RemoveContributor(ctx, reel, contributor.UID))
+	require.True(t, stored(clips[1]))
+	for _, clip := range clips[2:] {
+		require.False(t, stored(clip))
+	}
+
+	contributions, err := service.Contributions(ctx, reel)
+	require.NoError(t, err)
+	require.Len(t, contributions, 1)
+	require.Equal(t, clips[1].UID, contributions[0].UID)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+)
+
+type DeliveryService struct {
+	UnitOfWork datastore.UnitOfWork
+	Links      *RecipientLinkService
+	ClientURL  string
+}
+
+// DeliverReel marks the reel as delivered and, in the same transaction, issues every
+// recipient a personal viewing link and enqueues an email with it. A delivery that
+// fails leaves no links behind
+func (s *DeliveryService) DeliverReel(ctx context.Context, reel *datastore.Reel) error {
+	return s.UnitOfWork.RunInTx(ctx, func(repos datastore.Repositories) error {
+		return s.deliver(ctx, repos, reel)
+	})
+}
+
+func (s *DeliveryService) deliver(ctx context.Context, repos datastore.Repositories, reel *datastore.Reel) error {
+	messages := make([]datastore.OutboxMessage, 0, len(reel.Recipients))
+
+	for _, recipient := range reel.Recipients {
+		signedToken, err := s.Links.issueLink(ctx, repos.ViewTokens, reel, recipient)
+		if err != nil {
+			return err
+		}
+
+		viewURL, err := url.JoinPath(s.ClientURL, "view", signedToken)
+		if err != nil {
+			return err
+		}
+
+		email, err := mailer.ReelDeliveryMessage(recipient.Email, mailer.ReelDeliveryData{
+			Title:   reel.Title,
+			ViewURL: viewURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		message, err := NewEmailMessage(fmt.Sprintf("reel_delivery:%s:%s", reel.UID, recipient.UID), email)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, message)
+	}
+
+	reel.DeliveryStatus = datastore.DeliveredReelStatus
+
+	return repos.Reels.UpdateReel(ctx, reel, messages...)
+}
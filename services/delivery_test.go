@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+// recordingUnitOfWork notes the id of every view token created in its transactions
+type recordingUnitOfWork struct {
+	datastore.UnitOfWork
+	issued *[]string
+}
+
+func (u recordingUnitOfWork) RunInTx(ctx context.Context, fn func(repos datastore.Repositories) error) error {
+	return u.UnitOfWork.RunInTx(ctx, func(repos datastore.Repositories) error {
+		repos.ViewTokens = recordingViewTokens{ViewTokenRepository: repos.ViewTokens, issued: u.issued}
+		return fn(repos)
+	})
+}
+
+type recordingViewTokens struct {
+	datastore.ViewTokenRepository
+	issued *[]string
+}
+
+func (r recordingViewTokens) CreateViewToken(ctx context.Context, viewToken *datastore.ViewToken) error {
+	*r.issued = append(*r.issued, viewToken.UID)
+	return r.ViewTokenRepository.CreateViewToken(ctx, viewToken)
+}
+
+func TestDeliverReel(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(ctx, video))
+
+	reel := datastoretest.GenerateReel(video.UID, "")
+	reel.UserID = null.String{}
+	reel.DeliveryStatus = datastore.ScheduledReelStatus
+	require.NoError(t, memory.NewReelRepo(store).CreateReel(ctx, reel))
+
+	var issued []string
+	service := &DeliveryService{
+		UnitOfWork: recordingUnitOfWork{UnitOfWork: memory.NewUnitOfWork(store), issued: &issued},
+		Links: &RecipientLinkService{
+			ReelRepo:      memory.NewReelRepo(store),
+			VideoRepo:     memory.NewVideoRepo(store),
+			ViewTokenRepo: memory.NewViewTokenRepo(store),
+			Signer:        token.NewSigner("secret"),
+			TTL:           time.Hour,
+		},
+		ClientURL: "http://localhost:3000",
+	}
+
+	// a stale version fails the delivery after the links were issued
+	stale := *reel
+	stale.Version--
+	require.ErrorIs(t, service.DeliverReel(ctx, &stale), datastore.ErrVersionConflict)
+	require.Len(t, issued, len(reel.Recipients))
+
+	for _, id := range issued {
+		_, err := memory.NewViewTokenRepo(store).GetViewTokenByID(ctx, id)
+		require.ErrorIs(t, err, datastore.ErrViewTokenNotFound)
+	}
+	require.Empty(t, notifications(t, store))
+
+	issued = nil
+	require.NoError(t, service.DeliverReel(ctx, reel))
+
+	for _, id := range issued {
+		_, err := memory.NewViewTokenRepo(store).GetViewTokenByID(ctx, id)
+		require.NoError(t, err)
+	}
+	require.Len(t, notifications(t, store), len(reel.Recipients))
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/storage"
+)
+
+// GalleryReelView is a gallery reel along with where to stream it from
+type GalleryReelView struct {
+	datastore.GalleryItem
+	StreamURL string `json:"stream_url"`
+}
+
+// GalleryService serves reels their senders shared in the public gallery to anyone
+type GalleryService struct {
+	GalleryRepo datastore.GalleryRepository
+	VideoRepo   datastore.VideoRepository
+	Storage     storage.Storage
+}
+
+// Get returns a gallery reel, or datastore.ErrReelNotFound when it isn't shared publicly
+func (s *GalleryService) Get(ctx context.Context, reelID string) (*GalleryReelView, error) {
+	item, err := s.GalleryRepo.GetGalleryItem(ctx, reelID)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := s.VideoRepo.GetVideoByID(ctx, item.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
+	streamURL, err := s.Storage.URL(ctx, video.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GalleryReelView{GalleryItem: *item, StreamURL: streamURL}, nil
+}
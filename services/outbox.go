@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+	"github.com/oklog/ulid/v2"
+)
+
+const EmailTopic = "email"
+
+var (
+	ErrUnknownOutboxTopic     = errors.New("unknown outbox topic")
+	ErrMalformedOutboxMessage = errors.New("malformed outbox message")
+)
+
+// NewEmailMessage wraps an email in an outbox message. The key identifies the
+// logical email, e.g. reel_delivery:<reel id>:<recipient id>
+func NewEmailMessage(idempotencyKey string, message mailer.Message) (datastore.OutboxMessage, error) {
+	message.IdempotencyKey = idempotencyKey
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return datastore.OutboxMessage{}, err
+	}
+
+	return datastore.OutboxMessage{
+		UID:            ulid.Make().String(),
+		IdempotencyKey: idempotencyKey,
+		Topic:          EmailTopic,
+		Payload:        payload,
+	}, nil
+}
+
+// OutboxDispatcher publishes outbox messages with at-least-once semantics:
+// a message is only marked as dispatched after it has been sent, so a crash
+// in between means it's sent again once its lease runs out
+type OutboxDispatcher struct {
+	OutboxRepo datastore.OutboxRepository
+	Mailer     mailer.Mailer
+	BatchSize  int
+	// Lease is how long a claimed message is hidden from other dispatchers
+	Lease time.Duration
+}
+
+func (d *OutboxDispatcher) DispatchPending(ctx context.Context) error {
+	messages, err := d.OutboxRepo.ClaimPendingMessages(ctx, d.BatchSize, d.Lease)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if err := d.dispatch(ctx, message); err != nil {
+			// retrying won't help a message no dispatcher can handle, or one that keeps failing
+			if errors.Is(err, ErrUnknownOutboxTopic) || errors.Is(err, ErrMalformedOutboxMessage) || message.Attempts >= datastore.MaxOutboxAttempts {
+				if err := d.OutboxRepo.MarkMessageDeadLettered(ctx, message.UID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			retryAt := time.Now().Add(retryBackoff(message.Attempts))
+
+			if err := d.OutboxRepo.MarkMessageFailed(ctx, message.UID, err.Error(), retryAt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.OutboxRepo.MarkMessageDispatched(ctx, message.UID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context, message datastore.OutboxMessage) error {
+	switch message.Topic {
+	case EmailTopic:
+		var email mailer.Message
+		if err := json.Unmarshal(message.Payload, &email); err != nil {
+			return fmt.Errorf("%w: %s", ErrMalformedOutboxMessage, err)
+		}
+
+		return d.Mailer.Send(ctx, email)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOutboxTopic, message.Topic)
+	}
+}
+
+// retryBackoff grows quadratically with the number of attempts and is capped at a day
+func retryBackoff(attempts int) time.Duration {
+	backoff := time.Duration(attempts*attempts) * time.Minute
+
+	if backoff > 24*time.Hour {
+		return 24 * time.Hour
+	}
+
+	return backoff
+}
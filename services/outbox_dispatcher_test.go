@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, message mailer.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.sent = append(f.sent, message)
+	return nil
+}
+
+func TestDispatchPending(t *testing.T) {
+	outboxRepo := memory.NewOutboxRepo(memory.NewStore())
+	fake := &fakeMailer{err: errors.New("smtp unavailable")}
+
+	dispatcher := &OutboxDispatcher{OutboxRepo: outboxRepo, Mailer: fake, BatchSize: 10, Lease: time.Minute}
+
+	message, err := NewEmailMessage("welcome:123", mailer.Message{To: "user@gmail.com", Subject: "Welcome"})
+	require.NoError(t, err)
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), message))
+
+	// a failed send is retried later rather than lost
+	require.NoError(t, dispatcher.DispatchPending(context.Background()))
+	require.Empty(t, fake.sent)
+
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	// bring the retry forward instead of waiting out the backoff
+	require.NoError(t, outboxRepo.MarkMessageFailed(context.Background(), message.UID, "smtp unavailable", time.Now().Add(-time.Second)))
+
+	fake.err = nil
+	require.NoError(t, dispatcher.DispatchPending(context.Background()))
+
+	require.Len(t, fake.sent, 1)
+	require.Equal(t, "welcome:123", fake.sent[0].IdempotencyKey)
+
+	// dispatched messages aren't sent again
+	require.NoError(t, dispatcher.DispatchPending(context.Background()))
+	require.Len(t, fake.sent, 1)
+}
+
+func TestDispatchDeadLettersUnknownTopics(t *testing.T) {
+	store := memory.NewStore()
+	outboxRepo := memory.NewOutboxRepo(store)
+	fake := &fakeMailer{}
+
+	dispatcher := &OutboxDispatcher{OutboxRepo: outboxRepo, Mailer: fake, BatchSize: 10, Lease: time.Minute}
+
+	unknown, err := NewEmailMessage("sms:123", mailer.Message{To: "user@gmail.com"})
+	require.NoError(t, err)
+	unknown.Topic = "sms"
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), unknown))
+
+	require.NoError(t, dispatcher.DispatchPending(context.Background()))
+	require.Empty(t, fake.sent)
+
+	// it's set aside for good rather than retried with a backoff
+	require.NoError(t, outboxRepo.MarkMessageFailed(context.Background(), unknown.UID, "retry", time.Now().Add(-time.Second)))
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+}
+
+// countingMailer fails every send and counts them
+type countingMailer struct {
+	attempts int
+}
+
+func (c *countingMailer) Send(ctx context.Context, message mailer.Message) error {
+	c.attempts++
+	return errors.New("550 mailbox unavailable")
+}
+
+func TestDispatchDeadLettersAfterMaxAttempts(t *testing.T) {
+	outboxRepo := memory.NewOutboxRepo(memory.NewStore())
+	counting := &countingMailer{}
+
+	dispatcher := &OutboxDispatcher{OutboxRepo: outboxRepo, Mailer: counting, BatchSize: 10, Lease: time.Minute}
+
+	message, err := NewEmailMessage("welcome:123", mailer.Message{To: "gone@gmail.com", Subject: "Welcome"})
+	require.NoError(t, err)
+	require.NoError(t, outboxRepo.EnqueueMessages(context.Background(), message))
+
+	for i := 0; i < datastore.MaxOutboxAttempts; i++ {
+		require.NoError(t, dispatcher.DispatchPending(context.Background()))
+
+		// bring the retry forward instead of waiting out the backoff
+		require.NoError(t, outboxRepo.MarkMessageFailed(context.Background(), message.UID, "retry", time.Now().Add(-time.Second)))
+	}
+	require.Equal(t, datastore.MaxOutboxAttempts, counting.attempts)
+
+	// after the last attempt it's set aside for good
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	require.NoError(t, dispatcher.DispatchPending(context.Background()))
+	require.Equal(t, datastore.MaxOutboxAttempts, counting.attempts)
+}
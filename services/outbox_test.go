@@ -0,0 +1,33 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailMessage(t *testing.T) {
+	email := mailer.Message{To: "recipient@gmail.com", Subject: "Hello", Body: "<p>Hi</p>"}
+
+	message, err := NewEmailMessage("reel_delivery:123:456", email)
+	require.NoError(t, err)
+
+	require.Equal(t, EmailTopic, message.Topic)
+	require.Equal(t, "reel_delivery:123:456", message.IdempotencyKey)
+	require.NotEmpty(t, message.UID)
+
+	var payload mailer.Message
+	require.NoError(t, json.Unmarshal(message.Payload, &payload))
+
+	email.IdempotencyKey = "reel_delivery:123:456"
+	require.Equal(t, email, payload)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	require.Equal(t, time.Minute, retryBackoff(1))
+	require.Equal(t, 9*time.Minute, retryBackoff(3))
+	require.Equal(t, 24*time.Hour, retryBackoff(100))
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/media"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"gopkg.in/guregu/null.v4"
+)
+
+type Packager interface {
+	Package(ctx context.Context, inputPath string, outputDir string) error
+}
+
+// VideoPackagingService packages uploaded videos for adaptive streaming
+type VideoPackagingService struct {
+	VideoRepo datastore.VideoRepository
+	Storage   storage.Storage
+	Packager  Packager
+	// RetryAfter is how long a video that failed to package waits before it's tried again
+	RetryAfter time.Duration
+}
+
+// PackagePendingVideos packages up to limit videos that don't have HLS renditions yet.
+// A video that fails to package is skipped so it doesn't block the rest of the batch, and
+// its failure is recorded so later batches try other videos first
+func (s *VideoPackagingService) PackagePendingVideos(ctx context.Context, limit int) error {
+	videos, err := s.VideoRepo.GetVideosPendingPackaging(ctx, limit, s.RetryAfter)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range videos {
+		if err := s.PackageVideo(ctx, &videos[i]); err != nil {
+			errs = append(errs, fmt.Errorf("packaging video %s: %w", videos[i].UID, err))
+
+			if err := s.VideoRepo.RecordPackagingFailure(ctx, videos[i].UID); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// PackageVideo renders the video's HLS renditions, stores every playlist and
+// segment under hls/<video id>/ and records the master playlist on the video
+func (s *VideoPackagingService) PackageVideo(ctx context.Context, video *datastore.Video) error {
+	workDir, err := os.MkdirTemp("", "memoreel-hls-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	inputPath := filepath.Join(workDir, "source")
+	if err := s.download(ctx, video.Key, inputPath); err != nil {
+		return err
+	}
+
+	outputDir := filepath.Join(workDir, "hls")
+	if err := s.Packager.Package(ctx, inputPath, outputDir); err != nil {
+		return err
+	}
+
+	keyPrefix := hlsKeyPrefix(video.UID)
+	if err := s.upload(ctx, outputDir, keyPrefix); err != nil {
+		return err
+	}
+
+	video.HLSMasterKey = null.StringFrom(path.Join(keyPrefix, media.MasterPlaylistName))
+
+	return s.VideoRepo.UpdateVideo(ctx, video)
+}
+
+// hlsKeyPrefix is where a video's HLS renditions are stored
+func hlsKeyPrefix(videoID string) string {
+	return path.Join("hls", videoID)
+}
+
+// deleteVideoObjects removes a video's source and any renditions packaged from it from storage
+func deleteVideoObjects(ctx context.Context, store storage.Storage, video datastore.Video) error {
+	if err := store.Delete(ctx, video.Key); err != nil {
+		return err
+	}
+
+	return store.DeletePrefix(ctx, hlsKeyPrefix(video.UID))
+}
+
+func (s *VideoPackagingService) download(ctx context.Context, key string, dst string) error {
+	r, err := s.Storage.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *VideoPackagingService) upload(ctx context.Context, dir string, keyPrefix string) error {
+	return filepath.WalkDir(dir, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return s.Storage.Put(ctx, path.Join(keyPrefix, filepath.ToSlash(rel)), f)
+	})
+}
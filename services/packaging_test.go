@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/media"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePackager writes a master playlist for every source except ones that read "broken"
+type fakePackager struct{}
+
+func (fakePackager) Package(ctx context.Context, inputPath string, outputDir string) error {
+	source, err := os.ReadFile(inputPath)
+	if err != nil {
+		return err
+	}
+
+	if string(source) == "broken" {
+		return errors.New("invalid data found when processing input")
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(outputDir, media.MasterPlaylistName), []byte("#EXTM3U\n"), 0o644)
+}
+
+func TestPackagingSkipsBrokenVideos(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	service := &VideoPackagingService{
+		VideoRepo:  memory.NewVideoRepo(store),
+		Storage:    storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()}),
+		Packager:   fakePackager{},
+		RetryAfter: time.Hour,
+	}
+
+	// the broken video sorts first, so it's what a batch of one picks before it fails
+	broken := datastoretest.GenerateVideo()
+	broken.UID = "0" + broken.UID[1:]
+	videos := []*datastore.Video{broken, datastoretest.GenerateVideo(), datastoretest.GenerateVideo()}
+	for i, video := range videos {
+		source := "video"
+		if i == 0 {
+			source = "broken"
+		}
+
+		require.NoError(t, service.Storage.Put(ctx, video.Key, strings.NewReader(source)))
+		require.NoError(t, service.VideoRepo.CreateVideo(ctx, video))
+	}
+
+	err := service.PackagePendingVideos(ctx, 1)
+	require.ErrorContains(t, err, broken.UID)
+
+	// the other videos still get their turn
+	require.NoError(t, service.PackagePendingVideos(ctx, 1))
+	require.NoError(t, service.PackagePendingVideos(ctx, 1))
+
+	for _, video := range videos[1:] {
+		packaged, err := service.VideoRepo.GetVideoByID(ctx, video.UID)
+		require.NoError(t, err)
+		require.True(t, packaged.HLSMasterKey.Valid)
+	}
+
+	// while the broken one waits out its back off
+	pending, err := service.VideoRepo.GetVideosPendingPackaging(ctx, 10, service.RetryAfter)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	found, err := service.VideoRepo.GetVideoByID(ctx, broken.UID)
+	require.NoError(t, err)
+	require.Equal(t, 1, found.PackagingAttempts)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/media"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/oklog/ulid/v2"
+)
+
+const recipientViewAudience = "recipient_view"
+
+var (
+	ErrInvalidViewLink  = errors.New("view link is invalid or has expired")
+	ErrPlaylistNotReady = errors.New("reel has not been packaged for streaming yet")
+)
+
+// RecipientReelView is everything a recipient is allowed to see about a reel.
+// It deliberately leaves out the sender and the other recipients. PlaylistURL
+// is empty until the video has been packaged for streaming. Clips are the approved
+// contributions to a group reel, played after the reel's own video
+type RecipientReelView struct {
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	StreamURL    string    `json:"stream_url"`
+	PlaylistURL  string    `json:"playlist_url,omitempty"`
+	Clips        []string  `json:"clips,omitempty"`
+	DeliveryDate time.Time `json:"delivery_date"`
+}
+
+type RecipientLinkService struct {
+	ReelRepo      datastore.ReelRepository
+	VideoRepo     datastore.VideoRepository
+	ViewTokenRepo datastore.ViewTokenRepository
+	// ContributionRepo is optional, without it group reels are shown without their clips
+	ContributionRepo datastore.ContributionRepository
+	Signer           *token.Signer
+	Storage          storage.Storage
+	TTL              time.Duration
+	APIURL           string
+}
+
+// IssueLink creates a view token for the recipient and returns its signed form
+func (s *RecipientLinkService) IssueLink(ctx context.Context, reel *datastore.Reel, recipient datastore.Recipient) (string, error) {
+	return s.issueLink(ctx, s.ViewTokenRepo, reel, recipient)
+}
+
+// issueLink saves the view token through repo, so it can be part of the caller's transaction
+func (s *RecipientLinkService) issueLink(ctx context.Context, repo datastore.ViewTokenRepository, reel *datastore.Reel, recipient datastore.Recipient) (string, error) {
+	viewToken := &datastore.ViewToken{
+		UID:         ulid.Make().String(),
+		ReelID:      reel.UID,
+		RecipientID: recipient.UID,
+		ExpiresAt:   time.Now().Add(s.TTL),
+	}
+
+	if err := repo.CreateViewToken(ctx, viewToken); err != nil {
+		return "", err
+	}
+
+	return s.Signer.Sign(token.Claims{
+		Subject:   viewToken.UID,
+		Audience:  recipientViewAudience,
+		ExpiresAt: viewToken.ExpiresAt.Unix(),
+	})
+}
+
+// Resolve verifies a signed link and returns the recipient's view of the reel
+func (s *RecipientLinkService) Resolve(ctx context.Context, signedToken string) (*RecipientReelView, error) {
+	reel, video, err := s.resolve(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	streamURL, err := s.Storage.URL(ctx, video.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &RecipientReelView{
+		Title:        reel.Title,
+		Description:  reel.Description,
+		StreamURL:    streamURL,
+		DeliveryDate: reel.DeliveryDate,
+	}
+
+	if s.ContributionRepo != nil {
+		view.Clips, err = approvedClips(ctx, s.ContributionRepo, s.VideoRepo, s.Storage, reel.UID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if video.HLSMasterKey.Valid {
+		view.PlaylistURL, err = url.JoinPath(s.APIURL, "v1", "view", signedToken, media.MasterPlaylistName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return view, nil
+}
+
+// MasterPlaylist returns the reel's HLS master playlist with every rendition
+// playlist rewritten to an absolute storage url
+func (s *RecipientLinkService) MasterPlaylist(ctx context.Context, signedToken string) ([]byte, error) {
+	_, video, err := s.resolve(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !video.HLSMasterKey.Valid {
+		return nil, ErrPlaylistNotReady
+	}
+
+	r, err := s.Storage.Get(ctx, video.HLSMasterKey.String)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	playlist, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	playlistDir := path.Dir(video.HLSMasterKey.String)
+
+	return media.RewritePlaylistURIs(playlist, func(uri string) (string, error) {
+		return s.Storage.URL(ctx, path.Join(playlistDir, uri))
+	})
+}
+
+func (s *RecipientLinkService) resolve(ctx context.Context, signedToken string) (*datastore.Reel, *datastore.Video, error) {
+	reel, _, err := s.recipient(ctx, signedToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	video, err := s.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reel, video, nil
+}
+
+// recipient verifies a signed link and returns the reel and the recipient it was issued to
+func (s *RecipientLinkService) recipient(ctx context.Context, signedToken string) (*datastore.Reel, *datastore.Recipient, error) {
+	claims, err := s.Signer.Verify(signedToken, recipientViewAudience)
+	if err != nil {
+		return nil, nil, ErrInvalidViewLink
+	}
+
+	viewToken, err := s.ViewTokenRepo.GetViewTokenByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, datastore.ErrViewTokenNotFound) {
+			return nil, nil, ErrInvalidViewLink
+		}
+		return nil, nil, err
+	}
+
+	reel, err := s.ReelRepo.GetReelByID(ctx, viewToken.ReelID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			return nil, nil, ErrInvalidViewLink
+		}
+		return nil, nil, err
+	}
+
+	// the recipient may have been removed after the link was issued
+	recipient := reel.FindRecipient(viewToken.RecipientID)
+	if recipient == nil {
+		return nil, nil, ErrInvalidViewLink
+	}
+
+	return reel, recipient, nil
+}
+
+// Revoke invalidates every link issued to the recipient for the reel
+func (s *RecipientLinkService) Revoke(ctx context.Context, reel *datastore.Reel, recipientID string) error {
+	if reel.FindRecipient(recipientID) == nil {
+		return datastore.ErrRecipientNotFound
+	}
+
+	return s.ViewTokenRepo.RevokeViewTokens(ctx, reel.UID, recipientID)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+)
+
+const reminderBatchSize = 100
+
+type ReminderService struct {
+	ReelRepo   datastore.ReelRepository
+	OutboxRepo datastore.OutboxRepository
+	ClientURL  string
+	// DaysBefore lists how many days ahead of delivery a reminder goes out
+	DaysBefore []int
+}
+
+// SendDueReminders emails the creator of every scheduled reel that has entered one of the reminder windows.
+//
+// Windows are processed from the closest to delivery outwards and recording a reminder also
+// records every wider window, so a reel created 5 days before delivery gets the 7 day
+// reminder only, not the 30 day one as well. Reminder emails go through the outbox keyed
+// by reel and window, so a crash between enqueueing and recording can't remind the creator twice.
+func (s *ReminderService) SendDueReminders(ctx context.Context) error {
+	daysBefore := append([]int(nil), s.DaysBefore...)
+	sort.Ints(daysBefore)
+
+	for i, days := range daysBefore {
+		reels, err := s.ReelRepo.GetReelsDueForReminder(ctx, days, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for j := range reels {
+			if err := s.enqueueReminder(ctx, &reels[j], days); err != nil {
+				return err
+			}
+
+			if err := s.ReelRepo.RecordReminders(ctx, reels[j].UID, daysBefore[i:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *ReminderService) enqueueReminder(ctx context.Context, reel *datastore.Reel, daysBefore int) error {
+	reelURL, err := url.JoinPath(s.ClientURL, "reels", reel.UID)
+	if err != nil {
+		return err
+	}
+
+	message, err := mailer.ReelReminderMessage(reel.Email, mailer.ReelReminderData{
+		Title:        reel.Title,
+		DeliveryDate: reel.DeliveryDate,
+		SentAt:       time.Now(),
+		ReelURL:      reelURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	outboxMessage, err := NewEmailMessage(fmt.Sprintf("reel_reminder:%s:%d", reel.UID, daysBefore), message)
+	if err != nil {
+		return err
+	}
+
+	return s.OutboxRepo.EnqueueMessages(ctx, outboxMessage)
+}
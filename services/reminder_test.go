@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestSendDueReminders(t *testing.T) {
+	store := memory.NewStore()
+	reelRepo := memory.NewReelRepo(store)
+	outboxRepo := memory.NewOutboxRepo(store)
+
+	service := &ReminderService{
+		ReelRepo:   reelRepo,
+		OutboxRepo: outboxRepo,
+		ClientURL:  "https://memoreel.com",
+		DaysBefore: []int{30, 7},
+	}
+
+	// delivered in 5 days so only the 7 day reminder applies
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(context.Background(), video))
+
+	reel := datastoretest.GenerateReel(video.UID, "")
+	reel.UserID = null.String{}
+	reel.DeliveryStatus = datastore.ScheduledReelStatus
+	reel.DeliveryDate = time.Now().Add(time.Hour * 24 * 5)
+	require.NoError(t, reelRepo.CreateReel(context.Background(), reel))
+
+	require.NoError(t, service.SendDueReminders(context.Background()))
+	require.NoError(t, service.SendDueReminders(context.Background()))
+
+	messages, err := outboxRepo.ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+
+	require.Len(t, messages, 1)
+	require.Equal(t, "reel_reminder:"+reel.UID+":7", messages[0].IdempotencyKey)
+	require.Contains(t, string(messages[0].Payload), "delivered in 5 days")
+}
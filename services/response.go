@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/mailer"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	maxNoteLength = 1000
+	// maxReactionRunes leaves room for emoji built from several code points, like families and flags
+	maxReactionRunes = 8
+	zeroWidthJoiner  = '\u200d'
+	// noteEmailWindow is how often the sender can be emailed about a recipient's notes
+	noteEmailWindow = time.Hour
+)
+
+var (
+	ErrInvalidReaction    = errors.New("a reaction must be a single emoji")
+	ErrInvalidNote        = fmt.Errorf("a note must have between 1 and %d characters", maxNoteLength)
+	ErrResponseNotAllowed = errors.New("only recipients of a delivered reel can respond to it")
+	ErrInvalidModeration  = errors.New("a response can only be approved or rejected")
+)
+
+// Moderator screens responses before they reach the sender. It can approve or reject a
+// response right away, or leave it pending until it's settled with ResponseService.Review
+type Moderator interface {
+	Moderate(ctx context.Context, response *datastore.ReelResponse) (datastore.ModerationStatus, error)
+}
+
+// ApproveAll is the Moderator used when responses aren't screened
+type ApproveAll struct{}
+
+func (ApproveAll) Moderate(ctx context.Context, response *datastore.ReelResponse) (datastore.ModerationStatus, error) {
+	return datastore.ApprovedModeration, nil
+}
+
+// Responder is the recipient of a delivered reel who is responding to it
+type Responder struct {
+	Reel      *datastore.Reel
+	Recipient datastore.Recipient
+}
+
+// ResponseView is a response as its sender sees it, VideoURL is only set for reply videos
+type ResponseView struct {
+	datastore.ReelResponse
+	VideoURL string `json:"video_url,omitempty"`
+}
+
+// ResponseService lets recipients react to, leave a note on or reply with a video to a
+// reel they received. Senders are emailed about responses once they're approved
+type ResponseService struct {
+	UnitOfWork   datastore.UnitOfWork
+	ResponseRepo datastore.ResponseRepository
+	ReelRepo     datastore.ReelRepository
+	VideoRepo    datastore.VideoRepository
+	Links        *RecipientLinkService
+	Storage      storage.Storage
+	Moderator    Moderator
+	// MaxVideoBytes caps the size of reply videos
+	MaxVideoBytes int64
+	ClientURL     string
+}
+
+// ResponderFromLink identifies a recipient by the signed link from their delivery email
+func (s *ResponseService) ResponderFromLink(ctx context.Context, signedToken string) (*Responder, error) {
+	reel, recipient, err := s.Links.recipient(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Responder{Reel: reel, Recipient: *recipient}, nil
+}
+
+// ResponderFromUser identifies a signed in recipient by their email, which must be verified
+func (s *ResponseService) ResponderFromUser(ctx context.Context, user *datastore.User, reelID string) (*Responder, error) {
+	if !user.EmailVerified {
+		return nil, ErrResponseNotAllowed
+	}
+
+	reel, err := s.ReelRepo.GetReelByID(ctx, reelID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			return nil, ErrResponseNotAllowed
+		}
+		return nil, err
+	}
+
+	if reel.DeliveryStatus != datastore.DeliveredReelStatus {
+		return nil, ErrResponseNotAllowed
+	}
+
+	for _, recipient := range reel.Recipients {
+		if strings.EqualFold(recipient.Email, user.Email) {
+			return &Responder{Reel: reel, Recipient: recipient}, nil
+		}
+	}
+
+	return nil, ErrResponseNotAllowed
+}
+
+func (s *ResponseService) React(ctx context.Context, responder *Responder, emoji string) (*datastore.ReelResponse, error) {
+	if !isEmoji(emoji) {
+		return nil, ErrInvalidReaction
+	}
+
+	response := newResponse(responder, datastore.ReactionResponse)
+	response.Body = emoji
+
+	return response, s.respond(ctx, s.ResponseRepo, responder, response)
+}
+
+func (s *ResponseService) LeaveNote(ctx context.Context, responder *Responder, note string) (*datastore.ReelResponse, error) {
+	note = strings.TrimSpace(note)
+	if note == "" || utf8.RuneCountInString(note) > maxNoteLength {
+		return nil, ErrInvalidNote
+	}
+
+	response := newResponse(responder, datastore.NoteResponse)
+	response.Body = note
+
+	return response, s.respond(ctx, s.ResponseRepo, responder, response)
+}
+
+// ReplyWithVideo uploads a reply video of the given file format, e.g. mp4, and sends it back
+// to the sender. It's packaged for streaming like any other video
+func (s *ResponseService) ReplyWithVideo(ctx context.Context, responder *Responder, r io.Reader, fileFormat string) (*datastore.ReelResponse, error) {
+	response := newResponse(responder, datastore.VideoResponse)
+
+	video, err := storeUpload(ctx, s.Storage, "replies", response.UID, r, fileFormat, s.MaxVideoBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	response.VideoID = null.StringFrom(video.UID)
+
+	err = s.UnitOfWork.RunInTx(ctx, func(repos datastore.Repositories) error {
+		if err := repos.Videos.CreateVideo(ctx, video); err != nil {
+			return err
+		}
+
+		return s.respond(ctx, repos.Responses, responder, response)
+	})
+	if err != nil {
+		// the reply wasn't saved, so nothing refers to its upload
+		return nil, errors.Join(err, s.Storage.Delete(ctx, video.Key))
+	}
+
+	return response, nil
+}
+
+// Review settles a response to the reel the moderator left pending, the sender is notified
+// if it's approved
+func (s *ResponseService) Review(ctx context.Context, reel *datastore.Reel, responseID string, status datastore.ModerationStatus) error {
+	if status != datastore.ApprovedModeration && status != datastore.RejectedModeration {
+		return ErrInvalidModeration
+	}
+
+	response, err := s.ResponseRepo.GetResponseByID(ctx, responseID)
+	if err != nil {
+		return err
+	}
+
+	if response.ReelID != reel.UID {
+		return datastore.ErrResponseNotFound
+	}
+
+	var messages []datastore.OutboxMessage
+	if status == datastore.ApprovedModeration {
+		recipient := reel.FindRecipient(response.RecipientID)
+		if recipient == nil {
+			return ErrResponseNotAllowed
+		}
+
+		message, err := s.notification(&Responder{Reel: reel, Recipient: *recipient}, response)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	return s.ResponseRepo.ModerateResponse(ctx, responseID, status, messages...)
+}
+
+// Responses lists the responses to a reel in the given moderation status for its sender, who
+// sees the approved ones and reviews the pending ones
+func (s *ResponseService) Responses(ctx context.Context, reel *datastore.Reel, status datastore.ModerationStatus) ([]ResponseView, error) {
+	responses, err := s.ResponseRepo.GetResponsesByReel(ctx, reel.UID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]ResponseView, len(responses))
+	for i, response := range responses {
+		views[i] = ResponseView{ReelResponse: response}
+		if !response.VideoID.Valid {
+			continue
+		}
+
+		video, err := s.VideoRepo.GetVideoByID(ctx, response.VideoID.String)
+		if err != nil {
+			return nil, err
+		}
+
+		if views[i].VideoURL, err = s.Storage.URL(ctx, video.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
+
+// respond moderates the response and saves it, along with the sender's notification when
+// it's approved straight away
+func (s *ResponseService) respond(ctx context.Context, repo datastore.ResponseRepository, responder *Responder, response *datastore.ReelResponse) error {
+	status, err := s.Moderator.Moderate(ctx, response)
+	if err != nil {
+		return err
+	}
+	response.ModerationStatus = status
+
+	var messages []datastore.OutboxMessage
+	if status == datastore.ApprovedModeration {
+		message, err := s.notification(responder, response)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	return repo.CreateResponse(ctx, response, messages...)
+}
+
+func (s *ResponseService) notification(responder *Responder, response *datastore.ReelResponse) (datastore.OutboxMessage, error) {
+	reelURL, err := url.JoinPath(s.ClientURL, "reels", responder.Reel.UID)
+	if err != nil {
+		return datastore.OutboxMessage{}, err
+	}
+
+	email, err := mailer.ReelResponseMessage(responder.Reel.Email, mailer.ReelResponseData{
+		Title:     responder.Reel.Title,
+		Recipient: responder.Recipient.Email,
+		Kind:      string(response.Kind),
+		Body:      response.Body,
+		ReelURL:   reelURL,
+	})
+	if err != nil {
+		return datastore.OutboxMessage{}, err
+	}
+
+	return NewEmailMessage(notificationKey(response), email)
+}
+
+// notificationKey is the idempotency key of the sender's email about a response. The outbox
+// drops emails under a key it has already seen, so the sender hears about each recipient's
+// first reaction only and at most one of their notes every noteEmailWindow. The rest are in
+// the reel's responses
+func notificationKey(response *datastore.ReelResponse) string {
+	switch response.Kind {
+	case datastore.ReactionResponse:
+		return fmt.Sprintf("reel_reaction:%s:%s", response.ReelID, response.RecipientID)
+	case datastore.NoteResponse:
+		window := time.Now().Truncate(noteEmailWindow).Unix()
+		return fmt.Sprintf("reel_note:%s:%s:%d", response.ReelID, response.RecipientID, window)
+	}
+
+	return fmt.Sprintf("reel_response:%s", response.UID)
+}
+
+func newResponse(responder *Responder, kind datastore.ResponseKind) *datastore.ReelResponse {
+	return &datastore.ReelResponse{
+		UID:         ulid.Make().String(),
+		ReelID:      responder.Reel.UID,
+		RecipientID: responder.Recipient.UID,
+		Kind:        kind,
+	}
+}
+
+// isEmoji loosely checks that s is a single emoji: a few symbols joined by zero width
+// joiners, variation selectors and skin tone modifiers, with no letters, digits or spaces
+func isEmoji(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > maxReactionRunes {
+		return false
+	}
+
+	symbols := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.So, r):
+			symbols++
+		case r == zeroWidthJoiner, unicode.In(r, unicode.Sk, unicode.Mn, unicode.Me):
+		default:
+			return false
+		}
+	}
+
+	return symbols > 0
+}
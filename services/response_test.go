@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+)
+
+// holdAll leaves every response pending for review
+type holdAll struct{}
+
+func (holdAll) Moderate(ctx context.Context, response *datastore.ReelResponse) (datastore.ModerationStatus, error) {
+	return datastore.PendingModeration, nil
+}
+
+// failModeration fails to moderate any response
+type failModeration struct{}
+
+func (failModeration) Moderate(ctx context.Context, response *datastore.ReelResponse) (datastore.ModerationStatus, error) {
+	return "", errors.New("moderation is down")
+}
+
+func newResponseService(t *testing.T) (*ResponseService, *memory.Store, *datastore.Reel) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	reel := datastoretest.SeedReel(t, repos, datastoretest.SeedUser(t, repos).UID, datastore.DeliveredReelStatus)
+
+	return &ResponseService{
+		UnitOfWork:   memory.NewUnitOfWork(store),
+		ResponseRepo: repos.Responses,
+		ReelRepo:     repos.Reels,
+		VideoRepo:    repos.Videos,
+		Links: &RecipientLinkService{
+			ReelRepo:      repos.Reels,
+			VideoRepo:     repos.Videos,
+			ViewTokenRepo: repos.ViewTokens,
+			Signer:        token.NewSigner("secret"),
+			TTL:           time.Hour,
+		},
+		Storage:       storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir(), BaseURL: "http://localhost/media"}),
+		Moderator:     ApproveAll{},
+		MaxVideoBytes: 1024,
+		ClientURL:     "http://localhost:3000",
+	}, store, reel
+}
+
+// notifications returns the emails waiting to be sent
+func notifications(t *testing.T, store *memory.Store) []datastore.OutboxMessage {
+	messages, err := memory.NewOutboxRepo(store).ClaimPendingMessages(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	return messages
+}
+
+func TestRespondFromAccount(t *testing.T) {
+	service, store, reel := newResponseService(t)
+	ctx := context.Background()
+
+	user := datastoretest.GenerateUser()
+	user.Email = strings.ToUpper(reel.Recipients[0].Email)
+
+	_, err := service.ResponderFromUser(ctx, user, reel.UID)
+	require.ErrorIs(t, err, ErrResponseNotAllowed)
+
+	user.EmailVerified = true
+	responder, err := service.ResponderFromUser(ctx, user, reel.UID)
+	require.NoError(t, err)
+	require.Equal(t, reel.Recipients[0].UID, responder.Recipient.UID)
+
+	for _, emoji := range []string{"", "a", "🎉 🎉", "🎉🎉🎉🎉🎉🎉🎉🎉🎉"} {
+		_, err = service.React(ctx, responder, emoji)
+		require.ErrorIs(t, err, ErrInvalidReaction, emoji)
+	}
+
+	for _, emoji := range []string{"🎉", "👍🏽", "❤️", "👩‍👩‍👧"} {
+		_, err = service.React(ctx, responder, emoji)
+		require.NoError(t, err, emoji)
+	}
+
+	_, err = service.LeaveNote(ctx, responder, "   ")
+	require.ErrorIs(t, err, ErrInvalidNote)
+
+	note, err := service.LeaveNote(ctx, responder, " Thank you! ")
+	require.NoError(t, err)
+	require.Equal(t, "Thank you!", note.Body)
+	require.Equal(t, datastore.ApprovedModeration, note.ModerationStatus)
+
+	_, err = service.LeaveNote(ctx, responder, "Really, thank you!")
+	require.NoError(t, err)
+
+	// the sender hears about the recipient's first reaction and first note, not every one
+	messages := notifications(t, store)
+	require.Len(t, messages, 2)
+	require.Contains(t, string(messages[1].Payload), reel.Email)
+
+	// a reaction from another recipient is news
+	_, err = service.React(ctx, &Responder{Reel: reel, Recipient: reel.Recipients[1]}, "🎉")
+	require.NoError(t, err)
+	require.Len(t, notifications(t, store), 1)
+
+	responses, err := service.Responses(ctx, reel, datastore.ApprovedModeration)
+	require.NoError(t, err)
+	require.Len(t, responses, 7)
+
+	// someone who isn't a recipient can't respond
+	user.Email = "stranger@example.com"
+	_, err = service.ResponderFromUser(ctx, user, reel.UID)
+	require.ErrorIs(t, err, ErrResponseNotAllowed)
+}
+
+func TestRespondFromLink(t *testing.T) {
+	service, _, reel := newResponseService(t)
+	ctx := context.Background()
+
+	signed, err := service.Links.IssueLink(ctx, reel, reel.Recipients[1])
+	require.NoError(t, err)
+
+	responder, err := service.ResponderFromLink(ctx, signed)
+	require.NoError(t, err)
+	require.Equal(t, reel.Recipients[1].UID, responder.Recipient.UID)
+
+	_, err = service.ResponderFromLink(ctx, "invalid")
+	require.ErrorIs(t, err, ErrInvalidViewLink)
+
+	_, err = service.ReplyWithVideo(ctx, responder, strings.NewReader("video"), "exe")
+	require.ErrorIs(t, err, ErrUnsupportedVideo)
+
+	_, err = service.ReplyWithVideo(ctx, responder, bytes.NewReader(make([]byte, 2048)), "mp4")
+	require.ErrorIs(t, err, ErrVideoTooLarge)
+
+	reply, err := service.ReplyWithVideo(ctx, responder, bytes.NewReader(make([]byte, 512)), "MP4")
+	require.NoError(t, err)
+	require.True(t, reply.VideoID.Valid)
+
+	responses, err := service.Responses(ctx, reel, datastore.ApprovedModeration)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	require.Contains(t, responses[0].VideoURL, "replies/"+reply.UID+".mp4")
+}
+
+func TestReplyWithVideoCleanup(t *testing.T) {
+	service, _, reel := newResponseService(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	service.Storage = storage.NewLocalStorage(config.StorageConfiguration{Directory: dir})
+	responder := &Responder{Reel: reel, Recipient: reel.Recipients[0]}
+
+	_, err := service.ReplyWithVideo(ctx, responder, bytes.NewReader(make([]byte, 2048)), "mp4")
+	require.ErrorIs(t, err, ErrVideoTooLarge)
+
+	// the upload is removed when the reply can't be saved
+	service.Moderator = failModeration{}
+	_, err = service.ReplyWithVideo(ctx, responder, bytes.NewReader(make([]byte, 512)), "mp4")
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "replies"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestReviewResponse(t *testing.T) {
+	service, store, reel := newResponseService(t)
+	service.Moderator = holdAll{}
+	ctx := context.Background()
+
+	responder := &Responder{Reel: reel, Recipient: reel.Recipients[0]}
+
+	kept, err := service.LeaveNote(ctx, responder, "Thank you!")
+	require.NoError(t, err)
+	require.Equal(t, datastore.PendingModeration, kept.ModerationStatus)
+
+	dropped, err := service.LeaveNote(ctx, responder, "spam")
+	require.NoError(t, err)
+
+	// held responses don't reach the sender until they're approved
+	require.Empty(t, notifications(t, store))
+
+	pending, err := service.Responses(ctx, reel, datastore.PendingModeration)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	// responses can only be reviewed through the reel they were sent to
+	other := datastoretest.GenerateReel(reel.VideoID, reel.UserID.String)
+	require.ErrorIs(t, service.Review(ctx, other, kept.UID, datastore.ApprovedModeration), datastore.ErrResponseNotFound)
+
+	require.ErrorIs(t, service.Review(ctx, reel, kept.UID, datastore.PendingModeration), ErrInvalidModeration)
+	require.NoError(t, service.Review(ctx, reel, kept.UID, datastore.ApprovedModeration))
+	require.NoError(t, service.Review(ctx, reel, dropped.UID, datastore.RejectedModeration))
+	require.ErrorIs(t, service.Review(ctx, reel, dropped.UID, datastore.ApprovedModeration), datastore.ErrResponseNotFound)
+
+	require.Len(t, notifications(t, store), 1)
+
+	responses, err := service.Responses(ctx, reel, datastore.ApprovedModeration)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	require.Equal(t, kept.UID, responses[0].UID)
+}
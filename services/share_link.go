@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	shareLinkAudience   = "share_link"
+	shareStreamAudience = "share_stream"
+)
+
+const (
+	// maxSharePasswordAttempts wrong passwords in a row lock a share link for sharePasswordLockout
+	maxSharePasswordAttempts = 5
+	sharePasswordLockout     = 15 * time.Minute
+)
+
+var (
+	ErrInvalidShareLink    = errors.New("share link is invalid, has expired or has been used up")
+	ErrSharePasswordNeeded = errors.New("share link needs a password")
+	ErrWrongSharePassword  = errors.New("share link password is incorrect")
+	ErrShareLinkLocked     = errors.New("share link is locked after too many wrong passwords, try again later")
+	ErrReelNotDelivered    = errors.New("only delivered reels can be shared")
+	ErrInvalidShareOptions = errors.New("share link expiry must be in the future and max views at least 1")
+)
+
+// ShareLinkOptions limit who can use a share link and for how long, all are optional
+type ShareLinkOptions struct {
+	ExpiresAt null.Time
+	MaxViews  null.Int
+	Password  string
+}
+
+// SharedReelView is what someone opening a share link sees, like RecipientReelView it
+// leaves out the sender and the recipients
+type SharedReelView struct {
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	StreamURL    string    `json:"stream_url"`
+	DeliveryDate time.Time `json:"delivery_date"`
+}
+
+// ShareLinkService lets senders share a delivered reel with people outside its recipients
+type ShareLinkService struct {
+	ShareLinkRepo datastore.ShareLinkRepository
+	ReelRepo      datastore.ReelRepository
+	VideoRepo     datastore.VideoRepository
+	Signer        *token.Signer
+	Storage       storage.Storage
+	APIURL        string
+	// StreamTTL is how long the stream url handed out when a link is opened keeps working
+	StreamTTL time.Duration
+}
+
+// Create adds a share link to the reel and returns it along with its url
+func (s *ShareLinkService) Create(ctx context.Context, reel *datastore.Reel, opts ShareLinkOptions) (*datastore.ShareLink, string, error) {
+	if reel.DeliveryStatus != datastore.DeliveredReelStatus {
+		return nil, "", ErrReelNotDelivered
+	}
+
+	if (opts.ExpiresAt.Valid && !opts.ExpiresAt.Time.After(time.Now())) || (opts.MaxViews.Valid && opts.MaxViews.Int64 < 1) {
+		return nil, "", ErrInvalidShareOptions
+	}
+
+	link := &datastore.ShareLink{
+		UID:       ulid.Make().String(),
+		ReelID:    reel.UID,
+		ExpiresAt: opts.ExpiresAt,
+		MaxViews:  opts.MaxViews,
+	}
+
+	if opts.Password != "" {
+		hash, err := token.HashPassword(opts.Password)
+		if err != nil {
+			return nil, "", err
+		}
+		link.PasswordHash = null.StringFrom(hash)
+	}
+
+	if err := s.ShareLinkRepo.CreateShareLink(ctx, link); err != nil {
+		return nil, "", err
+	}
+
+	shareURL, err := s.URL(link)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return link, shareURL, nil
+}
+
+// URL returns where the link can be opened. The link id is signed so ids can't be guessed
+func (s *ShareLinkService) URL(link *datastore.ShareLink) (string, error) {
+	signed, err := s.Signer.Sign(token.Claims{Subject: link.UID, Audience: shareLinkAudience})
+	if err != nil {
+		return "", err
+	}
+
+	return url.JoinPath(s.APIURL, "v1", "share", signed)
+}
+
+// Resolve opens a share link, counting it as a view. The password is only checked when the
+// link has one, and a wrong password doesn't use up a view. Too many wrong passwords lock
+// the link for a while, failing with ErrShareLinkLocked
+func (s *ShareLinkService) Resolve(ctx context.Context, signedToken string, password string) (*SharedReelView, error) {
+	claims, err := s.Signer.Verify(signedToken, shareLinkAudience)
+	if err != nil {
+		return nil, ErrInvalidShareLink
+	}
+
+	link, err := s.ShareLinkRepo.GetShareLinkByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, datastore.ErrShareLinkNotFound) {
+			return nil, ErrInvalidShareLink
+		}
+		return nil, err
+	}
+
+	if !link.Active(time.Now()) {
+		return nil, ErrInvalidShareLink
+	}
+
+	if link.PasswordHash.Valid {
+		if link.Locked(time.Now()) {
+			return nil, ErrShareLinkLocked
+		}
+		if password == "" {
+			return nil, ErrSharePasswordNeeded
+		}
+		if !token.CheckPassword(link.PasswordHash.String, password) {
+			if _, err := s.ShareLinkRepo.RecordSharePasswordFailure(ctx, link.UID, maxSharePasswordAttempts, sharePasswordLockout); err != nil {
+				return nil, err
+			}
+			return nil, ErrWrongSharePassword
+		}
+	}
+
+	// a trashed reel can't be viewed, checked before the view is counted
+	reel, err := s.ReelRepo.GetReelByID(ctx, link.ReelID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			return nil, ErrInvalidShareLink
+		}
+		return nil, err
+	}
+
+	if _, err := s.ShareLinkRepo.RecordShareLinkView(ctx, link.UID); err != nil {
+		if errors.Is(err, datastore.ErrShareLinkNotFound) {
+			return nil, ErrInvalidShareLink
+		}
+		return nil, err
+	}
+
+	video, err := s.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
+	streamURL, err := s.streamURL(link, video)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SharedReelView{
+		Title:        reel.Title,
+		Description:  reel.Description,
+		StreamURL:    streamURL,
+		DeliveryDate: reel.DeliveryDate,
+	}, nil
+}
+
+// sourceName is what a shared reel's uploaded video is streamed as
+func sourceName(video *datastore.Video) string {
+	return "source." + video.FileFormat
+}
+
+// streamURL signs a short lived url the reel's video is streamed from through the api,
+// rather than handing out the stored object's own url which would outlive the link
+func (s *ShareLinkService) streamURL(link *datastore.ShareLink, video *datastore.Video) (string, error) {
+	signed, err := s.Signer.Sign(token.Claims{
+		Subject:   link.UID,
+		Audience:  shareStreamAudience,
+		ExpiresAt: time.Now().Add(s.StreamTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	name := sourceName(video)
+	if video.HLSMasterKey.Valid {
+		name = path.Base(video.HLSMasterKey.String)
+	}
+
+	return url.JoinPath(s.APIURL, "v1", "share", "stream", signed, name)
+}
+
+// Stream opens a file of a shared reel's video using a stream url from Resolve. name is the
+// uploaded video or a file of its HLS renditions, which playlists reference relative to each
+// other. The link is checked again so streaming stops once it's revoked or expires, but not
+// once its views are used up since the stream belongs to a view that was already counted
+func (s *ShareLinkService) Stream(ctx context.Context, streamToken string, name string) (io.ReadCloser, error) {
+	claims, err := s.Signer.Verify(streamToken, shareStreamAudience)
+	if err != nil {
+		return nil, ErrInvalidShareLink
+	}
+
+	link, err := s.ShareLinkRepo.GetShareLinkByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, datastore.ErrShareLinkNotFound) {
+			return nil, ErrInvalidShareLink
+		}
+		return nil, err
+	}
+
+	if link.RevokedAt.Valid || (link.ExpiresAt.Valid && !time.Now().Before(link.ExpiresAt.Time)) {
+		return nil, ErrInvalidShareLink
+	}
+
+	reel, err := s.ReelRepo.GetReelByID(ctx, link.ReelID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrReelNotFound) {
+			return nil, ErrInvalidShareLink
+		}
+		return nil, err
+	}
+
+	video, err := s.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := video.Key
+	if name != sourceName(video) {
+		// only files inside the video's own renditions can be reached
+		if !video.HLSMasterKey.Valid || !filepath.IsLocal(name) {
+			return nil, storage.ErrObjectNotFound
+		}
+		key = path.Join(path.Dir(video.HLSMasterKey.String), name)
+	}
+
+	return s.Storage.Get(ctx, key)
+}
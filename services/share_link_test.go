@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/ayo-awe/memoreel-be/token"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func newShareLinkService(t *testing.T) (*ShareLinkService, *datastore.Reel) {
+	repos := memory.NewRepositories(memory.NewStore())
+	reel := datastoretest.SeedReel(t, repos, datastoretest.SeedUser(t, repos).UID, datastore.DeliveredReelStatus)
+
+	return &ShareLinkService{
+		ShareLinkRepo: repos.Shares,
+		ReelRepo:      repos.Reels,
+		VideoRepo:     repos.Videos,
+		Signer:        token.NewSigner("secret"),
+		Storage:       storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir(), BaseURL: "http://localhost/media"}),
+		APIURL:        "http://localhost:8080",
+		StreamTTL:     time.Hour,
+	}, reel
+}
+
+// shareToken pulls the signed token out of a share url
+func shareToken(t *testing.T, shareURL string) string {
+	u, err := url.Parse(shareURL)
+	require.NoError(t, err)
+	return path.Base(u.Path)
+}
+
+func TestShareLink(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	link, shareURL, err := service.Create(ctx, reel, ShareLinkOptions{MaxViews: null.IntFrom(2), Password: "open sesame"})
+	require.NoError(t, err)
+	require.Contains(t, shareURL, "http://localhost:8080/v1/share/")
+	require.NotEqual(t, "open sesame", link.PasswordHash.String)
+
+	signed := shareToken(t, shareURL)
+
+	_, err = service.Resolve(ctx, signed, "")
+	require.ErrorIs(t, err, ErrSharePasswordNeeded)
+
+	// wrong passwords don't use up views
+	_, err = service.Resolve(ctx, signed, "wrong")
+	require.ErrorIs(t, err, ErrWrongSharePassword)
+
+	for i := 0; i < 2; i++ {
+		view, err := service.Resolve(ctx, signed, "open sesame")
+		require.NoError(t, err)
+		require.Equal(t, reel.Title, view.Title)
+		require.NotEmpty(t, view.StreamURL)
+	}
+
+	_, err = service.Resolve(ctx, signed, "open sesame")
+	require.ErrorIs(t, err, ErrInvalidShareLink)
+
+	_, err = service.Resolve(ctx, "tampered."+signed, "open sesame")
+	require.ErrorIs(t, err, ErrInvalidShareLink)
+}
+
+func TestShareLinkRevoked(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	link, shareURL, err := service.Create(ctx, reel, ShareLinkOptions{})
+	require.NoError(t, err)
+
+	_, err = service.Resolve(ctx, shareToken(t, shareURL), "")
+	require.NoError(t, err)
+
+	require.NoError(t, service.ShareLinkRepo.RevokeShareLink(ctx, reel.UID, link.UID))
+
+	_, err = service.Resolve(ctx, shareToken(t, shareURL), "")
+	require.ErrorIs(t, err, ErrInvalidShareLink)
+}
+
+// stream opens the file a stream url from Resolve points at
+func stream(t *testing.T, service *ShareLinkService, streamURL string, name string) (string, error) {
+	u, err := url.Parse(streamURL)
+	require.NoError(t, err)
+
+	rc, err := service.Stream(context.Background(), path.Base(path.Dir(u.Path)), name)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(content), nil
+}
+
+func TestShareLinkStream(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	video, err := service.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	require.NoError(t, err)
+	require.NoError(t, service.Storage.Put(ctx, video.Key, strings.NewReader("source")))
+
+	link, shareURL, err := service.Create(ctx, reel, ShareLinkOptions{})
+	require.NoError(t, err)
+
+	view, err := service.Resolve(ctx, shareToken(t, shareURL), "")
+	require.NoError(t, err)
+	require.Contains(t, view.StreamURL, "http://localhost:8080/v1/share/stream/")
+	require.NotContains(t, view.StreamURL, "http://localhost/media")
+
+	content, err := stream(t, service, view.StreamURL, path.Base(view.StreamURL))
+	require.NoError(t, err)
+	require.Equal(t, "source", content)
+
+	// stream urls stop working once they expire
+	service.StreamTTL = -time.Minute
+	expired, err := service.Resolve(ctx, shareToken(t, shareURL), "")
+	require.NoError(t, err)
+	_, err = stream(t, service, expired.StreamURL, path.Base(expired.StreamURL))
+	require.ErrorIs(t, err, ErrInvalidShareLink)
+
+	// and as soon as the link is revoked
+	require.NoError(t, service.ShareLinkRepo.RevokeShareLink(ctx, reel.UID, link.UID))
+	_, err = stream(t, service, view.StreamURL, path.Base(view.StreamURL))
+	require.ErrorIs(t, err, ErrInvalidShareLink)
+}
+
+func TestShareLinkStreamHLS(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	video, err := service.VideoRepo.GetVideoByID(ctx, reel.VideoID)
+	require.NoError(t, err)
+	video.HLSMasterKey = null.StringFrom(hlsKeyPrefix(video.UID) + "/master.m3u8")
+	require.NoError(t, service.VideoRepo.UpdateVideo(ctx, video))
+
+	require.NoError(t, service.Storage.Put(ctx, video.HLSMasterKey.String, strings.NewReader("master")))
+	require.NoError(t, service.Storage.Put(ctx, hlsKeyPrefix(video.UID)+"/720p/playlist.m3u8", strings.NewReader("720p")))
+	require.NoError(t, service.Storage.Put(ctx, "secret", strings.NewReader("secret")))
+
+	_, shareURL, err := service.Create(ctx, reel, ShareLinkOptions{})
+	require.NoError(t, err)
+
+	view, err := service.Resolve(ctx, shareToken(t, shareURL), "")
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(view.StreamURL, "/master.m3u8"))
+
+	content, err := stream(t, service, view.StreamURL, "master.m3u8")
+	require.NoError(t, err)
+	require.Equal(t, "master", content)
+
+	content, err = stream(t, service, view.StreamURL, "720p/playlist.m3u8")
+	require.NoError(t, err)
+	require.Equal(t, "720p", content)
+
+	// nothing outside the video's renditions can be reached
+	_, err = stream(t, service, view.StreamURL, "../../secret")
+	require.ErrorIs(t, err, storage.ErrObjectNotFound)
+}
+
+func TestShareLinkLockout(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	link, shareURL, err := service.Create(ctx, reel, ShareLinkOptions{Password: "open sesame"})
+	require.NoError(t, err)
+
+	signed := shareToken(t, shareURL)
+	for i := 0; i < maxSharePasswordAttempts; i++ {
+		_, err = service.Resolve(ctx, signed, "wrong")
+		require.ErrorIs(t, err, ErrWrongSharePassword)
+	}
+
+	// even the right password is turned away until the lockout is over
+	_, err = service.Resolve(ctx, signed, "open sesame")
+	require.ErrorIs(t, err, ErrShareLinkLocked)
+
+	locked, err := service.ShareLinkRepo.GetShareLinkByID(ctx, link.UID)
+	require.NoError(t, err)
+	require.Equal(t, 0, locked.Views)
+	require.WithinDuration(t, time.Now().Add(sharePasswordLockout), locked.LockedUntil.Time, time.Minute)
+}
+
+func TestShareLinkOptions(t *testing.T) {
+	service, reel := newShareLinkService(t)
+	ctx := context.Background()
+
+	_, _, err := service.Create(ctx, reel, ShareLinkOptions{ExpiresAt: null.TimeFrom(time.Now().Add(-time.Hour))})
+	require.ErrorIs(t, err, ErrInvalidShareOptions)
+
+	_, _, err = service.Create(ctx, reel, ShareLinkOptions{MaxViews: null.IntFrom(0)})
+	require.ErrorIs(t, err, ErrInvalidShareOptions)
+
+	reel.DeliveryStatus = datastore.ScheduledReelStatus
+	_, _, err = service.Create(ctx, reel, ShareLinkOptions{})
+	require.ErrorIs(t, err, ErrReelNotDelivered)
+}
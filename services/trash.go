@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/storage"
+)
+
+const purgeBatchSize = 100
+
+type TrashService struct {
+	ReelRepo datastore.ReelRepository
+	Storage  storage.Storage
+	// Retention is how long a deleted reel stays in the trash before it's purged
+	Retention time.Duration
+}
+
+// deletedAfter is how recently a reel must have been deleted to still be in the trash
+func (s *TrashService) deletedAfter() time.Time {
+	return time.Now().Add(-s.Retention)
+}
+
+// Trash pages through the user's deleted reels that can still be restored
+func (s *TrashService) Trash(ctx context.Context, userID string, pageable datastore.Pageable) ([]datastore.Reel, datastore.PaginationData, error) {
+	return s.ReelRepo.GetDeletedReelsPaged(ctx, userID, s.deletedAfter(), pageable)
+}
+
+// Restore brings a reel back from the trash. Reels past the retention period can't be
+// restored even before they're purged, they fail with ErrReelNotRestored
+func (s *TrashService) Restore(ctx context.Context, reel *datastore.Reel) error {
+	return s.ReelRepo.RestoreReel(ctx, reel, s.deletedAfter())
+}
+
+// PurgeExpired permanently removes reels that have been in the trash longer than the
+// retention period, a batch at a time, and deletes their videos from storage. It returns
+// how many reels were removed
+func (s *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	deletedBefore := s.deletedAfter()
+
+	var total int
+	var errs []error
+	for {
+		purged, videos, err := s.ReelRepo.PurgeDeletedReels(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			return total, errors.Join(append(errs, err)...)
+		}
+
+		total += purged
+
+		// the rows are gone either way, a video that can't be deleted is only reported
+		for _, video := range videos {
+			if err := deleteVideoObjects(ctx, s.Storage, video); err != nil {
+				errs = append(errs, fmt.Errorf("deleting video %s: %w", video.UID, err))
+			}
+		}
+
+		if purged < purgeBatchSize {
+			return total, errors.Join(errs...)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/ayo-awe/memoreel-be/database/memory"
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/datastore/datastoretest"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestPurgeExpiredDeletesVideos(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	service := &TrashService{
+		ReelRepo:  memory.NewReelRepo(store),
+		Storage:   storage.NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()}),
+		Retention: time.Hour,
+	}
+
+	video := datastoretest.GenerateVideo()
+	require.NoError(t, memory.NewVideoRepo(store).CreateVideo(ctx, video))
+
+	keys := []string{video.Key, hlsKeyPrefix(video.UID) + "/master.m3u8", hlsKeyPrefix(video.UID) + "/720p/segment0.ts"}
+	for _, key := range keys {
+		require.NoError(t, service.Storage.Put(ctx, key, strings.NewReader("data")))
+	}
+
+	reel := datastoretest.GenerateReel(video.UID, "")
+	reel.UserID = null.String{}
+	require.NoError(t, service.ReelRepo.CreateReel(ctx, reel))
+	require.NoError(t, service.ReelRepo.DeleteReel(ctx, reel.UID))
+
+	// still within the retention period
+	purged, err := service.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, purged)
+
+	deleted, err := service.ReelRepo.GetDeletedReelByID(ctx, reel.UID)
+	require.NoError(t, err)
+
+	service.Retention = 0
+	require.ErrorIs(t, service.Restore(ctx, deleted), datastore.ErrReelNotRestored)
+
+	purged, err = service.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	for _, key := range keys {
+		_, err := service.Storage.Get(ctx, key)
+		require.ErrorIs(t, err, storage.ErrObjectNotFound, key)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/datastore"
+	"github.com/ayo-awe/memoreel-be/storage"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrVideoTooLarge    = errors.New("video is too large")
+	ErrUnsupportedVideo = errors.New("video must be an mp4, mov or webm file")
+)
+
+// uploadFormats are the file formats people other than the reel's creator can upload videos in
+var uploadFormats = map[string]bool{"mp4": true, "mov": true, "webm": true}
+
+// storeUpload writes an uploaded video of the given file format to storage under dir/name and
+// returns the video to be saved. Uploads over maxBytes fail with ErrVideoTooLarge, nothing is
+// stored for an upload that fails
+func storeUpload(ctx context.Context, store storage.Storage, dir string, name string, r io.Reader, fileFormat string, maxBytes int64) (*datastore.Video, error) {
+	fileFormat = strings.ToLower(fileFormat)
+	if !uploadFormats[fileFormat] {
+		return nil, ErrUnsupportedVideo
+	}
+
+	video := &datastore.Video{
+		UID:        ulid.Make().String(),
+		Key:        fmt.Sprintf("%s/%s.%s", dir, name, fileFormat),
+		FileFormat: fileFormat,
+	}
+
+	limited := &limitedReader{r: r, remaining: maxBytes}
+	if err := store.Put(ctx, video.Key, limited); err != nil {
+		if errors.Is(err, ErrVideoTooLarge) {
+			return nil, ErrVideoTooLarge
+		}
+		return nil, err
+	}
+
+	video.SizeMB = float32(limited.read) / (1 << 20)
+
+	return video, nil
+}
+
+// limitedReader fails with ErrVideoTooLarge once more than remaining bytes are read
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	read      int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.remaining {
+		return n, ErrVideoTooLarge
+	}
+
+	return n, err
+}
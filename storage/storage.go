@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ayo-awe/memoreel-be/config"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+)
+
+type Storage interface {
+	// Put stores everything read from r at key. Nothing is left at key when it fails part way
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// URL returns a location the object stored at key can be streamed from
+	URL(ctx context.Context, key string) (string, error)
+	// Delete removes the object stored at key, it's not an error if there's none
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object whose key is under the prefix directory
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// LocalStorage keeps objects on the local filesystem. It expects
+// the storage directory to be served at BaseURL
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(cfg config.StorageConfiguration) *LocalStorage {
+	return &LocalStorage{dir: cfg.Directory, baseURL: strings.TrimSuffix(cfg.BaseURL, "/")}
+}
+
+func (l *LocalStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// the object is written next to its key and renamed into place once it's complete, so
+	// readers never see a partial file and a failed write leaves nothing behind
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(f, r); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
+
+// writeFile copies r to f and closes it
+func writeFile(f *os.File, r io.Reader) error {
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (l *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (l *LocalStorage) URL(ctx context.Context, key string) (string, error) {
+	return url.JoinPath(l.baseURL, key)
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *LocalStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	// the storage directory itself is never a prefix
+	if filepath.Clean(prefix) == "." {
+		return ErrInvalidKey
+	}
+
+	path, err := l.path(prefix)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
+
+// path maps a key to a file inside the storage directory, rejecting keys that escape it
+func (l *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(l.dir, key), nil
+}
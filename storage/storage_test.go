@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ayo-awe/memoreel-be/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoragePutAndGet(t *testing.T) {
+	store := NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir(), BaseURL: "http://localhost/media/"})
+
+	require.NoError(t, store.Put(context.Background(), "hls/123/master.m3u8", strings.NewReader("#EXTM3U")))
+
+	r, err := store.Get(context.Background(), "hls/123/master.m3u8")
+	require.NoError(t, err)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "#EXTM3U", string(b))
+
+	_, err = store.Get(context.Background(), "hls/123/missing.m3u8")
+	require.ErrorIs(t, err, ErrObjectNotFound)
+
+	u, err := store.URL(context.Background(), "hls/123/master.m3u8")
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost/media/hls/123/master.m3u8", u)
+}
+
+// failingReader returns its data and then fails, like an upload cut off part way
+type failingReader struct {
+	data string
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.data == "" {
+		return 0, errors.New("connection reset")
+	}
+
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestLocalStoragePutFailure(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStorage(config.StorageConfiguration{Directory: dir})
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "videos/123.mp4", strings.NewReader("complete")))
+	require.Error(t, store.Put(ctx, "videos/123.mp4", &failingReader{data: "partial"}))
+	require.Error(t, store.Put(ctx, "videos/456.mp4", &failingReader{data: "partial"}))
+
+	// the failed writes leave the earlier object alone and nothing else behind
+	r, err := store.Get(ctx, "videos/123.mp4")
+	require.NoError(t, err)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "complete", string(b))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "videos"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	store := NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()})
+
+	require.ErrorIs(t, store.Put(context.Background(), "../outside", strings.NewReader("")), ErrInvalidKey)
+
+	_, err := store.Get(context.Background(), "/etc/passwd")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestLocalStorageDelete(t *testing.T) {
+	store := NewLocalStorage(config.StorageConfiguration{Directory: t.TempDir()})
+	ctx := context.Background()
+
+	for _, key := range []string{"videos/123.mp4", "hls/123/master.m3u8", "hls/123/720p/segment0.ts", "hls/456/master.m3u8"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("data")))
+	}
+
+	require.NoError(t, store.Delete(ctx, "videos/123.mp4"))
+	require.NoError(t, store.Delete(ctx, "videos/123.mp4"))
+	require.NoError(t, store.DeletePrefix(ctx, "hls/123"))
+
+	for _, key := range []string{"videos/123.mp4", "hls/123/master.m3u8", "hls/123/720p/segment0.ts"} {
+		_, err := store.Get(ctx, key)
+		require.ErrorIs(t, err, ErrObjectNotFound, key)
+	}
+
+	r, err := store.Get(ctx, "hls/456/master.m3u8")
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	require.ErrorIs(t, store.DeletePrefix(ctx, ".."), ErrInvalidKey)
+	require.ErrorIs(t, store.DeletePrefix(ctx, "."), ErrInvalidKey)
+}
@@ -0,0 +1,58 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	passwordScheme = "pbkdf2-sha256"
+	// passwordIterations follows OWASP's recommendation for PBKDF2-HMAC-SHA256
+	passwordIterations = 600_000
+	passwordSaltSize   = 16
+	passwordKeySize    = 32
+)
+
+// HashPassword derives a salted hash of the password in the form
+// pbkdf2-sha256$<iterations>$<salt>$<key>
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2.Key([]byte(password), salt, passwordIterations, passwordKeySize, sha256.New)
+
+	return fmt.Sprintf("%s$%d$%s$%s", passwordScheme, passwordIterations, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// CheckPassword reports whether the password matches a hash made by HashPassword
+func CheckPassword(hash string, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+
+	salt, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	key, err := encoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(key, pbkdf2.Key([]byte(password), salt, iterations, len(key), sha256.New)) == 1
+}
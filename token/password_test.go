@@ -0,0 +1,21 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+
+	require.True(t, CheckPassword(hash, "correct horse"))
+	require.False(t, CheckPassword(hash, "wrong horse"))
+	require.False(t, CheckPassword("not a hash", "correct horse"))
+
+	// salted, so the same password hashes differently
+	other, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+}
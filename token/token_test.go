@@ -0,0 +1,47 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	signer := NewSigner("secret")
+
+	claims := Claims{Subject: "12345", Audience: "test", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	verified, err := signer.Verify(token, "test")
+	require.NoError(t, err)
+	require.Equal(t, claims, *verified)
+
+	// wrong audience
+	_, err = signer.Verify(token, "other")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// signed with a different secret
+	_, err = NewSigner("other-secret").Verify(token, "test")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// tampered payload
+	_, err = signer.Verify("e30"+token[3:], "test")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// malformed
+	_, err = signer.Verify("not-a-token", "test")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifyExpiredToken(t *testing.T) {
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(Claims{Subject: "12345", Audience: "test", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+
+	_, err = signer.Verify(token, "test")
+	require.ErrorIs(t, err, ErrExpiredToken)
+}